@@ -0,0 +1,78 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Result is the typed outcome of an agent handling an intent
+type Result interface {
+	// Type identifies the kind of result for API clients (e.g. "trip_plan")
+	Type() string
+	// Markdown renders the result for chat clients
+	Markdown() string
+}
+
+// Agent handles one or more intents detected by the IntentAgent
+type Agent interface {
+	// Name identifies the agent in logs and metadata
+	Name() string
+	// SupportedIntents lists the intents the agent can handle
+	SupportedIntents() []string
+	// Handle processes a detected intent
+	Handle(ctx context.Context, intent *IntentResult) (Result, error)
+}
+
+// Registry maps intents to the agents that handle them
+type Registry struct {
+	mu       sync.RWMutex
+	byIntent map[string]Agent
+}
+
+// NewRegistry creates an empty agent registry
+func NewRegistry() *Registry {
+	return &Registry{
+		byIntent: make(map[string]Agent),
+	}
+}
+
+// Register adds an agent for all of its supported intents.
+// An agent registered later replaces the previous handler of the same intent.
+func (r *Registry) Register(agent Agent) error {
+	if agent == nil {
+		return fmt.Errorf("agent is nil")
+	}
+	intents := agent.SupportedIntents()
+	if len(intents) == 0 {
+		return fmt.Errorf("agent %s supports no intents", agent.Name())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, intent := range intents {
+		r.byIntent[intent] = agent
+	}
+	return nil
+}
+
+// Lookup returns the agent registered for an intent
+func (r *Registry) Lookup(intent string) (Agent, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	agent, ok := r.byIntent[intent]
+	return agent, ok
+}
+
+// Intents lists every registered intent in alphabetical order
+func (r *Registry) Intents() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	intents := make([]string, 0, len(r.byIntent))
+	for intent := range r.byIntent {
+		intents = append(intents, intent)
+	}
+	sort.Strings(intents)
+	return intents
+}
@@ -0,0 +1,52 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type textResult string
+
+func (r textResult) Type() string     { return "text" }
+func (r textResult) Markdown() string { return string(r) }
+
+type stubAgent struct {
+	name    string
+	intents []string
+}
+
+func (a *stubAgent) Name() string               { return a.name }
+func (a *stubAgent) SupportedIntents() []string { return a.intents }
+func (a *stubAgent) Handle(ctx context.Context, intent *IntentResult) (Result, error) {
+	return textResult(a.name), nil
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	require.NoError(t, registry.Register(&stubAgent{name: "weather", intents: []string{"weather_check"}}))
+	require.NoError(t, registry.Register(&stubAgent{name: "travel", intents: []string{"plan_trip", "plan_update"}}))
+
+	agent, ok := registry.Lookup("plan_update")
+	require.True(t, ok)
+	assert.Equal(t, "travel", agent.Name())
+
+	_, ok = registry.Lookup("visa_check")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{"plan_trip", "plan_update", "weather_check"}, registry.Intents())
+
+	t.Run("Later registration replaces the handler", func(t *testing.T) {
+		require.NoError(t, registry.Register(&stubAgent{name: "fake-weather", intents: []string{"weather_check"}}))
+		agent, ok := registry.Lookup("weather_check")
+		require.True(t, ok)
+		assert.Equal(t, "fake-weather", agent.Name())
+	})
+
+	t.Run("Invalid agents are rejected", func(t *testing.T) {
+		assert.Error(t, registry.Register(nil))
+		assert.Error(t, registry.Register(&stubAgent{name: "empty"}))
+	})
+}
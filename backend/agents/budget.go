@@ -11,7 +11,8 @@ type BudgetPlan struct {
 	Misc      int `json:"misc"`
 }
 
-// EstimateBudget splits a total budget into travel expense categories
+// EstimateBudget splits a total budget into travel expense categories using fixed shares.
+// Use EstimateTripBudget when the destination is known.
 // Parameters:
 //   - total: Total budget amount in THB
 // Returns:
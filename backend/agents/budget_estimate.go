@@ -0,0 +1,343 @@
+package agents
+
+import (
+	"math"
+	"strings"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+)
+
+// Travel styles for budget estimates
+const (
+	StyleBackpacker = "backpacker"
+	StyleMid        = "mid"
+	StyleLuxury     = "luxury"
+)
+
+// Budget categories, in the order they are shown
+const (
+	BudgetFlights       = "flights"
+	BudgetAccommodation = "accommodation"
+	BudgetFood          = "food"
+	BudgetTransport     = "transport"
+	BudgetActivities    = "activities"
+	BudgetMisc          = "misc"
+)
+
+// Budget verdicts
+const (
+	// VerdictSuggested means no budget was given and the total is the estimated cost
+	VerdictSuggested = "suggested"
+	// VerdictComfortable means the budget covers the estimate with at least 25% to spare
+	VerdictComfortable = "comfortable"
+	VerdictFeasible    = "feasible"
+	VerdictTooLow      = "too_low"
+)
+
+// domesticRoadKm is the distance below which a trip is assumed to go by road instead of by air
+const domesticRoadKm = 300
+
+// miscShare is the contingency for SIM cards, insurance, tips and the unexpected
+const miscShare = 0.10
+
+// dailyCosts is the typical mid-range spend per person per day in THB
+type dailyCosts struct {
+	Food       float64
+	Transport  float64
+	Activities float64
+}
+
+// costOfLiving holds daily costs by ISO country code
+var costOfLiving = map[string]dailyCosts{
+	"TH": {Food: 600, Transport: 250, Activities: 400},
+	"JP": {Food: 1800, Transport: 700, Activities: 900},
+	"KR": {Food: 1500, Transport: 500, Activities: 800},
+	"SG": {Food: 1600, Transport: 500, Activities: 1000},
+	"HK": {Food: 1600, Transport: 450, Activities: 900},
+	"TW": {Food: 1100, Transport: 400, Activities: 600},
+	"MY": {Food: 700, Transport: 300, Activities: 500},
+	"ID": {Food: 600, Transport: 300, Activities: 500},
+	"VN": {Food: 500, Transport: 250, Activities: 400},
+	"PH": {Food: 600, Transport: 300, Activities: 500},
+	"CN": {Food: 1000, Transport: 400, Activities: 700},
+	"IN": {Food: 500, Transport: 250, Activities: 400},
+	"AE": {Food: 2000, Transport: 700, Activities: 1500},
+	"QA": {Food: 2000, Transport: 700, Activities: 1200},
+	"TR": {Food: 1000, Transport: 400, Activities: 700},
+	"GB": {Food: 2500, Transport: 1000, Activities: 1500},
+	"FR": {Food: 2400, Transport: 800, Activities: 1400},
+	"DE": {Food: 2200, Transport: 800, Activities: 1200},
+	"IT": {Food: 2200, Transport: 700, Activities: 1300},
+	"ES": {Food: 1900, Transport: 600, Activities: 1100},
+	"NL": {Food: 2400, Transport: 800, Activities: 1300},
+	"CH": {Food: 3500, Transport: 1400, Activities: 2000},
+	"US": {Food: 2600, Transport: 1000, Activities: 1600},
+	"CA": {Food: 2300, Transport: 800, Activities: 1300},
+	"AU": {Food: 2400, Transport: 800, Activities: 1400},
+	"NZ": {Food: 2200, Transport: 800, Activities: 1400},
+}
+
+// defaultDailyCosts is used for countries without cost-of-living data
+var defaultDailyCosts = dailyCosts{Food: 1500, Transport: 600, Activities: 900}
+
+// styleFactors scales mid-range costs for a travel style. Hotel prices are relative to
+// estimateHotelPricePerNight, which prices budget hotels.
+type styleFactors struct {
+	Flight     float64
+	Hotel      float64
+	Food       float64
+	Transport  float64
+	Activities float64
+}
+
+var travelStyles = map[string]styleFactors{
+	StyleBackpacker: {Flight: 0.85, Hotel: 0.5, Food: 0.5, Transport: 0.6, Activities: 0.5},
+	StyleMid:        {Flight: 1, Hotel: 1.6, Food: 1, Transport: 1, Activities: 1},
+	StyleLuxury:     {Flight: 2.5, Hotel: 4.5, Food: 2.5, Transport: 2.5, Activities: 2},
+}
+
+// BudgetRequest describes the trip to budget. Total is the traveler's budget in THB; without one
+// the estimate suggests a budget.
+type BudgetRequest struct {
+	Destination string  `json:"destination"`
+	Origin      string  `json:"origin"`
+	Days        int     `json:"days"`
+	Travelers   int     `json:"travelers"`
+	Style       string  `json:"style"`
+	Total       float64 `json:"total,omitempty"`
+}
+
+// WithDefaults fills in a 7-day mid-range trip for one traveler from Bangkok
+func (r BudgetRequest) WithDefaults() BudgetRequest {
+	if r.Origin == "" {
+		r.Origin = "Bangkok"
+	}
+	if r.Days <= 0 {
+		r.Days = 7
+	}
+	if r.Travelers <= 0 {
+		r.Travelers = 1
+	}
+	r.Style = NormalizeTravelStyle(r.Style)
+	return r
+}
+
+// BudgetAllocation is the part of the budget set aside for one category
+type BudgetAllocation struct {
+	Category string  `json:"category"`
+	Amount   float64 `json:"amount"`
+	PerDay   float64 `json:"per_day"`
+	// Share is the percentage of the total
+	Share float64 `json:"share"`
+	// Estimate is what the category typically costs for this trip and style
+	Estimate float64 `json:"estimate"`
+}
+
+// BudgetEstimate splits a trip budget into categories based on where and how the trip is taken
+type BudgetEstimate struct {
+	Destination     string             `json:"destination"`
+	Country         string             `json:"country,omitempty"`
+	Origin          string             `json:"origin"`
+	Days            int                `json:"days"`
+	Travelers       int                `json:"travelers"`
+	Style           string             `json:"style"`
+	Total           float64            `json:"total"`
+	Required        float64            `json:"required"`
+	PerDay          float64            `json:"per_day"`
+	PerPersonPerDay float64            `json:"per_person_per_day"`
+	Allocations     []BudgetAllocation `json:"allocations"`
+	Verdict         string             `json:"verdict"`
+	Shortfall       float64            `json:"shortfall,omitempty"`
+	// FitsStyle is the most comfortable cheaper style the budget covers when it is too low
+	FitsStyle string `json:"fits_style,omitempty"`
+}
+
+// Feasible reports whether the budget covers the estimated cost
+func (e BudgetEstimate) Feasible() bool {
+	return e.Verdict != VerdictTooLow
+}
+
+// Allocation returns the allocation for a category
+func (e BudgetEstimate) Allocation(category string) (BudgetAllocation, bool) {
+	for _, allocation := range e.Allocations {
+		if allocation.Category == category {
+			return allocation, true
+		}
+	}
+	return BudgetAllocation{}, false
+}
+
+// BudgetPlan summarizes the allocations in the categories of EstimateBudget; activities count as misc
+func (e BudgetEstimate) BudgetPlan() BudgetPlan {
+	amount := func(category string) int {
+		allocation, _ := e.Allocation(category)
+		return int(allocation.Amount)
+	}
+	return BudgetPlan{
+		Flight:    amount(BudgetFlights),
+		Hotel:     amount(BudgetAccommodation),
+		Food:      amount(BudgetFood),
+		Transport: amount(BudgetTransport),
+		Misc:      amount(BudgetActivities) + amount(BudgetMisc),
+	}
+}
+
+// NormalizeTravelStyle maps free text such as "budget" or "5-star" to a travel style (default mid)
+func NormalizeTravelStyle(style string) string {
+	lower := strings.ToLower(strings.TrimSpace(style))
+	switch {
+	case lower == "":
+		return StyleMid
+	case strings.Contains(lower, "backpack") || strings.Contains(lower, "cheap") || strings.Contains(lower, "budget") ||
+		strings.Contains(lower, "hostel") || strings.Contains(lower, "แบ็คแพ็ค") || strings.Contains(lower, "ประหยัด"):
+		return StyleBackpacker
+	case strings.Contains(lower, "lux") || strings.Contains(lower, "premium") || strings.Contains(lower, "5-star") ||
+		strings.Contains(lower, "five star") || strings.Contains(lower, "หรู"):
+		return StyleLuxury
+	}
+	return StyleMid
+}
+
+// EstimateTripBudget estimates what a trip costs from the route's flight price, hotel prices at the
+// destination and its cost of living, then splits the budget in the same proportions. Without a
+// budget the estimated cost is suggested.
+func EstimateTripBudget(req BudgetRequest) BudgetEstimate {
+	req = req.WithDefaults()
+	costs := estimateTripCosts(req, req.Style)
+
+	estimate := BudgetEstimate{
+		Destination: req.Destination,
+		Origin:      req.Origin,
+		Days:        req.Days,
+		Travelers:   req.Travelers,
+		Style:       req.Style,
+		Total:       req.Total,
+		Required:    sumCosts(costs),
+	}
+	if location, ok := airports.Resolve(req.Destination); ok {
+		estimate.Country = location.CountryCode
+	}
+
+	switch {
+	case req.Total <= 0:
+		estimate.Total = estimate.Required
+		estimate.Verdict = VerdictSuggested
+	case req.Total >= estimate.Required*1.25:
+		estimate.Verdict = VerdictComfortable
+	case req.Total >= estimate.Required:
+		estimate.Verdict = VerdictFeasible
+	default:
+		estimate.Verdict = VerdictTooLow
+		estimate.Shortfall = estimate.Required - req.Total
+		estimate.FitsStyle = cheaperStyleWithin(req)
+	}
+
+	estimate.Allocations = allocate(costs, estimate.Required, estimate.Total, req.Days)
+	estimate.PerDay = math.Round(estimate.Total / float64(req.Days))
+	estimate.PerPersonPerDay = math.Round(estimate.Total / float64(req.Days*req.Travelers))
+	return estimate
+}
+
+// categoryCost is the estimated cost of one budget category
+type categoryCost struct {
+	category string
+	amount   float64
+}
+
+// estimateTripCosts prices each category for a style, in THB for the whole group
+func estimateTripCosts(req BudgetRequest, style string) []categoryCost {
+	factors := travelStyles[style]
+	travelers := float64(req.Travelers)
+	days := float64(req.Days)
+
+	city, country := req.Destination, ""
+	if location, ok := airports.Resolve(req.Destination); ok {
+		city, country = location.City, location.CountryCode
+	}
+	daily, ok := costOfLiving[country]
+	if !ok {
+		daily = defaultDailyCosts
+	}
+
+	// Fares are economy return fares; short domestic trips go by road and are part of transport
+	flights := 0.0
+	if distance, ok := airports.DistanceKm(req.Origin, req.Destination); !ok || distance >= domesticRoadKm {
+		from, to := airports.AirportCode(req.Origin), airports.AirportCode(req.Destination)
+		if from == "" {
+			from = req.Origin
+		}
+		if to == "" {
+			to = req.Destination
+		}
+		flights = float64(estimateFlightPrice(from, to)) * factors.Flight * travelers
+	}
+
+	nights := req.Days - 1
+	if nights < 1 {
+		nights = 1
+	}
+	rooms := (req.Travelers + 1) / 2
+	accommodation := float64(estimateHotelPricePerNight(city)) * factors.Hotel * float64(nights*rooms)
+
+	costs := []categoryCost{
+		{BudgetFlights, math.Round(flights)},
+		{BudgetAccommodation, math.Round(accommodation)},
+		{BudgetFood, math.Round(daily.Food * factors.Food * travelers * days)},
+		{BudgetTransport, math.Round(daily.Transport * factors.Transport * travelers * days)},
+		{BudgetActivities, math.Round(daily.Activities * factors.Activities * travelers * days)},
+	}
+	return append(costs, categoryCost{BudgetMisc, math.Round(sumCosts(costs) * miscShare)})
+}
+
+// sumCosts adds up category costs
+func sumCosts(costs []categoryCost) float64 {
+	total := 0.0
+	for _, cost := range costs {
+		total += cost.amount
+	}
+	return total
+}
+
+// allocate splits total in proportion to the estimated costs; rounding is absorbed by misc
+func allocate(costs []categoryCost, required, total float64, days int) []BudgetAllocation {
+	allocations := make([]BudgetAllocation, 0, len(costs))
+	allocated := 0.0
+	for i, cost := range costs {
+		amount := 0.0
+		if required > 0 {
+			amount = math.Round(total * cost.amount / required)
+		}
+		if i == len(costs)-1 {
+			amount = total - allocated
+		}
+		allocated += amount
+
+		share := 0.0
+		if total > 0 {
+			share = math.Round(amount/total*1000) / 10
+		}
+		allocations = append(allocations, BudgetAllocation{
+			Category: cost.category,
+			Amount:   amount,
+			PerDay:   math.Round(amount / float64(days)),
+			Share:    share,
+			Estimate: cost.amount,
+		})
+	}
+	return allocations
+}
+
+// cheaperStyleWithin returns the most comfortable style cheaper than the requested one that the
+// budget covers, or "" when none does
+func cheaperStyleWithin(req BudgetRequest) string {
+	cheaper := map[string][]string{
+		StyleLuxury: {StyleMid, StyleBackpacker},
+		StyleMid:    {StyleBackpacker},
+	}
+	for _, style := range cheaper[req.Style] {
+		if sumCosts(estimateTripCosts(req, style)) <= req.Total {
+			return style
+		}
+	}
+	return ""
+}
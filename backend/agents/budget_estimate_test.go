@@ -0,0 +1,105 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTripBudget_SuggestsBudget(t *testing.T) {
+	estimate := EstimateTripBudget(BudgetRequest{Destination: "Tokyo", Days: 5})
+
+	assert.Equal(t, "JP", estimate.Country)
+	assert.Equal(t, "Bangkok", estimate.Origin)
+	assert.Equal(t, StyleMid, estimate.Style)
+	assert.Equal(t, VerdictSuggested, estimate.Verdict)
+	assert.Equal(t, 49280.0, estimate.Required)
+	assert.Equal(t, estimate.Required, estimate.Total)
+	assert.Equal(t, 9856.0, estimate.PerDay)
+
+	flights, ok := estimate.Allocation(BudgetFlights)
+	require.True(t, ok)
+	assert.Equal(t, 15000.0, flights.Amount)
+
+	accommodation, _ := estimate.Allocation(BudgetAccommodation)
+	assert.Equal(t, 12800.0, accommodation.Amount, "4 nights at 1.6x the budget hotel price")
+	assert.Equal(t, 2560.0, accommodation.PerDay)
+
+	food, _ := estimate.Allocation(BudgetFood)
+	assert.Equal(t, 9000.0, food.Amount)
+	assert.Equal(t, 18.3, food.Share)
+}
+
+func TestEstimateTripBudget_AllocationsAddUpToBudget(t *testing.T) {
+	estimate := EstimateTripBudget(BudgetRequest{Destination: "Paris", Days: 10, Travelers: 3, Style: "luxury", Total: 1234567})
+
+	total := 0.0
+	for _, allocation := range estimate.Allocations {
+		total += allocation.Amount
+	}
+	assert.Equal(t, 1234567.0, total)
+	assert.Len(t, estimate.Allocations, 6)
+	assert.Equal(t, BudgetMisc, estimate.Allocations[5].Category)
+}
+
+func TestEstimateTripBudget_DependsOnDestinationAndStyle(t *testing.T) {
+	seoul := EstimateTripBudget(BudgetRequest{Destination: "Seoul", Days: 7})
+	zurich := EstimateTripBudget(BudgetRequest{Destination: "Zurich", Days: 7})
+	assert.Greater(t, zurich.Required, seoul.Required)
+
+	backpacker := EstimateTripBudget(BudgetRequest{Destination: "Seoul", Days: 7, Style: "backpacking"})
+	luxury := EstimateTripBudget(BudgetRequest{Destination: "Seoul", Days: 7, Style: "luxury"})
+	assert.Less(t, backpacker.Required, seoul.Required)
+	assert.Greater(t, luxury.Required, seoul.Required)
+
+	// Travelers share rooms, so a couple costs less than twice a solo traveler
+	couple := EstimateTripBudget(BudgetRequest{Destination: "Seoul", Days: 7, Travelers: 2})
+	assert.Less(t, couple.Required, 2*seoul.Required)
+	assert.InDelta(t, couple.PerDay/2, couple.PerPersonPerDay, 1)
+}
+
+func TestEstimateTripBudget_NoFlightsWithoutLeavingTheCity(t *testing.T) {
+	estimate := EstimateTripBudget(BudgetRequest{Destination: "Bangkok", Days: 3})
+
+	flights, _ := estimate.Allocation(BudgetFlights)
+	assert.Zero(t, flights.Amount)
+	assert.Zero(t, flights.Share)
+}
+
+func TestEstimateTripBudget_Verdicts(t *testing.T) {
+	required := EstimateTripBudget(BudgetRequest{Destination: "Tokyo", Days: 5}).Required
+
+	tests := []struct {
+		name      string
+		total     float64
+		verdict   string
+		shortfall float64
+		fitsStyle string
+	}{
+		{"Plenty of room", required * 1.5, VerdictComfortable, 0, ""},
+		{"Just enough", required, VerdictFeasible, 0, ""},
+		{"Enough for backpacking", 30000, VerdictTooLow, required - 30000, StyleBackpacker},
+		{"Too low for any style", 5000, VerdictTooLow, required - 5000, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			estimate := EstimateTripBudget(BudgetRequest{Destination: "Tokyo", Days: 5, Total: tt.total})
+			assert.Equal(t, tt.verdict, estimate.Verdict)
+			assert.Equal(t, tt.shortfall, estimate.Shortfall)
+			assert.Equal(t, tt.fitsStyle, estimate.FitsStyle)
+			assert.Equal(t, tt.verdict != VerdictTooLow, estimate.Feasible())
+			assert.Equal(t, tt.total, estimate.Total)
+		})
+	}
+}
+
+func TestNormalizeTravelStyle(t *testing.T) {
+	assert.Equal(t, StyleMid, NormalizeTravelStyle(""))
+	assert.Equal(t, StyleMid, NormalizeTravelStyle("mid-range"))
+	assert.Equal(t, StyleBackpacker, NormalizeTravelStyle("Backpacking"))
+	assert.Equal(t, StyleBackpacker, NormalizeTravelStyle("ประหยัด"))
+	assert.Equal(t, StyleLuxury, NormalizeTravelStyle("5-star"))
+	assert.Equal(t, StyleLuxury, NormalizeTravelStyle("หรูหรา"))
+}
@@ -0,0 +1,261 @@
+package agents
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+)
+
+// Expense categories, matching the fields of BudgetPlan
+const (
+	ExpenseFlight    = "flight"
+	ExpenseHotel     = "hotel"
+	ExpenseFood      = "food"
+	ExpenseTransport = "transport"
+	ExpenseMisc      = "misc"
+)
+
+// ExpenseCategories lists the expense categories in the order they are reported
+var ExpenseCategories = []string{ExpenseFlight, ExpenseHotel, ExpenseFood, ExpenseTransport, ExpenseMisc}
+
+// Budget alert levels
+const (
+	AlertWarning = "warning"
+	AlertOver    = "over_budget"
+)
+
+// budgetWarningShare is the share of a category's budget at which a warning is raised
+const budgetWarningShare = 0.8
+
+// Expense is money spent on a trip. Amount is in Currency; AmountTHB is the same amount in baht,
+// converted when the expense was recorded.
+type Expense struct {
+	ID        int64     `json:"id"`
+	TripID    int64     `json:"trip_id"`
+	Category  string    `json:"category"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	AmountTHB float64   `json:"amount_thb"`
+	Date      time.Time `json:"date"`
+	// Day is the day of the itinerary the expense belongs to, 0 when it is not tied to one
+	Day         int    `json:"day,omitempty"`
+	Description string `json:"description,omitempty"`
+	// PaidBy is the member who paid for the group; expenses without a payer are not shared
+	PaidBy    int64         `json:"paid_by,omitempty"`
+	Split     *ExpenseSplit `json:"split,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TripBudget is the budget expenses of a trip are tracked against, in THB
+type TripBudget struct {
+	TripID    int64      `json:"trip_id"`
+	Total     float64    `json:"total"`
+	Plan      BudgetPlan `json:"breakdown"`
+	Days      int        `json:"days,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// CategoryBudget returns the budget of an expense category
+func (b TripBudget) CategoryBudget(category string) float64 {
+	switch category {
+	case ExpenseFlight:
+		return float64(b.Plan.Flight)
+	case ExpenseHotel:
+		return float64(b.Plan.Hotel)
+	case ExpenseFood:
+		return float64(b.Plan.Food)
+	case ExpenseTransport:
+		return float64(b.Plan.Transport)
+	case ExpenseMisc:
+		return float64(b.Plan.Misc)
+	}
+	return 0
+}
+
+// IsExpenseCategory reports whether category is one of ExpenseCategories
+func IsExpenseCategory(category string) bool {
+	for _, known := range ExpenseCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+// CategorySpending compares spending in a category with its budget
+type CategorySpending struct {
+	Category  string  `json:"category"`
+	Budget    float64 `json:"budget"`
+	Spent     float64 `json:"spent"`
+	Remaining float64 `json:"remaining"`
+	// Percent is the share of the budget spent
+	Percent    float64 `json:"percent"`
+	OverBudget bool    `json:"over_budget"`
+}
+
+// DailySpending is the spending on one date with the running total up to that date
+type DailySpending struct {
+	Date       string  `json:"date"`
+	Spent      float64 `json:"spent"`
+	Cumulative float64 `json:"cumulative"`
+}
+
+// BudgetAlert warns about a category, or the whole budget ("total"), that is nearly or fully spent
+type BudgetAlert struct {
+	Category string  `json:"category"`
+	Level    string  `json:"level"`
+	Message  string  `json:"message"`
+	Amount   float64 `json:"amount"`
+}
+
+// ExpenseSummary is the state of a trip's spending against its budget, in THB
+type ExpenseSummary struct {
+	TripID     int64              `json:"trip_id"`
+	Budget     *TripBudget        `json:"budget,omitempty"`
+	Spent      float64            `json:"spent"`
+	Remaining  float64            `json:"remaining"`
+	Categories []CategorySpending `json:"categories"`
+	Daily      []DailySpending    `json:"daily"`
+	// BurnRate is the average spend per day on the trip. Flights are usually paid before
+	// leaving, so they are left out.
+	BurnRate    float64 `json:"daily_burn_rate"`
+	DaysTracked int     `json:"days_tracked"`
+	// ProjectedTotal is what the trip will cost at the current burn rate, when its length is known
+	ProjectedTotal float64       `json:"projected_total,omitempty"`
+	Alerts         []BudgetAlert `json:"alerts"`
+	Currency       string        `json:"currency"`
+}
+
+// SummarizeExpenses totals a trip's expenses per category and per day and checks them against the
+// budget. Without a budget only the totals and burn rate are reported.
+func SummarizeExpenses(tripID int64, budget *TripBudget, expenses []Expense) ExpenseSummary {
+	summary := ExpenseSummary{
+		TripID:   tripID,
+		Budget:   budget,
+		Daily:    []DailySpending{},
+		Alerts:   []BudgetAlert{},
+		Currency: currency.Base,
+	}
+
+	spentByCategory := make(map[string]float64)
+	spentByDate := make(map[string]float64)
+	flights := 0.0
+	var first, last time.Time
+	for _, expense := range expenses {
+		spentByCategory[expense.Category] += expense.AmountTHB
+		spentByDate[expense.Date.Format("2006-01-02")] += expense.AmountTHB
+		summary.Spent += expense.AmountTHB
+		if expense.Category == ExpenseFlight {
+			flights += expense.AmountTHB
+		}
+		if first.IsZero() || expense.Date.Before(first) {
+			first = expense.Date
+		}
+		if expense.Date.After(last) {
+			last = expense.Date
+		}
+	}
+	summary.Spent = roundAmount(summary.Spent)
+
+	for _, category := range ExpenseCategories {
+		spending := CategorySpending{Category: category, Spent: roundAmount(spentByCategory[category])}
+		if budget != nil {
+			spending.Budget = budget.CategoryBudget(category)
+			spending.Remaining = roundAmount(spending.Budget - spending.Spent)
+			spending.OverBudget = spending.Spent > spending.Budget
+			if spending.Budget > 0 {
+				spending.Percent = math.Round(spending.Spent/spending.Budget*1000) / 10
+			}
+			if alert, ok := budgetAlert(category, spending.Budget, spending.Spent); ok {
+				summary.Alerts = append(summary.Alerts, alert)
+			}
+		}
+		summary.Categories = append(summary.Categories, spending)
+	}
+
+	dates := make([]string, 0, len(spentByDate))
+	for date := range spentByDate {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	cumulative := 0.0
+	for _, date := range dates {
+		cumulative += spentByDate[date]
+		summary.Daily = append(summary.Daily, DailySpending{
+			Date:       date,
+			Spent:      roundAmount(spentByDate[date]),
+			Cumulative: roundAmount(cumulative),
+		})
+	}
+
+	if len(expenses) > 0 {
+		summary.DaysTracked = int(last.Sub(first).Hours()/24) + 1
+		summary.BurnRate = roundAmount((summary.Spent - flights) / float64(summary.DaysTracked))
+	}
+
+	if budget != nil {
+		summary.Remaining = roundAmount(budget.Total - summary.Spent)
+		if alert, ok := budgetAlert("total", budget.Total, summary.Spent); ok {
+			summary.Alerts = append(summary.Alerts, alert)
+		}
+		if budget.Days > 0 && summary.DaysTracked > 0 {
+			summary.ProjectedTotal = roundAmount(flights + summary.BurnRate*float64(budget.Days))
+			if summary.ProjectedTotal > budget.Total && summary.Spent <= budget.Total {
+				summary.Alerts = append(summary.Alerts, BudgetAlert{
+					Category: "total",
+					Level:    AlertWarning,
+					Message: fmt.Sprintf("At %s a day the trip will cost %s, %s over budget",
+						currency.Format(summary.BurnRate, currency.Base), currency.Format(summary.ProjectedTotal, currency.Base),
+						currency.Format(summary.ProjectedTotal-budget.Total, currency.Base)),
+					Amount: roundAmount(summary.ProjectedTotal - budget.Total),
+				})
+			}
+		}
+	}
+
+	return summary
+}
+
+// budgetAlert checks spending against a budget: over budget, or a warning from 80% spent
+func budgetAlert(category string, budget, spent float64) (BudgetAlert, bool) {
+	switch {
+	case spent > budget:
+		return BudgetAlert{
+			Category: category,
+			Level:    AlertOver,
+			Message:  fmt.Sprintf("%s is over budget by %s", alertName(category), currency.Format(spent-budget, currency.Base)),
+			Amount:   roundAmount(spent - budget),
+		}, true
+	case budget > 0 && spent >= budget*budgetWarningShare:
+		return BudgetAlert{
+			Category: category,
+			Level:    AlertWarning,
+			Message: fmt.Sprintf("%s has %.0f%% of its budget spent, %s left", alertName(category),
+				math.Floor(spent/budget*100), currency.Format(budget-spent, currency.Base)),
+			Amount: roundAmount(budget - spent),
+		}, true
+	}
+	return BudgetAlert{}, false
+}
+
+// alertName is how a category is named in alert messages
+func alertName(category string) string {
+	names := map[string]string{
+		ExpenseFlight:    "Flight spending",
+		ExpenseHotel:     "Hotel spending",
+		ExpenseFood:      "Food spending",
+		ExpenseTransport: "Transport spending",
+		ExpenseMisc:      "Other spending",
+		"total":          "The trip",
+	}
+	return names[category]
+}
+
+// roundAmount rounds to satang so sums of converted amounts stay readable
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,75 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExpense(category string, amount float64, day string) Expense {
+	return Expense{TripID: 1, Category: category, Amount: amount, Currency: "THB", AmountTHB: amount, Date: date(day)}
+}
+
+func TestSummarizeExpenses(t *testing.T) {
+	budget := &TripBudget{TripID: 1, Total: 10000, Plan: EstimateBudget(10000), Days: 5}
+	expenses := []Expense{
+		newExpense(ExpenseFlight, 4500, "2025-05-01"),
+		newExpense(ExpenseHotel, 1200, "2025-05-01"),
+		newExpense(ExpenseFood, 800, "2025-05-01"),
+		newExpense(ExpenseFood, 500, "2025-05-02"),
+		newExpense(ExpenseTransport, 1100, "2025-05-02"),
+	}
+
+	summary := SummarizeExpenses(1, budget, expenses)
+
+	assert.Equal(t, 8100.0, summary.Spent)
+	assert.Equal(t, 1900.0, summary.Remaining)
+	assert.Equal(t, "THB", summary.Currency)
+
+	require.Len(t, summary.Categories, 5)
+	food := summary.Categories[2]
+	assert.Equal(t, CategorySpending{Category: ExpenseFood, Budget: 1500, Spent: 1300, Remaining: 200, Percent: 86.7}, food)
+	transport := summary.Categories[3]
+	assert.True(t, transport.OverBudget)
+	assert.Equal(t, -100.0, transport.Remaining)
+
+	assert.Equal(t, []DailySpending{
+		{Date: "2025-05-01", Spent: 6500, Cumulative: 6500},
+		{Date: "2025-05-02", Spent: 1600, Cumulative: 8100},
+	}, summary.Daily)
+
+	// Flights are left out of the burn rate
+	assert.Equal(t, 2, summary.DaysTracked)
+	assert.Equal(t, 1800.0, summary.BurnRate)
+	assert.Equal(t, 13500.0, summary.ProjectedTotal)
+
+	levels := map[string]string{}
+	for _, alert := range summary.Alerts {
+		levels[alert.Category] = alert.Level
+	}
+	assert.Equal(t, AlertWarning, levels[ExpenseFood])
+	assert.Equal(t, AlertOver, levels[ExpenseTransport])
+	assert.NotContains(t, levels, ExpenseHotel)
+	assert.Contains(t, summary.Alerts, BudgetAlert{
+		Category: ExpenseTransport, Level: AlertOver, Message: "Transport spending is over budget by 100 THB", Amount: 100,
+	})
+	assert.Contains(t, summary.Alerts, BudgetAlert{
+		Category: "total", Level: AlertWarning, Message: "At 1800 THB a day the trip will cost 13500 THB, 3500 THB over budget", Amount: 3500,
+	})
+}
+
+func TestSummarizeExpenses_WithoutBudget(t *testing.T) {
+	summary := SummarizeExpenses(1, nil, []Expense{newExpense(ExpenseFood, 300, "2025-05-01"), newExpense(ExpenseFood, 600, "2025-05-03")})
+
+	assert.Equal(t, 900.0, summary.Spent)
+	assert.Equal(t, 3, summary.DaysTracked)
+	assert.Equal(t, 300.0, summary.BurnRate)
+	assert.Empty(t, summary.Alerts)
+	assert.Zero(t, summary.ProjectedTotal)
+
+	empty := SummarizeExpenses(2, nil, nil)
+	assert.Zero(t, empty.BurnRate)
+	assert.NotNil(t, empty.Daily)
+	assert.Len(t, empty.Categories, 5)
+}
@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
 )
 
 // FlightStatus represents complete flight status information
@@ -29,6 +33,7 @@ type FlightStatus struct {
 type FlightAgent struct {
 	client *openai.Client
 	apiKey string
+	source FlightSource
 }
 
 // NewFlightAgent creates a new flight agent
@@ -216,8 +221,8 @@ Generate a polite, brief notification message for the passenger. Include:
 
 // GetCheapestFlight searches for the cheapest flight between two cities (Legacy function)
 // Parameters:
-//   - from: Origin airport/city code (e.g., "BKK" for Bangkok)
-//   - to: Destination airport/city code (e.g., "YVR" for Vancouver)
+//   - from: Origin airport code or city/country name (e.g., "BKK" or "Bangkok")
+//   - to: Destination airport code or city/country name (e.g., "YVR" or "แคนาดา")
 //   - date: Departure date in YYYY-MM-DD format
 // Returns:
 //   - price: Lowest flight price in THB
@@ -247,6 +252,9 @@ func GetCheapestFlight(from, to, date string) (price int, airline string) {
 		return defaultPrice, defaultAirline
 	}
 
+	// Accept city and country names as well as airport codes
+	from, to = airportCode(from), airportCode(to)
+
 	// Try Skyscanner RapidAPI first
 	price, airline = searchSkyscanner(from, to, date, apiKey)
 	if price > 0 && airline != "" {
@@ -259,20 +267,121 @@ func GetCheapestFlight(from, to, date string) (price int, airline string) {
 	return estimateFlightPrice(from, to), estimateAirline(from, to)
 }
 
+// skyscannerBaseURL is the Sky Scrapper RapidAPI flights endpoint
+var skyscannerBaseURL = "https://sky-scrapper.p.rapidapi.com/api/v1/flights"
+
+// skyscannerPlace identifies an airport to the Sky Scrapper API, which needs both its sky ID and entity ID
+type skyscannerPlace struct {
+	SkyID    string
+	EntityID string
+}
+
+// skyscannerPlaces caches looked-up places by IATA code
+var skyscannerPlaces sync.Map
+
 // searchSkyscanner queries Skyscanner RapidAPI
 func searchSkyscanner(from, to, date, apiKey string) (price int, airline string) {
-	// Skyscanner RapidAPI endpoint
-	url := fmt.Sprintf(
-		"https://sky-scrapper.p.rapidapi.com/api/v1/flights/searchFlights?originSkyId=%s&destinationSkyId=%s&originEntityId=%s&destinationEntityId=%s&date=%s&adults=1&currency=THB&market=TH&locale=en-US",
-		from, to, from, to, date,
-	)
+	origin, err := lookupSkyscannerPlace(from, apiKey)
+	if err != nil {
+		log.Printf("Failed to look up Skyscanner origin %s: %v", from, err)
+		return 0, ""
+	}
+	destination, err := lookupSkyscannerPlace(to, apiKey)
+	if err != nil {
+		log.Printf("Failed to look up Skyscanner destination %s: %v", to, err)
+		return 0, ""
+	}
+
+	params := url.Values{}
+	params.Add("originSkyId", origin.SkyID)
+	params.Add("destinationSkyId", destination.SkyID)
+	params.Add("originEntityId", origin.EntityID)
+	params.Add("destinationEntityId", destination.EntityID)
+	params.Add("date", date)
+	params.Add("adults", "1")
+	params.Add("currency", "THB")
+	params.Add("market", "TH")
+	params.Add("locale", "en-US")
 
-	req, err := http.NewRequest("GET", url, nil)
+	result, err := skyscannerGet("searchFlights", params, apiKey)
 	if err != nil {
-		log.Printf("Failed to create Skyscanner request: %v", err)
+		log.Printf("Skyscanner flight search failed: %v", err)
 		return 0, ""
 	}
 
+	// Extract cheapest flight
+	price, airline = parseSkyscannerResponse(result)
+
+	return price, airline
+}
+
+// lookupSkyscannerPlace finds the sky ID and entity ID of an airport code with the searchAirport endpoint
+func lookupSkyscannerPlace(code, apiKey string) (skyscannerPlace, error) {
+	code = strings.ToUpper(code)
+	if cached, ok := skyscannerPlaces.Load(code); ok {
+		return cached.(skyscannerPlace), nil
+	}
+
+	params := url.Values{}
+	params.Add("query", code)
+	params.Add("locale", "en-US")
+
+	result, err := skyscannerGet("searchAirport", params, apiKey)
+	if err != nil {
+		return skyscannerPlace{}, err
+	}
+
+	items, _ := result["data"].([]interface{})
+	var found *skyscannerPlace
+	for _, item := range items {
+		place, ok := parseSkyscannerPlace(item)
+		if !ok {
+			continue
+		}
+		if place.SkyID == code {
+			found = &place
+			break
+		}
+		if found == nil {
+			found = &place
+		}
+	}
+	if found == nil {
+		return skyscannerPlace{}, fmt.Errorf("no Skyscanner place for %s", code)
+	}
+
+	skyscannerPlaces.Store(code, *found)
+	return *found, nil
+}
+
+// parseSkyscannerPlace reads skyId and entityId from a searchAirport result, which carries them
+// either at the top level or under navigation.relevantFlightParams
+func parseSkyscannerPlace(item interface{}) (skyscannerPlace, bool) {
+	fields, ok := item.(map[string]interface{})
+	if !ok {
+		return skyscannerPlace{}, false
+	}
+	if navigation, ok := fields["navigation"].(map[string]interface{}); ok {
+		if params, ok := navigation["relevantFlightParams"].(map[string]interface{}); ok {
+			fields = params
+		}
+	}
+
+	skyID, _ := fields["skyId"].(string)
+	entityID, _ := fields["entityId"].(string)
+	if skyID == "" || entityID == "" {
+		return skyscannerPlace{}, false
+	}
+	return skyscannerPlace{SkyID: skyID, EntityID: entityID}, true
+}
+
+// skyscannerGet calls a Sky Scrapper endpoint and decodes the JSON response
+func skyscannerGet(endpoint string, params url.Values, apiKey string) (map[string]interface{}, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/%s?%s", skyscannerBaseURL, endpoint, params.Encode()), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
 	// Set headers
 	req.Header.Add("X-RapidAPI-Key", apiKey)
 	req.Header.Add("X-RapidAPI-Host", "sky-scrapper.p.rapidapi.com")
@@ -280,33 +389,24 @@ func searchSkyscanner(from, to, date, apiKey string) (price int, airline string)
 	client := &http.Client{Timeout: 15 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("Skyscanner API request failed: %v", err)
-		return 0, ""
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		log.Printf("Skyscanner API returned status %d", resp.StatusCode)
-		return 0, ""
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("Failed to read Skyscanner response: %v", err)
-		return 0, ""
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	// Parse response
 	var result map[string]interface{}
 	if err := json.Unmarshal(body, &result); err != nil {
-		log.Printf("Failed to parse Skyscanner response: %v", err)
-		return 0, ""
+		return nil, fmt.Errorf("failed to parse response: %w", err)
 	}
-
-	// Extract cheapest flight
-	price, airline = parseSkyscannerResponse(result)
-
-	return price, airline
+	return result, nil
 }
 
 // parseSkyscannerResponse extracts cheapest flight from Skyscanner response
@@ -414,7 +514,12 @@ func estimateFlightPrice(from, to string) int {
 		return price
 	}
 
-	// Default estimate based on distance (simplified)
+	// Estimate from the distance between known airports
+	if distance, ok := airports.DistanceKm(from, to); ok {
+		return int(math.Round((distance*2.8+2000)/100) * 100)
+	}
+
+	// Default estimate
 	return 38000
 }
 
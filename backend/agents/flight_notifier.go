@@ -0,0 +1,113 @@
+package agents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Notification channels for flight watches
+const (
+	ChannelWebhook = "webhook"
+	ChannelLine    = "line"
+	ChannelEmail   = "email"
+)
+
+// Notifier delivers a flight watch event to the subscriber
+type Notifier interface {
+	Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error
+}
+
+// ChannelNotifier sends each notification through the notifier registered for the watch's channel
+type ChannelNotifier map[string]Notifier
+
+// NewDefaultNotifier routes webhook, LINE and email notifications; LINE and email only log for now
+func NewDefaultNotifier() ChannelNotifier {
+	return ChannelNotifier{
+		ChannelWebhook: NewWebhookNotifier(),
+		ChannelLine:    &LineNotifier{},
+		ChannelEmail:   &EmailNotifier{},
+	}
+}
+
+// Notify implements Notifier
+func (n ChannelNotifier) Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error {
+	notifier, ok := n[watch.Channel]
+	if !ok {
+		return fmt.Errorf("unsupported notification channel %q", watch.Channel)
+	}
+	return notifier.Notify(ctx, watch, event)
+}
+
+// Supports reports whether a channel has a notifier
+func (n ChannelNotifier) Supports(channel string) bool {
+	_, ok := n[channel]
+	return ok
+}
+
+// WebhookNotifier POSTs the event as JSON to the watch's target URL
+type WebhookNotifier struct {
+	client *http.Client
+}
+
+// webhookPayload is the body sent to webhook subscribers
+type webhookPayload struct {
+	Watch FlightWatch      `json:"watch"`
+	Event FlightWatchEvent `json:"event"`
+}
+
+// NewWebhookNotifier creates a webhook notifier
+func NewWebhookNotifier() *WebhookNotifier {
+	return &WebhookNotifier{
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Notify implements Notifier
+func (n *WebhookNotifier) Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error {
+	body, err := json.Marshal(webhookPayload{Watch: watch, Event: event})
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, watch.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// LineNotifier will push LINE messages; until the LINE Messaging API is wired up it only logs
+type LineNotifier struct{}
+
+// Notify implements Notifier
+func (n *LineNotifier) Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error {
+	log.Printf("LineNotifier: (not sent) to %s: %s", watch.Target, event.Message)
+	return nil
+}
+
+// EmailNotifier will send emails; until an email provider is wired up it only logs
+type EmailNotifier struct{}
+
+// Notify implements Notifier
+func (n *EmailNotifier) Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error {
+	log.Printf("EmailNotifier: (not sent) to %s: %s", watch.Target, event.Message)
+	return nil
+}
@@ -0,0 +1,346 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// Flight sort orders
+const (
+	FlightSortBest     = "best"
+	FlightSortPrice    = "price"
+	FlightSortDuration = "duration"
+	FlightSortStops    = "stops"
+)
+
+// defaultFlightResults is how many options a search returns when no limit is set
+const defaultFlightResults = 5
+
+// FlightSearchRequest describes a one-way flight search
+type FlightSearchRequest struct {
+	Origin      string    `json:"origin"`
+	Destination string    `json:"destination"`
+	Date        time.Time `json:"date"`
+	Passengers  int       `json:"passengers"`
+	// MaxStops filters out options with more stops (0 means any); DirectOnly allows non-stop flights only
+	MaxStops   int    `json:"max_stops,omitempty"`
+	DirectOnly bool   `json:"direct_only,omitempty"`
+	SortBy     string `json:"sort_by,omitempty"`
+	Limit      int    `json:"limit,omitempty"`
+}
+
+// WithDefaults resolves airport codes and fills in tomorrow's date, one passenger and best-first sorting
+func (r FlightSearchRequest) WithDefaults() FlightSearchRequest {
+	r.Origin = airportCode(r.Origin)
+	r.Destination = airportCode(r.Destination)
+	if r.Date.IsZero() {
+		r.Date = time.Now().AddDate(0, 0, 1)
+	}
+	r.Date = time.Date(r.Date.Year(), r.Date.Month(), r.Date.Day(), 0, 0, 0, 0, time.UTC)
+	if r.Passengers <= 0 {
+		r.Passengers = 1
+	}
+	if r.SortBy == "" {
+		r.SortBy = FlightSortBest
+	}
+	if r.Limit <= 0 {
+		r.Limit = defaultFlightResults
+	}
+	return r
+}
+
+// FlightOption is one way to fly a route, with its rank among the search results
+type FlightOption struct {
+	Rank              int       `json:"rank"`
+	Airline           string    `json:"airline"`
+	FlightNumber      string    `json:"flight_number,omitempty"`
+	Origin            string    `json:"origin"`
+	Destination       string    `json:"destination"`
+	DepartTime        time.Time `json:"depart_time"`
+	ArriveTime        time.Time `json:"arrive_time"`
+	DurationMinutes   int       `json:"duration_minutes"`
+	Stops             int       `json:"stops"`
+	PricePerPassenger float64   `json:"price_per_passenger"`
+	TotalPrice        float64   `json:"total_price"`
+	Currency          string    `json:"currency"`
+	PriceEstimated    bool      `json:"price_estimated"`
+	Score             float64   `json:"score"`
+	Source            string    `json:"source"`
+}
+
+// Duration formats the flight time as "6h 5m"
+func (o FlightOption) Duration() string {
+	return fmt.Sprintf("%dh %dm", o.DurationMinutes/60, o.DurationMinutes%60)
+}
+
+// FlightSource looks up scheduled flights for a route, e.g. services.FlightService
+type FlightSource interface {
+	SearchFlights(origin, destination, date string) ([]models.FlightInfo, error)
+}
+
+// SetSource sets the flight schedule API used before falling back to estimated options
+func (a *FlightAgent) SetSource(source FlightSource) {
+	a.source = source
+}
+
+// SearchFlights returns ranked flight options for a route and date.
+// Scheduled flights come from the flight source when one is set; prices it does not provide are estimated.
+func (a *FlightAgent) SearchFlights(ctx context.Context, req FlightSearchRequest) ([]FlightOption, error) {
+	req = req.WithDefaults()
+	if req.Origin == "" || req.Destination == "" {
+		return nil, fmt.Errorf("origin and destination are required")
+	}
+	if req.Origin == req.Destination {
+		return nil, fmt.Errorf("origin and destination are the same airport (%s)", req.Origin)
+	}
+
+	var options []FlightOption
+	if a.source != nil {
+		flights, err := a.source.SearchFlights(req.Origin, req.Destination, req.Date.Format("2006-01-02"))
+		if err != nil {
+			log.Printf("FlightAgent: Flight search API failed: %v", err)
+		} else {
+			options = flightOptionsFromSchedule(flights, req)
+		}
+	}
+
+	if len(options) == 0 {
+		options = estimateFlightOptions(req)
+	}
+
+	options = RankFlights(options, req)
+	log.Printf("FlightAgent: Found %d flight options %s → %s on %s",
+		len(options), req.Origin, req.Destination, req.Date.Format("2006-01-02"))
+	return options, nil
+}
+
+// flightOptionsFromSchedule converts scheduled flights, estimating prices the API leaves out
+func flightOptionsFromSchedule(flights []models.FlightInfo, req FlightSearchRequest) []FlightOption {
+	options := make([]FlightOption, 0, len(flights))
+	for _, flight := range flights {
+		option := FlightOption{
+			Airline:           flight.Airline,
+			FlightNumber:      flight.FlightNumber,
+			Origin:            req.Origin,
+			Destination:       req.Destination,
+			DepartTime:        flight.DepartTime,
+			ArriveTime:        flight.ArriveTime,
+			DurationMinutes:   int(flight.ArriveTime.Sub(flight.DepartTime).Minutes()),
+			Stops:             flight.Stops,
+			PricePerPassenger: flight.Price,
+			Currency:          "THB",
+			Source:            "api",
+		}
+		if option.DurationMinutes <= 0 {
+			option.DurationMinutes = estimateFlightMinutes(req.Origin, req.Destination)
+			option.ArriveTime = option.DepartTime.Add(time.Duration(option.DurationMinutes) * time.Minute)
+		}
+		if option.PricePerPassenger <= 0 {
+			option.PricePerPassenger = float64(estimateFlightPrice(req.Origin, req.Destination))
+			option.PriceEstimated = true
+		}
+		option.TotalPrice = option.PricePerPassenger * float64(req.Passengers)
+		options = append(options, option)
+	}
+	return options
+}
+
+// estimateFlightOptions builds typical options for a route: a direct full-service flight,
+// a cheaper one-stop connection and an early direct flight, stable for the same route and date
+func estimateFlightOptions(req FlightSearchRequest) []FlightOption {
+	basePrice := float64(estimateFlightPrice(req.Origin, req.Destination))
+	directMinutes := estimateFlightMinutes(req.Origin, req.Destination)
+	mainAirline := estimateAirline(req.Origin, req.Destination)
+
+	// Vary prices a little per route and date without making results random
+	hash := fnv.New32a()
+	hash.Write([]byte(req.Origin + req.Destination + req.Date.Format("2006-01-02")))
+	variance := 0.9 + float64(hash.Sum32()%21)/100 // 0.90 to 1.10
+
+	templates := []struct {
+		airline      string
+		departHour   int
+		departMinute int
+		stops        int
+		priceFactor  float64
+		extraMinutes int
+	}{
+		{mainAirline, 10, 30, 0, 1.0, 0},
+		{connectingAirline(mainAirline), 13, 15, 1, 0.78, directMinutes/3 + 150},
+		{mainAirline, 6, 45, 0, 0.92, 0},
+	}
+
+	options := make([]FlightOption, 0, len(templates))
+	for i, template := range templates {
+		depart := req.Date.Add(time.Duration(template.departHour)*time.Hour + time.Duration(template.departMinute)*time.Minute)
+		minutes := directMinutes + template.extraMinutes
+		price := math.Round(basePrice*template.priceFactor*variance/10) * 10
+
+		options = append(options, FlightOption{
+			Airline:           template.airline,
+			FlightNumber:      fmt.Sprintf("%s%d", airlineCode(template.airline), 100+int(hash.Sum32()%800)+i),
+			Origin:            req.Origin,
+			Destination:       req.Destination,
+			DepartTime:        depart,
+			ArriveTime:        depart.Add(time.Duration(minutes) * time.Minute),
+			DurationMinutes:   minutes,
+			Stops:             template.stops,
+			PricePerPassenger: price,
+			TotalPrice:        price * float64(req.Passengers),
+			Currency:          "THB",
+			PriceEstimated:    true,
+			Source:            "estimate",
+		})
+	}
+	return options
+}
+
+// RankFlights scores options on price, duration and stops, drops those with too many stops,
+// sorts them as requested and numbers them from 1
+func RankFlights(options []FlightOption, req FlightSearchRequest) []FlightOption {
+	req = req.WithDefaults()
+
+	ranked := make([]FlightOption, 0, len(options))
+	for _, option := range options {
+		if (req.DirectOnly && option.Stops > 0) || (req.MaxStops > 0 && option.Stops > req.MaxStops) {
+			continue
+		}
+		ranked = append(ranked, option)
+	}
+	if len(ranked) == 0 {
+		return ranked
+	}
+
+	// Score each option relative to the cheapest and fastest: 1.0 is cheapest, fastest and direct
+	minPrice, minMinutes := ranked[0].PricePerPassenger, ranked[0].DurationMinutes
+	for _, option := range ranked {
+		minPrice = math.Min(minPrice, option.PricePerPassenger)
+		if option.DurationMinutes < minMinutes {
+			minMinutes = option.DurationMinutes
+		}
+	}
+	for i := range ranked {
+		priceScore, durationScore := 1.0, 1.0
+		if ranked[i].PricePerPassenger > 0 {
+			priceScore = minPrice / ranked[i].PricePerPassenger
+		}
+		if ranked[i].DurationMinutes > 0 {
+			durationScore = float64(minMinutes) / float64(ranked[i].DurationMinutes)
+		}
+		stopsScore := 1.0 / float64(1+ranked[i].Stops)
+		ranked[i].Score = math.Round((0.5*priceScore+0.3*durationScore+0.2*stopsScore)*100) / 100
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		switch req.SortBy {
+		case FlightSortPrice:
+			return a.PricePerPassenger < b.PricePerPassenger
+		case FlightSortDuration:
+			return a.DurationMinutes < b.DurationMinutes
+		case FlightSortStops:
+			if a.Stops != b.Stops {
+				return a.Stops < b.Stops
+			}
+			return a.PricePerPassenger < b.PricePerPassenger
+		}
+		return a.Score > b.Score
+	})
+
+	if len(ranked) > req.Limit {
+		ranked = ranked[:req.Limit]
+	}
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
+
+// NormalizeFlightSort maps free text such as "cheapest" or "fastest" to a sort order (default best)
+func NormalizeFlightSort(sortBy string) string {
+	lower := strings.ToLower(strings.TrimSpace(sortBy))
+	switch {
+	case strings.Contains(lower, "price") || strings.Contains(lower, "cheap"):
+		return FlightSortPrice
+	case strings.Contains(lower, "duration") || strings.Contains(lower, "fast") || strings.Contains(lower, "short"):
+		return FlightSortDuration
+	case strings.Contains(lower, "stop") || strings.Contains(lower, "direct"):
+		return FlightSortStops
+	}
+	return FlightSortBest
+}
+
+// airportCode resolves a city, country or airport name to its IATA code; unknown three-letter codes are upper-cased
+func airportCode(name string) string {
+	name = strings.TrimSpace(name)
+	if code := airports.AirportCode(name); code != "" {
+		return code
+	}
+	if len(name) == 3 {
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// estimateFlightMinutes returns the typical direct flight time for a route
+func estimateFlightMinutes(from, to string) int {
+	minutes := map[string]int{
+		"BKK-YVR": 900, "BKK-NRT": 360, "BKK-KIX": 345, "BKK-ICN": 330, "BKK-SIN": 145, "BKK-HKG": 165,
+		"BKK-TPE": 225, "BKK-KUL": 125, "BKK-CGK": 205, "BKK-DPS": 255, "BKK-HAN": 110, "BKK-SYD": 540,
+		"BKK-LHR": 795, "BKK-CDG": 780, "BKK-FRA": 720, "BKK-LAX": 1020, "BKK-JFK": 1200, "BKK-DXB": 390,
+		"BKK-CNX": 75, "BKK-HKT": 85, "BKK-KBV": 80,
+	}
+
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if value, ok := minutes[from+"-"+to]; ok {
+		return value
+	}
+	if value, ok := minutes[to+"-"+from]; ok {
+		return value
+	}
+
+	// Cruise at about 780 km/h plus time to take off and land
+	if distance, ok := airports.DistanceKm(from, to); ok {
+		return int(distance/780*60) + 35
+	}
+	return 480
+}
+
+// connectingAirline suggests a carrier for a one-stop alternative
+func connectingAirline(mainAirline string) string {
+	alternatives := map[string]string{
+		"Thai Airways":       "Cathay Pacific",
+		"EVA Air":            "China Airlines",
+		"Singapore Airlines": "Scoot",
+		"Emirates":           "Qatar Airways",
+		"Korean Air":         "Asiana Airlines",
+	}
+	if airline, ok := alternatives[mainAirline]; ok {
+		return airline
+	}
+	return "Emirates"
+}
+
+// airlineCode returns the IATA designator used to build estimated flight numbers
+func airlineCode(airline string) string {
+	codes := map[string]string{
+		"Thai Airways": "TG", "EVA Air": "BR", "Korean Air": "KE", "Singapore Airlines": "SQ",
+		"Cathay Pacific": "CX", "AirAsia": "FD", "Thai Lion Air": "SL", "Qantas": "QF",
+		"British Airways": "BA", "Air France": "AF", "Lufthansa": "LH", "United Airlines": "UA",
+		"American Airlines": "AA", "Emirates": "EK", "China Airlines": "CI", "Scoot": "TR",
+		"Qatar Airways": "QR", "Asiana Airlines": "OZ",
+	}
+	if code, ok := codes[airline]; ok {
+		return code
+	}
+	return "XX"
+}
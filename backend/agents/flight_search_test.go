@@ -0,0 +1,137 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFlightSource returns fixed scheduled flights
+type fakeFlightSource struct {
+	flights []models.FlightInfo
+	err     error
+}
+
+func (f *fakeFlightSource) SearchFlights(origin, destination, date string) ([]models.FlightInfo, error) {
+	return f.flights, f.err
+}
+
+func TestRankFlights(t *testing.T) {
+	options := []FlightOption{
+		{Airline: "Slow Cheap", PricePerPassenger: 8000, DurationMinutes: 600, Stops: 1},
+		{Airline: "Direct", PricePerPassenger: 10000, DurationMinutes: 360},
+		{Airline: "Two Stops", PricePerPassenger: 7000, DurationMinutes: 900, Stops: 2},
+	}
+
+	t.Run("Best balances price, duration and stops", func(t *testing.T) {
+		ranked := RankFlights(options, FlightSearchRequest{})
+		require.Len(t, ranked, 3)
+		assert.Equal(t, "Direct", ranked[0].Airline)
+		assert.Equal(t, 1, ranked[0].Rank)
+		assert.Equal(t, 3, ranked[2].Rank)
+		assert.Greater(t, ranked[0].Score, ranked[1].Score)
+	})
+
+	t.Run("Sort by price, duration and stops", func(t *testing.T) {
+		assert.Equal(t, "Two Stops", RankFlights(options, FlightSearchRequest{SortBy: FlightSortPrice})[0].Airline)
+		assert.Equal(t, "Direct", RankFlights(options, FlightSearchRequest{SortBy: FlightSortDuration})[0].Airline)
+		byStops := RankFlights(options, FlightSearchRequest{SortBy: FlightSortStops})
+		assert.Equal(t, []int{0, 1, 2}, []int{byStops[0].Stops, byStops[1].Stops, byStops[2].Stops})
+	})
+
+	t.Run("Stop filters and limit", func(t *testing.T) {
+		direct := RankFlights(options, FlightSearchRequest{DirectOnly: true})
+		require.Len(t, direct, 1)
+		assert.Equal(t, "Direct", direct[0].Airline)
+
+		assert.Len(t, RankFlights(options, FlightSearchRequest{MaxStops: 1}), 2)
+		assert.Len(t, RankFlights(options, FlightSearchRequest{Limit: 2}), 2)
+	})
+
+	t.Run("Does not modify the input", func(t *testing.T) {
+		RankFlights(options, FlightSearchRequest{})
+		assert.Equal(t, "Slow Cheap", options[0].Airline)
+		assert.Zero(t, options[0].Rank)
+	})
+}
+
+func TestNormalizeFlightSort(t *testing.T) {
+	assert.Equal(t, FlightSortPrice, NormalizeFlightSort("cheapest"))
+	assert.Equal(t, FlightSortDuration, NormalizeFlightSort("Fastest"))
+	assert.Equal(t, FlightSortStops, NormalizeFlightSort("direct"))
+	assert.Equal(t, FlightSortBest, NormalizeFlightSort(""))
+}
+
+func TestFlightAgent_SearchFlights_Estimates(t *testing.T) {
+	agent := NewFlightAgent("", "")
+	req := FlightSearchRequest{Origin: "Bangkok", Destination: "Tokyo", Date: date("2025-05-01"), Passengers: 2}
+
+	options, err := agent.SearchFlights(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, options, 3)
+
+	for _, option := range options {
+		assert.Equal(t, "BKK", option.Origin)
+		assert.Equal(t, "NRT", option.Destination)
+		assert.True(t, option.PriceEstimated)
+		assert.Equal(t, option.PricePerPassenger*2, option.TotalPrice)
+		assert.Equal(t, "2025-05-01", option.DepartTime.Format("2006-01-02"))
+		assert.True(t, option.ArriveTime.After(option.DepartTime))
+	}
+
+	again, err := agent.SearchFlights(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, options, again, "Estimates should be stable for the same route and date")
+}
+
+func TestFlightAgent_SearchFlights_WithSource(t *testing.T) {
+	depart := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	agent := NewFlightAgent("", "")
+	agent.SetSource(&fakeFlightSource{flights: []models.FlightInfo{
+		{Airline: "Thai Airways", FlightNumber: "TG642", DepartTime: depart, ArriveTime: depart.Add(6 * time.Hour), Price: 15000},
+		{Airline: "ANA", FlightNumber: "NH806", DepartTime: depart, ArriveTime: depart.Add(7 * time.Hour), Stops: 1},
+	}})
+
+	options, err := agent.SearchFlights(context.Background(), FlightSearchRequest{Origin: "BKK", Destination: "NRT", SortBy: FlightSortDuration})
+	require.NoError(t, err)
+	require.Len(t, options, 2)
+	assert.Equal(t, "TG642", options[0].FlightNumber)
+	assert.Equal(t, 360, options[0].DurationMinutes)
+	assert.False(t, options[0].PriceEstimated)
+	assert.Equal(t, "api", options[0].Source)
+	assert.True(t, options[1].PriceEstimated, "Missing prices should be estimated")
+	assert.Positive(t, options[1].PricePerPassenger)
+
+	agent.SetSource(&fakeFlightSource{err: errors.New("API down")})
+	options, err = agent.SearchFlights(context.Background(), FlightSearchRequest{Origin: "BKK", Destination: "NRT"})
+	require.NoError(t, err)
+	assert.Equal(t, "estimate", options[0].Source, "Should fall back to estimates when the API fails")
+}
+
+func TestFlightAgent_SearchFlights_Errors(t *testing.T) {
+	agent := NewFlightAgent("", "")
+
+	_, err := agent.SearchFlights(context.Background(), FlightSearchRequest{Origin: "BKK"})
+	assert.Error(t, err)
+
+	_, err = agent.SearchFlights(context.Background(), FlightSearchRequest{Origin: "Bangkok", Destination: "bkk"})
+	assert.ErrorContains(t, err, "same airport")
+}
+
+func TestFlightSearchRequest_ResolvesPlaceNames(t *testing.T) {
+	req := FlightSearchRequest{Origin: "กรุงเทพ", Destination: "แคนาดา"}.WithDefaults()
+	assert.Equal(t, "BKK", req.Origin)
+	assert.Equal(t, "YVR", req.Destination)
+
+	req = FlightSearchRequest{Origin: "Osaka, Japan", Destination: "xyz"}.WithDefaults()
+	assert.Equal(t, "KIX", req.Origin)
+	assert.Equal(t, "XYZ", req.Destination, "Unknown codes are passed through")
+
+	assert.Equal(t, 125, estimateFlightMinutes("BKK", "KUL"), "Known routes keep their typical time")
+	assert.InDelta(t, 140, estimateFlightMinutes("NRT", "ICN"), 30, "Other routes are estimated from the distance")
+}
@@ -1,6 +1,9 @@
 package agents
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 )
@@ -585,3 +588,66 @@ func TestGetCheapestFlight_ValidDate(t *testing.T) {
 		})
 	}
 }
+
+func TestEstimateFlightPrice_FromDistance(t *testing.T) {
+	// No fixed price for Tokyo to Seoul, so it is estimated from the distance between the airports
+	price := estimateFlightPrice("NRT", "ICN")
+	if price == 38000 || price < 5000 || price > 10000 {
+		t.Errorf("estimateFlightPrice(NRT, ICN) = %d, want a short-haul estimate", price)
+	}
+}
+
+func TestGetCheapestFlight_Skyscanner(t *testing.T) {
+	var searchQuery map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/searchAirport":
+			entityIDs := map[string]string{"BKK": "95673506", "YVR": "95673812"}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": []interface{}{
+				map[string]interface{}{"navigation": map[string]interface{}{"relevantFlightParams": map[string]interface{}{
+					"skyId": query.Get("query"), "entityId": entityIDs[query.Get("query")],
+				}}},
+			}})
+		case "/searchFlights":
+			searchQuery = map[string]string{}
+			for key := range query {
+				searchQuery[key] = query.Get(key)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"itineraries": []interface{}{
+				map[string]interface{}{
+					"price": map[string]interface{}{"raw": 31000.0},
+					"legs": []interface{}{map[string]interface{}{"carriers": map[string]interface{}{
+						"marketing": []interface{}{map[string]interface{}{"name": "Air Canada"}},
+					}}},
+				},
+			}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	originalURL := skyscannerBaseURL
+	skyscannerBaseURL = server.URL
+	defer func() { skyscannerBaseURL = originalURL }()
+	os.Setenv("FLIGHT_API_KEY", "test-key")
+	defer os.Unsetenv("FLIGHT_API_KEY")
+
+	// City and Thai country names are resolved to airport codes first
+	price, airline := GetCheapestFlight("Bangkok", "แคนาดา", "2025-12-01")
+	if price != 31000 || airline != "Air Canada" {
+		t.Errorf("GetCheapestFlight() = %d, %s, want 31000, Air Canada", price, airline)
+	}
+
+	want := map[string]string{
+		"originSkyId": "BKK", "originEntityId": "95673506",
+		"destinationSkyId": "YVR", "destinationEntityId": "95673812",
+	}
+	for key, value := range want {
+		if searchQuery[key] != value {
+			t.Errorf("searchFlights %s = %q, want %q", key, searchQuery[key], value)
+		}
+	}
+}
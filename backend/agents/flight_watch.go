@@ -0,0 +1,377 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Flight status fields that trigger a notification when they change
+const (
+	FlightChangeStatus = "status"
+	FlightChangeDelay  = "delay"
+	FlightChangeGate   = "gate"
+)
+
+// DefaultFlightWatchInterval is how often the watcher re-checks subscribed flights
+const DefaultFlightWatchInterval = 5 * time.Minute
+
+// FlightWatch is a subscription to status changes of a flight on a given date
+type FlightWatch struct {
+	ID         int64     `json:"id"`
+	UserID     string    `json:"userId,omitempty"`
+	FlightCode string    `json:"flight_code"`
+	FlightDate time.Time `json:"flight_date"`
+	// Channel is how notifications are delivered (webhook, line or email) and Target where to:
+	// a URL, a LINE user ID or an email address
+	Channel       string        `json:"channel"`
+	Target        string        `json:"target"`
+	Active        bool          `json:"active"`
+	LastStatus    *FlightStatus `json:"last_status,omitempty"`
+	LastCheckedAt *time.Time    `json:"last_checked_at,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+}
+
+// FlightWatchEvent records a status transition of a watched flight
+type FlightWatchEvent struct {
+	ID         int64         `json:"id"`
+	WatchID    int64         `json:"watch_id"`
+	FlightCode string        `json:"flight_code"`
+	Changes    []string      `json:"changes"`
+	Previous   *FlightStatus `json:"previous,omitempty"`
+	Current    FlightStatus  `json:"current"`
+	Message    string        `json:"message"`
+	Notified   bool          `json:"notified"`
+	Error      string        `json:"error,omitempty"`
+	CreatedAt  time.Time     `json:"created_at"`
+}
+
+// FlightWatchStore persists flight watches and their status transitions
+type FlightWatchStore interface {
+	CreateWatch(ctx context.Context, watch *FlightWatch) error
+	// GetWatch returns the watch with the given ID, or nil if there is none
+	GetWatch(ctx context.Context, id int64) (*FlightWatch, error)
+	ListWatches(ctx context.Context, userID string) ([]FlightWatch, error)
+	ActiveWatches(ctx context.Context) ([]FlightWatch, error)
+	// UpdateWatchStatus saves the latest checked status and whether the watch stays active
+	UpdateWatchStatus(ctx context.Context, id int64, status FlightStatus, checkedAt time.Time, active bool) error
+	DeleteWatch(ctx context.Context, id int64) error
+	AddEvent(ctx context.Context, event *FlightWatchEvent) error
+	ListEvents(ctx context.Context, watchID int64) ([]FlightWatchEvent, error)
+}
+
+// flightStatusChecker looks up the current status of a flight, e.g. FlightAgent
+type flightStatusChecker interface {
+	CheckFlight(ctx context.Context, flightCode string) (*FlightStatus, error)
+}
+
+// FlightWatcher polls watched flights on a schedule, records status transitions and sends notifications
+type FlightWatcher struct {
+	checker  flightStatusChecker
+	store    FlightWatchStore
+	notifier Notifier
+	interval time.Duration
+	now      func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewFlightWatcher creates a watcher that checks flights every interval (default 5 minutes)
+func NewFlightWatcher(checker flightStatusChecker, store FlightWatchStore, notifier Notifier, interval time.Duration) *FlightWatcher {
+	if interval <= 0 {
+		interval = DefaultFlightWatchInterval
+	}
+	return &FlightWatcher{
+		checker:  checker,
+		store:    store,
+		notifier: notifier,
+		interval: interval,
+		now:      time.Now,
+	}
+}
+
+// Start polls in the background until ctx is cancelled or Stop is called
+func (w *FlightWatcher) Start(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		return
+	}
+
+	ctx, w.cancel = context.WithCancel(ctx)
+	w.done = make(chan struct{})
+
+	go func() {
+		defer close(w.done)
+		log.Printf("FlightWatcher: Checking watched flights every %s", w.interval)
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		for {
+			if err := w.PollOnce(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("FlightWatcher: Poll failed: %v", err)
+			}
+			select {
+			case <-ctx.Done():
+				log.Println("FlightWatcher: Stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop cancels polling and waits for the current poll to finish
+func (w *FlightWatcher) Stop() {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel = nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+// PollOnce checks every active watch that is due: from the day before the flight until two days after it.
+// Watches whose flight has passed or landed are deactivated.
+func (w *FlightWatcher) PollOnce(ctx context.Context) error {
+	watches, err := w.store.ActiveWatches(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load flight watches: %w", err)
+	}
+
+	now := w.now()
+	for _, watch := range watches {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if now.Before(watch.FlightDate.AddDate(0, 0, -1)) {
+			continue
+		}
+		if err := w.checkWatch(ctx, watch, now); err != nil {
+			log.Printf("FlightWatcher: Failed to check watch %d (%s): %v", watch.ID, watch.FlightCode, err)
+		}
+	}
+	return nil
+}
+
+// checkWatch re-checks one flight and records and notifies any change
+func (w *FlightWatcher) checkWatch(ctx context.Context, watch FlightWatch, now time.Time) error {
+	status, err := w.checker.CheckFlight(ctx, watch.FlightCode)
+	if err != nil {
+		return err
+	}
+
+	active := !flightFinished(*status) && now.Before(watch.FlightDate.AddDate(0, 0, 2))
+
+	changes := []string{}
+	if watch.LastStatus != nil {
+		changes = DiffFlightStatus(*watch.LastStatus, *status)
+	}
+
+	if len(changes) > 0 {
+		event := &FlightWatchEvent{
+			WatchID:    watch.ID,
+			FlightCode: watch.FlightCode,
+			Changes:    changes,
+			Previous:   watch.LastStatus,
+			Current:    *status,
+			Message:    FlightChangeMessage(watch.FlightCode, *watch.LastStatus, *status, changes),
+			CreatedAt:  now,
+		}
+
+		if w.notifier != nil {
+			if err := w.notifier.Notify(ctx, watch, *event); err != nil {
+				log.Printf("FlightWatcher: Failed to notify watch %d via %s: %v", watch.ID, watch.Channel, err)
+				event.Error = err.Error()
+			} else {
+				event.Notified = true
+			}
+		}
+
+		if err := w.store.AddEvent(ctx, event); err != nil {
+			return err
+		}
+		log.Printf("FlightWatcher: %s changed (%s)", watch.FlightCode, strings.Join(changes, ", "))
+	}
+
+	return w.store.UpdateWatchStatus(ctx, watch.ID, *status, now, active)
+}
+
+// DiffFlightStatus lists which of status, delay and gate differ between two checks
+func DiffFlightStatus(previous, current FlightStatus) []string {
+	changes := []string{}
+	if !strings.EqualFold(previous.Status, current.Status) {
+		changes = append(changes, FlightChangeStatus)
+	}
+	if previous.DelayMinutes != current.DelayMinutes {
+		changes = append(changes, FlightChangeDelay)
+	}
+	if !strings.EqualFold(previous.Gate, current.Gate) {
+		changes = append(changes, FlightChangeGate)
+	}
+	return changes
+}
+
+// FlightChangeMessage describes the changes of a watched flight, e.g.
+// "Flight TG642 update: status on-time → delayed, delayed by 30 minutes."
+func FlightChangeMessage(flightCode string, previous, current FlightStatus, changes []string) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change {
+		case FlightChangeStatus:
+			parts = append(parts, fmt.Sprintf("status %s → %s", previous.Status, current.Status))
+		case FlightChangeDelay:
+			if current.DelayMinutes > 0 {
+				parts = append(parts, fmt.Sprintf("delayed by %d minutes", current.DelayMinutes))
+			} else {
+				parts = append(parts, "no longer delayed")
+			}
+		case FlightChangeGate:
+			parts = append(parts, fmt.Sprintf("gate %s → %s", valueOr(previous.Gate, "unassigned"), valueOr(current.Gate, "unassigned")))
+		}
+	}
+	return fmt.Sprintf("Flight %s update: %s.", flightCode, strings.Join(parts, ", "))
+}
+
+// flightFinished reports whether a flight will not change any more
+func flightFinished(status FlightStatus) bool {
+	switch strings.ToLower(status.Status) {
+	case "landed", "cancelled", "diverted":
+		return true
+	}
+	return false
+}
+
+// valueOr returns value, or fallback when it is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// MemoryFlightWatchStore keeps flight watches in process memory
+type MemoryFlightWatchStore struct {
+	mu          sync.RWMutex
+	nextID      int64
+	nextEventID int64
+	watches     map[int64]FlightWatch
+	events      []FlightWatchEvent
+}
+
+// NewMemoryFlightWatchStore creates an empty in-memory watch store
+func NewMemoryFlightWatchStore() *MemoryFlightWatchStore {
+	return &MemoryFlightWatchStore{watches: make(map[int64]FlightWatch)}
+}
+
+// CreateWatch implements FlightWatchStore
+func (s *MemoryFlightWatchStore) CreateWatch(ctx context.Context, watch *FlightWatch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	watch.ID = s.nextID
+	if watch.CreatedAt.IsZero() {
+		watch.CreatedAt = time.Now()
+	}
+	s.watches[watch.ID] = *watch
+	return nil
+}
+
+// GetWatch implements FlightWatchStore
+func (s *MemoryFlightWatchStore) GetWatch(ctx context.Context, id int64) (*FlightWatch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	watch, ok := s.watches[id]
+	if !ok {
+		return nil, nil
+	}
+	return &watch, nil
+}
+
+// ListWatches implements FlightWatchStore
+func (s *MemoryFlightWatchStore) ListWatches(ctx context.Context, userID string) ([]FlightWatch, error) {
+	return s.filter(func(watch FlightWatch) bool { return watch.UserID == userID }), nil
+}
+
+// ActiveWatches implements FlightWatchStore
+func (s *MemoryFlightWatchStore) ActiveWatches(ctx context.Context) ([]FlightWatch, error) {
+	return s.filter(func(watch FlightWatch) bool { return watch.Active }), nil
+}
+
+// UpdateWatchStatus implements FlightWatchStore
+func (s *MemoryFlightWatchStore) UpdateWatchStatus(ctx context.Context, id int64, status FlightStatus, checkedAt time.Time, active bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watch, ok := s.watches[id]
+	if !ok {
+		return fmt.Errorf("flight watch %d not found", id)
+	}
+	watch.LastStatus = &status
+	watch.LastCheckedAt = &checkedAt
+	watch.Active = active
+	s.watches[id] = watch
+	return nil
+}
+
+// DeleteWatch implements FlightWatchStore
+func (s *MemoryFlightWatchStore) DeleteWatch(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.watches, id)
+
+	events := s.events[:0]
+	for _, event := range s.events {
+		if event.WatchID != id {
+			events = append(events, event)
+		}
+	}
+	s.events = events
+	return nil
+}
+
+// AddEvent implements FlightWatchStore
+func (s *MemoryFlightWatchStore) AddEvent(ctx context.Context, event *FlightWatchEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextEventID++
+	event.ID = s.nextEventID
+	s.events = append(s.events, *event)
+	return nil
+}
+
+// ListEvents implements FlightWatchStore
+func (s *MemoryFlightWatchStore) ListEvents(ctx context.Context, watchID int64) ([]FlightWatchEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := []FlightWatchEvent{}
+	for _, event := range s.events {
+		if event.WatchID == watchID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+// filter returns the watches that match, in creation order
+func (s *MemoryFlightWatchStore) filter(match func(FlightWatch) bool) []FlightWatch {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	watches := []FlightWatch{}
+	for _, watch := range s.watches {
+		if match(watch) {
+			watches = append(watches, watch)
+		}
+	}
+	sort.Slice(watches, func(i, j int) bool { return watches[i].ID < watches[j].ID })
+	return watches
+}
@@ -0,0 +1,200 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedChecker returns the next status from a script on every check
+type scriptedChecker struct {
+	mu       sync.Mutex
+	statuses []FlightStatus
+	checks   int
+}
+
+func (c *scriptedChecker) CheckFlight(ctx context.Context, flightCode string) (*FlightStatus, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.statuses[c.checks%len(c.statuses)]
+	c.checks++
+	status.FlightCode = flightCode
+	return &status, nil
+}
+
+func (c *scriptedChecker) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.checks
+}
+
+// recordingNotifier remembers the events it was asked to send
+type recordingNotifier struct {
+	events []FlightWatchEvent
+	err    error
+}
+
+func (n *recordingNotifier) Notify(ctx context.Context, watch FlightWatch, event FlightWatchEvent) error {
+	n.events = append(n.events, event)
+	return n.err
+}
+
+func newTestWatch(t *testing.T, store *MemoryFlightWatchStore, flightDate time.Time) FlightWatch {
+	t.Helper()
+	watch := FlightWatch{FlightCode: "TG642", FlightDate: flightDate, Channel: ChannelWebhook, Target: "http://example.com", Active: true}
+	require.NoError(t, store.CreateWatch(context.Background(), &watch))
+	return watch
+}
+
+func TestDiffFlightStatus(t *testing.T) {
+	previous := FlightStatus{Status: "on-time", Gate: "A12"}
+
+	assert.Empty(t, DiffFlightStatus(previous, FlightStatus{Status: "On-Time", Gate: "a12", DepartureTime: "later"}))
+	assert.Equal(t, []string{FlightChangeStatus, FlightChangeDelay}, DiffFlightStatus(previous, FlightStatus{Status: "delayed", DelayMinutes: 30, Gate: "A12"}))
+	assert.Equal(t, []string{FlightChangeGate}, DiffFlightStatus(previous, FlightStatus{Status: "on-time", Gate: "B3"}))
+}
+
+func TestFlightChangeMessage(t *testing.T) {
+	message := FlightChangeMessage("TG642",
+		FlightStatus{Status: "on-time", Gate: "A12"},
+		FlightStatus{Status: "delayed", DelayMinutes: 45, Gate: "B3"},
+		[]string{FlightChangeStatus, FlightChangeDelay, FlightChangeGate})
+	assert.Equal(t, "Flight TG642 update: status on-time → delayed, delayed by 45 minutes, gate A12 → B3.", message)
+}
+
+func TestFlightWatcher_RecordsAndNotifiesChanges(t *testing.T) {
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	store := NewMemoryFlightWatchStore()
+	watch := newTestWatch(t, store, date("2025-05-01"))
+
+	checker := &scriptedChecker{statuses: []FlightStatus{
+		{Status: "on-time", Gate: "A12"},
+		{Status: "on-time", Gate: "A12"},
+		{Status: "delayed", DelayMinutes: 30, Gate: "A12"},
+	}}
+	notifier := &recordingNotifier{}
+	watcher := NewFlightWatcher(checker, store, notifier, time.Minute)
+	watcher.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, watcher.PollOnce(context.Background()))
+	}
+
+	// The first check sets the baseline and the second finds no change
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, []string{FlightChangeStatus, FlightChangeDelay}, notifier.events[0].Changes)
+	assert.Equal(t, "Flight TG642 update: status on-time → delayed, delayed by 30 minutes.", notifier.events[0].Message)
+
+	events, err := store.ListEvents(context.Background(), watch.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].Notified)
+	assert.Equal(t, "on-time", events[0].Previous.Status)
+	assert.Equal(t, "delayed", events[0].Current.Status)
+
+	saved, err := store.GetWatch(context.Background(), watch.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.Active)
+	assert.Equal(t, 30, saved.LastStatus.DelayMinutes)
+	assert.Equal(t, now, *saved.LastCheckedAt)
+}
+
+func TestFlightWatcher_RecordsFailedNotifications(t *testing.T) {
+	store := NewMemoryFlightWatchStore()
+	watch := newTestWatch(t, store, date("2025-05-01"))
+
+	checker := &scriptedChecker{statuses: []FlightStatus{{Status: "on-time"}, {Status: "cancelled"}}}
+	watcher := NewFlightWatcher(checker, store, &recordingNotifier{err: errors.New("webhook down")}, time.Minute)
+	watcher.now = func() time.Time { return time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, watcher.PollOnce(context.Background()))
+	require.NoError(t, watcher.PollOnce(context.Background()))
+
+	events, err := store.ListEvents(context.Background(), watch.ID)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.False(t, events[0].Notified)
+	assert.Equal(t, "webhook down", events[0].Error)
+
+	saved, err := store.GetWatch(context.Background(), watch.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.Active, "Cancelled flights are no longer watched")
+}
+
+func TestFlightWatcher_OnlyChecksFlightsThatAreDue(t *testing.T) {
+	store := NewMemoryFlightWatchStore()
+	future := newTestWatch(t, store, date("2025-05-10"))
+	past := newTestWatch(t, store, date("2025-04-20"))
+
+	checker := &scriptedChecker{statuses: []FlightStatus{{Status: "on-time"}}}
+	watcher := NewFlightWatcher(checker, store, &recordingNotifier{}, time.Minute)
+	watcher.now = func() time.Time { return time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC) }
+
+	require.NoError(t, watcher.PollOnce(context.Background()))
+	assert.Equal(t, 1, checker.count(), "Flights more than a day away are not checked yet")
+
+	saved, err := store.GetWatch(context.Background(), future.ID)
+	require.NoError(t, err)
+	assert.True(t, saved.Active)
+	assert.Nil(t, saved.LastStatus)
+
+	saved, err = store.GetWatch(context.Background(), past.ID)
+	require.NoError(t, err)
+	assert.False(t, saved.Active, "Flights that have passed are deactivated")
+
+	active, err := store.ActiveWatches(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, active, 1)
+}
+
+func TestFlightWatcher_StartAndStop(t *testing.T) {
+	store := NewMemoryFlightWatchStore()
+	newTestWatch(t, store, time.Now())
+
+	checker := &scriptedChecker{statuses: []FlightStatus{{Status: "on-time"}}}
+	watcher := NewFlightWatcher(checker, store, &recordingNotifier{}, 10*time.Millisecond)
+	watcher.Start(context.Background())
+
+	require.Eventually(t, func() bool { return checker.count() >= 2 }, time.Second, 5*time.Millisecond)
+	watcher.Stop()
+
+	checks := checker.count()
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, checks, checker.count(), "No checks after Stop")
+	watcher.Stop()
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Event.FlightCode == "FAIL1" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewDefaultNotifier()
+	watch := FlightWatch{ID: 7, FlightCode: "TG642", Channel: ChannelWebhook, Target: server.URL}
+	event := FlightWatchEvent{WatchID: 7, FlightCode: "TG642", Message: "Flight TG642 update: gate A12 → B3."}
+
+	require.NoError(t, notifier.Notify(context.Background(), watch, event))
+	assert.Equal(t, int64(7), received.Watch.ID)
+	assert.Equal(t, event.Message, received.Event.Message)
+
+	event.FlightCode = "FAIL1"
+	assert.ErrorContains(t, notifier.Notify(context.Background(), watch, event), "status 500")
+
+	watch.Channel = "sms"
+	assert.ErrorContains(t, notifier.Notify(context.Background(), watch, event), "unsupported")
+	assert.True(t, notifier.Supports(ChannelLine))
+}
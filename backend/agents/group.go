@@ -0,0 +1,360 @@
+package agents
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+)
+
+// Ways of splitting a shared expense
+const (
+	// SplitEqual divides the expense evenly among the listed members, or all members when none are listed
+	SplitEqual = "equal"
+	// SplitExact gives each member an amount in the expense's currency; the amounts add up to the expense
+	SplitExact = "exact"
+	// SplitPercent gives each member a percentage; the percentages add up to 100
+	SplitPercent = "percent"
+)
+
+// maxExactSettlement is the largest number of unsettled members for which the fewest transfers are
+// searched exhaustively; larger groups are settled greedily
+const maxExactSettlement = 16
+
+// TripMember is a person sharing the costs of a trip
+type TripMember struct {
+	ID        int64     `json:"id"`
+	TripID    int64     `json:"trip_id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExpenseSplit says how a shared expense is divided among members
+type ExpenseSplit struct {
+	Mode   string       `json:"mode"`
+	Shares []SplitShare `json:"shares,omitempty"`
+}
+
+// SplitShare is one member's part of a split: Amount for exact splits, Percent for percentage splits
+type SplitShare struct {
+	MemberID int64   `json:"member_id"`
+	Amount   float64 `json:"amount,omitempty"`
+	Percent  float64 `json:"percent,omitempty"`
+}
+
+// MemberShares returns what each member owes for a shared expense, in satang (hundredths of a baht)
+// so shares add up exactly to the expense. Rounding leftovers go to the first members.
+func (e Expense) MemberShares(members []TripMember) (map[int64]int64, error) {
+	split := ExpenseSplit{Mode: SplitEqual}
+	if e.Split != nil {
+		split = *e.Split
+	}
+
+	known := make(map[int64]bool, len(members))
+	for _, member := range members {
+		known[member.ID] = true
+	}
+	total := int64(math.Round(e.AmountTHB * 100))
+
+	var ids []int64
+	var weights []float64
+	switch split.Mode {
+	case SplitEqual, "":
+		if len(split.Shares) == 0 {
+			for _, member := range members {
+				ids = append(ids, member.ID)
+				weights = append(weights, 1)
+			}
+		}
+		for _, share := range split.Shares {
+			ids = append(ids, share.MemberID)
+			weights = append(weights, 1)
+		}
+	case SplitExact:
+		sum := 0.0
+		for _, share := range split.Shares {
+			if share.Amount < 0 {
+				return nil, fmt.Errorf("split amounts must not be negative")
+			}
+			ids = append(ids, share.MemberID)
+			weights = append(weights, share.Amount)
+			sum += share.Amount
+		}
+		if math.Abs(sum-e.Amount) > 0.005 {
+			return nil, fmt.Errorf("split amounts add up to %.2f, not the expense amount %.2f", sum, e.Amount)
+		}
+	case SplitPercent:
+		sum := 0.0
+		for _, share := range split.Shares {
+			if share.Percent < 0 {
+				return nil, fmt.Errorf("split percentages must not be negative")
+			}
+			ids = append(ids, share.MemberID)
+			weights = append(weights, share.Percent)
+			sum += share.Percent
+		}
+		if math.Abs(sum-100) > 0.005 {
+			return nil, fmt.Errorf("split percentages add up to %.2f, not 100", sum)
+		}
+	default:
+		return nil, fmt.Errorf("split mode must be equal, exact or percent")
+	}
+
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("the expense is not split among any members")
+	}
+	weightSum := 0.0
+	seen := make(map[int64]bool, len(ids))
+	for i, id := range ids {
+		if !known[id] {
+			return nil, fmt.Errorf("member %d is not part of this trip", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("member %d is listed twice in the split", id)
+		}
+		seen[id] = true
+		weightSum += weights[i]
+	}
+	if weightSum <= 0 {
+		return nil, fmt.Errorf("the expense is not split among any members")
+	}
+
+	shares := make(map[int64]int64, len(ids))
+	allocated := int64(0)
+	for i, id := range ids {
+		shares[id] = int64(math.Floor(float64(total) * weights[i] / weightSum))
+		allocated += shares[id]
+	}
+	for i := 0; allocated < total; i = (i + 1) % len(ids) {
+		if weights[i] > 0 {
+			shares[ids[i]]++
+			allocated++
+		}
+	}
+	return shares, nil
+}
+
+// SharedWith reports whether a member paid for the expense or is named in its split
+func (e Expense) SharedWith(memberID int64) bool {
+	if e.PaidBy == memberID {
+		return true
+	}
+	if e.Split != nil {
+		for _, share := range e.Split.Shares {
+			if share.MemberID == memberID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MemberBalance is what a member paid for the group and what their share of it was, in THB.
+// A positive Net means the member is owed money.
+type MemberBalance struct {
+	MemberID int64   `json:"member_id"`
+	Name     string  `json:"name"`
+	Paid     float64 `json:"paid"`
+	Share    float64 `json:"share"`
+	Net      float64 `json:"net"`
+	// ShareByCategory splits the member's share into the BudgetPlan categories
+	ShareByCategory map[string]float64 `json:"share_by_category"`
+	// Budget is the member's part of the trip budget, when the trip has one
+	Budget float64 `json:"budget,omitempty"`
+}
+
+// Transfer is a payment that settles debts between two members
+type Transfer struct {
+	From     int64   `json:"from"`
+	FromName string  `json:"from_name"`
+	To       int64   `json:"to"`
+	ToName   string  `json:"to_name"`
+	Amount   float64 `json:"amount"`
+}
+
+// Settlement is who owes whom on a group trip
+type Settlement struct {
+	TripID    int64           `json:"trip_id"`
+	Balances  []MemberBalance `json:"balances"`
+	Transfers []Transfer      `json:"transfers"`
+	Currency  string          `json:"currency"`
+}
+
+// SettleGroup works out each member's balance from the shared expenses (those with a payer) and the
+// fewest transfers that settle them. Expenses nobody paid for the group are personal and left out.
+func SettleGroup(tripID int64, members []TripMember, budget *TripBudget, expenses []Expense) (Settlement, error) {
+	settlement := Settlement{TripID: tripID, Balances: []MemberBalance{}, Transfers: []Transfer{}, Currency: currency.Base}
+
+	names := make(map[int64]string, len(members))
+	paid := make(map[int64]int64, len(members))
+	owed := make(map[int64]int64, len(members))
+	byCategory := make(map[int64]map[string]int64, len(members))
+	for _, member := range members {
+		names[member.ID] = member.Name
+		byCategory[member.ID] = make(map[string]int64)
+	}
+
+	for _, expense := range expenses {
+		if expense.PaidBy == 0 {
+			continue
+		}
+		if _, ok := names[expense.PaidBy]; !ok {
+			return settlement, fmt.Errorf("expense %d was paid by member %d, who is not part of this trip", expense.ID, expense.PaidBy)
+		}
+		shares, err := expense.MemberShares(members)
+		if err != nil {
+			return settlement, fmt.Errorf("expense %d: %w", expense.ID, err)
+		}
+		for id, share := range shares {
+			paid[expense.PaidBy] += share
+			owed[id] += share
+			byCategory[id][expense.Category] += share
+		}
+	}
+
+	var ids []int64
+	var nets []int64
+	for _, member := range members {
+		balance := MemberBalance{
+			MemberID:        member.ID,
+			Name:            member.Name,
+			Paid:            float64(paid[member.ID]) / 100,
+			Share:           float64(owed[member.ID]) / 100,
+			Net:             float64(paid[member.ID]-owed[member.ID]) / 100,
+			ShareByCategory: make(map[string]float64, len(ExpenseCategories)),
+		}
+		for _, category := range ExpenseCategories {
+			balance.ShareByCategory[category] = float64(byCategory[member.ID][category]) / 100
+		}
+		if budget != nil && len(members) > 0 {
+			balance.Budget = roundAmount(budget.Total / float64(len(members)))
+		}
+		settlement.Balances = append(settlement.Balances, balance)
+
+		if net := paid[member.ID] - owed[member.ID]; net != 0 {
+			ids = append(ids, member.ID)
+			nets = append(nets, net)
+		}
+	}
+
+	for _, transfer := range minimizeTransfers(ids, nets) {
+		transfer.FromName = names[transfer.From]
+		transfer.ToName = names[transfer.To]
+		settlement.Transfers = append(settlement.Transfers, transfer)
+	}
+	return settlement, nil
+}
+
+// minimizeTransfers settles balances (in satang, summing to zero) with as few transfers as possible.
+// A group whose balances cancel out can be settled with one transfer fewer than its size, so the
+// fewest transfers come from splitting the members into as many such groups as possible.
+func minimizeTransfers(ids []int64, nets []int64) []Transfer {
+	n := len(ids)
+	if n == 0 {
+		return nil
+	}
+	if n > maxExactSettlement {
+		return settleGreedily(ids, nets)
+	}
+
+	// groups[mask] is the most zero-sum groups the members in mask can be split into
+	full := 1<<n - 1
+	sums := make([]int64, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		low := mask & -mask
+		sums[mask] = sums[mask^low] + nets[bitIndex(low)]
+		best := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)] > best {
+				best = groups[mask^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		groups[mask] = best
+	}
+
+	// Peel members off in an order whose running balance returns to zero at every group boundary
+	var order []int
+	for mask := full; mask != 0; {
+		bonus := 0
+		if sums[mask] == 0 {
+			bonus = 1
+		}
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask^(1<<i)]+bonus == groups[mask] {
+				order = append(order, i)
+				mask ^= 1 << i
+				break
+			}
+		}
+	}
+
+	var transfers []Transfer
+	var groupIDs []int64
+	var groupNets []int64
+	running := int64(0)
+	for i := len(order) - 1; i >= 0; i-- {
+		groupIDs = append(groupIDs, ids[order[i]])
+		groupNets = append(groupNets, nets[order[i]])
+		running += nets[order[i]]
+		if running == 0 {
+			transfers = append(transfers, settleGreedily(groupIDs, groupNets)...)
+			groupIDs, groupNets = nil, nil
+		}
+	}
+	return transfers
+}
+
+// settleGreedily pays the largest creditor from the largest debtor until everyone is settled
+func settleGreedily(ids []int64, nets []int64) []Transfer {
+	type balance struct {
+		id     int64
+		amount int64
+	}
+	var creditors, debtors []balance
+	for i, net := range nets {
+		if net > 0 {
+			creditors = append(creditors, balance{ids[i], net})
+		} else if net < 0 {
+			debtors = append(debtors, balance{ids[i], -net})
+		}
+	}
+
+	var transfers []Transfer
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.SliceStable(creditors, func(i, j int) bool { return creditors[i].amount > creditors[j].amount })
+		sort.SliceStable(debtors, func(i, j int) bool { return debtors[i].amount > debtors[j].amount })
+
+		amount := creditors[0].amount
+		if debtors[0].amount < amount {
+			amount = debtors[0].amount
+		}
+		transfers = append(transfers, Transfer{From: debtors[0].id, To: creditors[0].id, Amount: float64(amount) / 100})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return transfers
+}
+
+// bitIndex returns the position of the single set bit in a power of two
+func bitIndex(bit int) int {
+	index := 0
+	for bit > 1 {
+		bit >>= 1
+		index++
+	}
+	return index
+}
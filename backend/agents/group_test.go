@@ -0,0 +1,146 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMembers() []TripMember {
+	return []TripMember{
+		{ID: 1, TripID: 7, Name: "Alice"},
+		{ID: 2, TripID: 7, Name: "Bob"},
+		{ID: 3, TripID: 7, Name: "Chai"},
+	}
+}
+
+func TestExpense_MemberShares(t *testing.T) {
+	members := testMembers()
+
+	t.Run("Equal splits cover everyone and hand out the leftover satang", func(t *testing.T) {
+		expense := Expense{Amount: 100, AmountTHB: 100, PaidBy: 1}
+		shares, err := expense.MemberShares(members)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int64{1: 3334, 2: 3333, 3: 3333}, shares)
+	})
+
+	t.Run("Equal splits among listed members", func(t *testing.T) {
+		expense := Expense{Amount: 900, AmountTHB: 900, PaidBy: 1, Split: &ExpenseSplit{
+			Mode:   SplitEqual,
+			Shares: []SplitShare{{MemberID: 2}, {MemberID: 3}},
+		}}
+		shares, err := expense.MemberShares(members)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int64{2: 45000, 3: 45000}, shares)
+	})
+
+	t.Run("Exact amounts in a foreign currency scale to THB", func(t *testing.T) {
+		expense := Expense{Amount: 40, Currency: "USD", AmountTHB: 1600, PaidBy: 1, Split: &ExpenseSplit{
+			Mode:   SplitExact,
+			Shares: []SplitShare{{MemberID: 1, Amount: 10}, {MemberID: 2, Amount: 30}},
+		}}
+		shares, err := expense.MemberShares(members)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int64{1: 40000, 2: 120000}, shares)
+	})
+
+	t.Run("Percentages", func(t *testing.T) {
+		expense := Expense{Amount: 1000, AmountTHB: 1000, PaidBy: 1, Split: &ExpenseSplit{
+			Mode:   SplitPercent,
+			Shares: []SplitShare{{MemberID: 1, Percent: 50}, {MemberID: 2, Percent: 30}, {MemberID: 3, Percent: 20}},
+		}}
+		shares, err := expense.MemberShares(members)
+		require.NoError(t, err)
+		assert.Equal(t, map[int64]int64{1: 50000, 2: 30000, 3: 20000}, shares)
+	})
+
+	t.Run("Invalid splits", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			split ExpenseSplit
+		}{
+			{"Exact amounts short of the total", ExpenseSplit{Mode: SplitExact, Shares: []SplitShare{{MemberID: 1, Amount: 400}}}},
+			{"Percentages not adding to 100", ExpenseSplit{Mode: SplitPercent, Shares: []SplitShare{{MemberID: 1, Percent: 60}, {MemberID: 2, Percent: 30}}}},
+			{"Unknown member", ExpenseSplit{Mode: SplitEqual, Shares: []SplitShare{{MemberID: 9}}}},
+			{"Member listed twice", ExpenseSplit{Mode: SplitEqual, Shares: []SplitShare{{MemberID: 1}, {MemberID: 1}}}},
+			{"Unknown mode", ExpenseSplit{Mode: "shares"}},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				split := tt.split
+				expense := Expense{Amount: 500, AmountTHB: 500, PaidBy: 1, Split: &split}
+				_, err := expense.MemberShares(members)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestSettleGroup(t *testing.T) {
+	members := testMembers()
+	expenses := []Expense{
+		{ID: 1, Category: ExpenseHotel, Amount: 3000, AmountTHB: 3000, PaidBy: 1},
+		{ID: 2, Category: ExpenseFood, Amount: 600, AmountTHB: 600, PaidBy: 2, Split: &ExpenseSplit{
+			Mode:   SplitExact,
+			Shares: []SplitShare{{MemberID: 1, Amount: 100}, {MemberID: 2, Amount: 200}, {MemberID: 3, Amount: 300}},
+		}},
+		{ID: 3, Category: ExpenseMisc, Amount: 250, AmountTHB: 250, Description: "Souvenirs"},
+	}
+	budget := &TripBudget{TripID: 7, Total: 9000}
+
+	settlement, err := SettleGroup(7, members, budget, expenses)
+	require.NoError(t, err)
+
+	require.Len(t, settlement.Balances, 3)
+	alice := settlement.Balances[0]
+	assert.Equal(t, 3000.0, alice.Paid)
+	assert.Equal(t, 1100.0, alice.Share)
+	assert.Equal(t, 1900.0, alice.Net)
+	assert.Equal(t, 1000.0, alice.ShareByCategory[ExpenseHotel])
+	assert.Equal(t, 100.0, alice.ShareByCategory[ExpenseFood])
+	assert.Equal(t, 3000.0, alice.Budget)
+	assert.Equal(t, -600.0, settlement.Balances[1].Net)
+	assert.Equal(t, -1300.0, settlement.Balances[2].Net, "Expenses without a payer are personal")
+
+	assert.Equal(t, []Transfer{
+		{From: 3, FromName: "Chai", To: 1, ToName: "Alice", Amount: 1300},
+		{From: 2, FromName: "Bob", To: 1, ToName: "Alice", Amount: 600},
+	}, settlement.Transfers)
+
+	t.Run("Payers must be members", func(t *testing.T) {
+		_, err := SettleGroup(7, members, nil, []Expense{{ID: 4, Amount: 100, AmountTHB: 100, PaidBy: 9}})
+		assert.Error(t, err)
+	})
+
+	t.Run("Nothing to settle", func(t *testing.T) {
+		settlement, err := SettleGroup(7, members, nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, settlement.Transfers)
+		assert.Zero(t, settlement.Balances[0].Budget)
+	})
+}
+
+func TestMinimizeTransfers(t *testing.T) {
+	ids := []int64{1, 2, 3, 4, 5}
+	nets := []int64{500, 400, -300, -200, -400}
+
+	// Paying the largest balances first takes four transfers; {2, 5} and {1, 3, 4} cancel out on
+	// their own and take three
+	assert.Len(t, settleGreedily(ids, nets), 4)
+
+	transfers := minimizeTransfers(ids, nets)
+	require.Len(t, transfers, 3)
+
+	balances := make(map[int64]int64)
+	for _, transfer := range transfers {
+		balances[transfer.From] -= int64(transfer.Amount * 100)
+		balances[transfer.To] += int64(transfer.Amount * 100)
+	}
+	for i, id := range ids {
+		assert.Equal(t, nets[i], balances[id], "member %d", id)
+	}
+
+	assert.Empty(t, minimizeTransfers(nil, nil))
+}
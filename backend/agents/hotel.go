@@ -2,33 +2,60 @@ package agents
 
 import (
 	"context"
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"math/rand"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 )
 
 var ctx = context.Background()
 
+// hotelPriceCacheTTL is how long GetHotelPrice results are cached
+const hotelPriceCacheTTL = 24 * time.Hour
+
+// hotelCachePolicy keeps provider availability fresh for 15 minutes, serves it for up to an
+// hour more while refreshing, and backs off a failing provider for a minute
+var hotelCachePolicy = cache.Policy{Fresh: 15 * time.Minute, Stale: time.Hour, Negative: time.Minute}
+
 // HotelRecommendation represents a hotel search result
 type HotelRecommendation struct {
-	Name          string  `json:"name"`
-	PricePerNight float64 `json:"price_per_night"`
-	Rating        float64 `json:"rating"`
-	Address       string  `json:"address"`
-	Distance      float64 `json:"distance_km"`
+	Name          string   `json:"name"`
+	PricePerNight float64  `json:"price_per_night"`
+	Rating        float64  `json:"rating"`
+	Address       string   `json:"address"`
+	Distance      float64  `json:"distance_km"`
+	TotalPrice    float64  `json:"total_price,omitempty"`
+	Nights        int      `json:"nights,omitempty"`
+	Currency      string   `json:"currency,omitempty"`
+	Stars         int      `json:"stars,omitempty"`
+	RoomType      string   `json:"room_type,omitempty"`
+	Amenities     []string `json:"amenities,omitempty"`
+	Refundable    bool     `json:"refundable"`
+	Neighborhood  string   `json:"neighborhood,omitempty"`
+	Latitude      float64  `json:"latitude,omitempty"`
+	Longitude     float64  `json:"longitude,omitempty"`
+	Source        string   `json:"source,omitempty"`
 }
 
 // HotelAgent handles hotel search and recommendations
 type HotelAgent struct {
-	client *openai.Client
-	apiKey string
+	client    *openai.Client
+	apiKey    string
+	provider  HotelProvider
+	cache     cache.Cache
+	loader    *cache.Loader
+	converter *currency.Converter
 }
 
 // NewHotelAgent creates a new hotel agent
@@ -38,40 +65,149 @@ func NewHotelAgent(openaiKey, hotelKey string) *HotelAgent {
 		client = openai.NewClient(openaiKey)
 	}
 	return &HotelAgent{
-		client: client,
-		apiKey: hotelKey,
+		client:    client,
+		apiKey:    hotelKey,
+		converter: currency.NewConverter(currency.DefaultRates()),
 	}
 }
 
-// SearchHotels searches for hotels within budget
+// SetProvider sets the hotel inventory used before falling back to LLM or estimated hotels
+func (a *HotelAgent) SetProvider(provider HotelProvider) {
+	a.provider = provider
+}
+
+// SetRateProvider changes the exchange rates used to compare offers priced in other currencies
+func (a *HotelAgent) SetRateProvider(provider currency.RateProvider) {
+	a.converter = currency.NewConverter(provider)
+}
+
+// SetCache makes the agent share provider results between requests for the same search
+func (a *HotelAgent) SetCache(c cache.Cache) {
+	a.cache = c
+	a.loader = cache.NewLoader("hotels", c, hotelCachePolicy)
+}
+
+// CacheStats reports how provider lookups were served
+func (a *HotelAgent) CacheStats() cache.Stats {
+	return a.loader.Stats()
+}
+
+// SearchHotels searches for one night's stay in a destination, preferring hotels within the nightly budget
 func (a *HotelAgent) SearchHotels(ctx context.Context, destination string, budget float64) ([]HotelRecommendation, error) {
+	return a.Search(ctx, HotelSearchRequest{City: destination, BudgetPerNight: budget})
+}
+
+// Search finds hotels for the requested dates and occupancy that pass the filters.
+// A configured provider is tried first, then the LLM, then estimated hotels.
+func (a *HotelAgent) Search(ctx context.Context, req HotelSearchRequest) ([]HotelRecommendation, error) {
+	req = req.WithDefaults()
+
+	// Use real availability when a provider is configured
+	if a.provider != nil {
+		recommendations, err := a.searchWithProvider(ctx, req)
+		if err != nil {
+			log.Printf("HotelAgent: %s provider failed: %v", a.provider.Name(), err)
+		} else if len(recommendations) > 0 {
+			log.Printf("HotelAgent: Found %d available hotels in %s via %s",
+				len(recommendations), req.City, a.provider.Name())
+			return recommendations, nil
+		}
+	}
+
 	// Use LLM to generate realistic hotel recommendations
 	if a.client != nil {
-		recommendations, err := a.searchWithLLM(ctx, destination, budget)
+		recommendations, err := a.searchWithLLM(ctx, req)
+		if err == nil {
+			recommendations = FilterHotels(recommendations, req)
+		}
 		if err == nil && len(recommendations) > 0 {
-			log.Printf("HotelAgent: Found %d hotels in %s within budget %.0f THB", 
-				len(recommendations), destination, budget)
+			log.Printf("HotelAgent: Found %d hotels in %s within budget %.0f THB",
+				len(recommendations), req.City, req.BudgetPerNight)
 			return recommendations, nil
 		}
 	}
 
-	// Fallback to estimated hotels
-	recommendations := a.estimateHotels(destination, budget)
-	log.Printf("HotelAgent: Generated %d estimated hotels for %s", len(recommendations), destination)
+	// Fallback to estimated hotels; they are typical city prices, so the budget preference does not apply
+	estimateReq := req
+	estimateReq.BudgetPerNight = 0
+	recommendations := FilterHotels(a.estimateHotels(req), estimateReq)
+	log.Printf("HotelAgent: Generated %d estimated hotels for %s", len(recommendations), req.City)
 	return recommendations, nil
 }
 
+// searchWithProvider returns the provider's available offers that pass the filters
+func (a *HotelAgent) searchWithProvider(ctx context.Context, req HotelSearchRequest) ([]HotelRecommendation, error) {
+	var offers []HotelOffer
+	key := hotelSearchCacheKey(a.provider.Name(), req)
+	err := a.loader.Load(ctx, key, &offers, func(ctx context.Context) (interface{}, error) {
+		return a.provider.SearchHotels(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	available := make([]HotelRecommendation, 0, len(offers))
+	for _, offer := range offers {
+		if offer.Available {
+			available = append(available, a.priceIn(ctx, offer.HotelRecommendation, req.Currency))
+		}
+	}
+	return FilterHotels(available, req), nil
+}
+
+// priceIn converts a hotel's prices into the given currency so offers can be compared with the budget;
+// prices without an exchange rate are left in their own currency
+func (a *HotelAgent) priceIn(ctx context.Context, hotel HotelRecommendation, code string) HotelRecommendation {
+	if hotel.Currency == "" || hotel.Currency == code || a.converter == nil {
+		return hotel
+	}
+	perNight, err := a.converter.Convert(ctx, currency.New(hotel.PricePerNight, hotel.Currency), code)
+	if err != nil {
+		log.Printf("HotelAgent: Cannot convert %s prices for %s: %v", hotel.Currency, hotel.Name, err)
+		return hotel
+	}
+	total, err := a.converter.Convert(ctx, currency.New(hotel.TotalPrice, hotel.Currency), code)
+	if err != nil {
+		return hotel
+	}
+	hotel.PricePerNight = perNight.Round().Amount
+	hotel.TotalPrice = total.Round().Amount
+	hotel.Currency = code
+	return hotel
+}
+
 // searchWithLLM uses LLM to generate hotel recommendations
-func (a *HotelAgent) searchWithLLM(ctx context.Context, destination string, budget float64) ([]HotelRecommendation, error) {
-	prompt := fmt.Sprintf(`You are HotelAgent. Search for 3 hotels in %s with nightly rate around %.0f THB per night.
+func (a *HotelAgent) searchWithLLM(ctx context.Context, req HotelSearchRequest) ([]HotelRecommendation, error) {
+	var wishes []string
+	if req.MinStars > 0 {
+		wishes = append(wishes, fmt.Sprintf("at least %d stars", req.MinStars))
+	}
+	if len(req.Amenities) > 0 {
+		wishes = append(wishes, "amenities: "+strings.Join(req.Amenities, ", "))
+	}
+	if req.Neighborhood != "" {
+		wishes = append(wishes, "in the "+req.Neighborhood+" area")
+	}
+	if req.RefundableOnly {
+		wishes = append(wishes, "free cancellation")
+	}
+	if len(wishes) == 0 {
+		wishes = append(wishes, "none")
+	}
+
+	prompt := fmt.Sprintf(`You are HotelAgent. Search for 5 hotels in %s with nightly rate around %.0f THB per night.
+Stay: %s to %s (%d nights), %d guests, %d rooms.
+Requirements: %s.
 
 Return ONLY valid JSON array:
 [
-  {"name": "Hotel Name", "price_per_night": 2500.0, "rating": 4.5, "address": "Full address", "distance_km": 1.5},
+  {"name": "Hotel Name", "price_per_night": 2500.0, "rating": 4.5, "stars": 4, "address": "Full address", "neighborhood": "Area", "distance_km": 1.5, "room_type": "Deluxe Double", "amenities": ["wifi", "pool"], "refundable": true, "latitude": 0.0, "longitude": 0.0},
   ...
 ]
 
-Generate realistic hotel names, addresses, and ratings for %s.`, destination, budget, destination)
+Generate realistic hotel names, addresses, and ratings for %s.`,
+		req.City, req.BudgetPerNight, req.CheckIn.Format("2006-01-02"), req.CheckOut.Format("2006-01-02"),
+		req.Nights(), req.Guests, req.Rooms, strings.Join(wishes, "; "), req.City)
 
 	resp, err := a.client.CreateChatCompletion(
 		ctx,
@@ -108,40 +244,130 @@ Generate realistic hotel names, addresses, and ratings for %s.`, destination, bu
 		return nil, err
 	}
 
+	for i := range recommendations {
+		recommendations[i].Nights = req.Nights()
+		recommendations[i].TotalPrice = recommendations[i].PricePerNight * float64(req.Nights()*req.Rooms)
+		recommendations[i].Currency = "THB"
+		recommendations[i].Source = "llm"
+	}
+
 	return recommendations, nil
 }
 
-// estimateHotels generates estimated hotel recommendations
-func (a *HotelAgent) estimateHotels(destination string, budget float64) []HotelRecommendation {
+// estimateHotels generates estimated hotel recommendations that satisfy the request's filters
+func (a *HotelAgent) estimateHotels(req HotelSearchRequest) []HotelRecommendation {
 	rand.Seed(time.Now().UnixNano())
-	
-	basePricePerNight := estimateHotelPricePerNight(destination)
-	
+
+	basePricePerNight := estimateHotelPricePerNight(req.City)
+	nights := req.Nights()
+
+	cityLat, cityLng, located := airports.Coordinates(req.City)
+
 	recommendations := make([]HotelRecommendation, 3)
 	for i := 0; i < 3; i++ {
 		variance := 0.8 + rand.Float64()*0.4 // 0.8 to 1.2
-		pricePerNight := float64(basePricePerNight) * variance
-		
+		stars := 3 + i%2
+		if stars < req.MinStars {
+			stars = req.MinStars
+		}
+		// Higher star ratings cost more
+		pricePerNight := float64(basePricePerNight) * variance * (1 + 0.5*float64(stars-3))
+
+		rating := 4.0 + rand.Float64()*1.0
+		if rating < req.MinRating {
+			rating = req.MinRating
+		}
+
+		neighborhood := req.Neighborhood
+		if neighborhood == "" {
+			neighborhood = "City Center"
+		}
+
 		recommendations[i] = HotelRecommendation{
-			Name:          estimateHotelName(destination),
+			Name:          estimateHotelName(req.City),
 			PricePerNight: pricePerNight,
-			Rating:        4.0 + rand.Float64()*1.0,
-			Address:       fmt.Sprintf("%s City Center", destination),
+			Rating:        rating,
+			Address:       fmt.Sprintf("%s, %s", neighborhood, req.City),
 			Distance:      0.5 + rand.Float64()*3.0,
+			TotalPrice:    pricePerNight * float64(nights*req.Rooms),
+			Nights:        nights,
+			Currency:      "THB",
+			Stars:         stars,
+			RoomType:      estimateRoomType(req.Guests, req.Rooms),
+			Amenities:     estimateAmenities(stars, req.Amenities),
+			Refundable:    req.RefundableOnly || i != 1,
+			Neighborhood:  neighborhood,
+			Source:        "estimate",
+		}
+
+		// Place the hotel its distance from the city centre, spread around the compass
+		if located {
+			bearing := float64(i) * 2 * math.Pi / 3
+			km := recommendations[i].Distance
+			recommendations[i].Latitude = cityLat + km/111.0*math.Cos(bearing)
+			recommendations[i].Longitude = cityLng + km/(111.0*math.Cos(cityLat*math.Pi/180))*math.Sin(bearing)
 		}
 	}
 
 	return recommendations
 }
 
+// estimateRoomType picks a room type that fits the guests per room
+func estimateRoomType(guests, rooms int) string {
+	if rooms < 1 {
+		rooms = 1
+	}
+	switch perRoom := (guests + rooms - 1) / rooms; {
+	case perRoom <= 1:
+		return "Standard Single"
+	case perRoom == 2:
+		return "Standard Double"
+	case perRoom == 3:
+		return "Triple Room"
+	}
+	return "Family Room"
+}
+
+// estimateAmenities lists typical amenities for the star rating plus any requested ones
+func estimateAmenities(stars int, requested []string) []string {
+	amenities := []string{"wifi", "air conditioning"}
+	if stars >= 4 {
+		amenities = append(amenities, "breakfast", "gym", "pool")
+	}
+	if stars >= 5 {
+		amenities = append(amenities, "spa")
+	}
+
+	for _, amenity := range requested {
+		amenity = strings.ToLower(strings.TrimSpace(amenity))
+		if amenity != "" && !(HotelRecommendation{Amenities: amenities}).HasAmenity(amenity) {
+			amenities = append(amenities, amenity)
+		}
+	}
+	return amenities
+}
+
 // GetHotelPrice searches for affordable hotel prices in a city (Legacy function)
 // Parameters:
 //   - city: Destination city name (e.g., "Vancouver", "Tokyo")
 //   - nights: Number of nights to stay
+//
 // Returns:
 //   - price: Total hotel price in THB for the entire stay
 //   - name: Name of the hotel
+//
+// Results are not cached; use HotelAgent.GetHotelPrice to cache them.
 func GetHotelPrice(city string, nights int) (price int, name string) {
+	return getHotelPrice(ctx, nil, city, nights)
+}
+
+// GetHotelPrice works like the package-level GetHotelPrice and caches results for 24 hours
+func (a *HotelAgent) GetHotelPrice(ctx context.Context, city string, nights int) (price int, name string) {
+	return getHotelPrice(ctx, a.cache, city, nights)
+}
+
+// getHotelPrice looks up a stay in the cache (when one is given), the hotel API, then estimates
+func getHotelPrice(ctx context.Context, c cache.Cache, city string, nights int) (price int, name string) {
 	// Default values
 	defaultPricePerNight := 2500
 	defaultName := "Budget Hotel"
@@ -151,8 +377,8 @@ func GetHotelPrice(city string, nights int) (price int, name string) {
 		return defaultPricePerNight * max(1, nights), defaultName
 	}
 
-	// Try to get from Redis cache first
-	cachedPrice, cachedName := getCachedHotelPrice(city, nights)
+	// Try the cache first
+	cachedPrice, cachedName := getCachedHotelPrice(ctx, c, city, nights)
 	if cachedPrice > 0 {
 		log.Printf("Cache hit for hotel in %s: %s for %d THB", city, cachedName, cachedPrice)
 		return cachedPrice, cachedName
@@ -164,7 +390,7 @@ func GetHotelPrice(city string, nights int) (price int, name string) {
 		price, name = searchHotelAPI(city, nights, apiKey)
 		if price > 0 {
 			// Cache the result
-			cacheHotelPrice(city, nights, price, name)
+			cacheHotelPrice(ctx, c, city, nights, price, name)
 			log.Printf("API result for hotel in %s: %s for %d THB", city, name, price)
 			return price, name
 		}
@@ -176,7 +402,7 @@ func GetHotelPrice(city string, nights int) (price int, name string) {
 	hotelName := estimateHotelName(city)
 
 	// Cache the estimated result
-	cacheHotelPrice(city, nights, totalPrice, hotelName)
+	cacheHotelPrice(ctx, c, city, nights, totalPrice, hotelName)
 
 	log.Printf("Estimated hotel in %s: %s for %d THB (%d nights x %d THB/night)",
 		city, hotelName, totalPrice, nights, pricePerNight)
@@ -184,83 +410,33 @@ func GetHotelPrice(city string, nights int) (price int, name string) {
 	return totalPrice, hotelName
 }
 
-// getCachedHotelPrice retrieves hotel price from Redis cache
-func getCachedHotelPrice(city string, nights int) (price int, name string) {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
-		DB:   0,
-	})
-	defer rdb.Close()
-
-	// Test connection
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Printf("Redis connection failed: %v", err)
-		return 0, ""
-	}
-
-	// Cache key format: hotel:<city>:<nights>
-	cacheKey := fmt.Sprintf("hotel:%s:%d", strings.ToLower(city), nights)
-
-	val, err := rdb.Get(ctx, cacheKey).Result()
-	if err == redis.Nil {
-		// Cache miss
-		return 0, ""
-	} else if err != nil {
-		log.Printf("Redis get error: %v", err)
+// getCachedHotelPrice retrieves a hotel price from the cache
+func getCachedHotelPrice(ctx context.Context, c cache.Cache, city string, nights int) (price int, name string) {
+	if c == nil {
 		return 0, ""
 	}
 
-	// Parse cached JSON
 	var cached struct {
 		Price int    `json:"price"`
 		Name  string `json:"name"`
 	}
-
-	if err := json.Unmarshal([]byte(val), &cached); err != nil {
-		log.Printf("Failed to parse cached hotel data: %v", err)
+	err := c.GetJSON(ctx, hotelPriceCacheKey(city, nights), &cached)
+	if err == cache.ErrMiss {
+		return 0, ""
+	} else if err != nil {
+		log.Printf("Failed to read cached hotel data: %v", err)
 		return 0, ""
 	}
 
 	return cached.Price, cached.Name
 }
 
-// cacheHotelPrice stores hotel price in Redis cache
-func cacheHotelPrice(city string, nights, price int, name string) {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
-		DB:   0,
-	})
-	defer rdb.Close()
-
-	// Test connection
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Printf("Redis connection failed, skipping cache: %v", err)
+// cacheHotelPrice stores a hotel price in the cache
+func cacheHotelPrice(ctx context.Context, c cache.Cache, city string, nights, price int, name string) {
+	if c == nil {
 		return
 	}
 
-	// Cache key format: hotel:<city>:<nights>
-	cacheKey := fmt.Sprintf("hotel:%s:%d", strings.ToLower(city), nights)
-
 	data := struct {
 		Price int    `json:"price"`
 		Name  string `json:"name"`
@@ -269,16 +445,8 @@ func cacheHotelPrice(city string, nights, price int, name string) {
 		Name:  name,
 	}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Failed to marshal hotel data: %v", err)
-		return
-	}
-
-	// Cache for 24 hours
-	expiration := 24 * time.Hour
-
-	if err := rdb.Set(ctx, cacheKey, jsonData, expiration).Err(); err != nil {
+	cacheKey := hotelPriceCacheKey(city, nights)
+	if err := c.SetJSON(ctx, cacheKey, data, hotelPriceCacheTTL); err != nil {
 		log.Printf("Failed to cache hotel data: %v", err)
 		return
 	}
@@ -286,24 +454,49 @@ func cacheHotelPrice(city string, nights, price int, name string) {
 	log.Printf("Cached hotel data for %s (key: %s)", city, cacheKey)
 }
 
-// searchHotelAPI queries Booking.com or Trip.com API
+// hotelPriceCacheKey has the format hotel:<city>:<nights>
+func hotelPriceCacheKey(city string, nights int) string {
+	return fmt.Sprintf("hotel:%s:%d", strings.ToLower(city), nights)
+}
+
+// hotelSearchCacheKey identifies a provider search by a hash of the request with its defaults filled in
+func hotelSearchCacheKey(provider string, req HotelSearchRequest) string {
+	req.City = strings.ToLower(strings.TrimSpace(req.City))
+	data, _ := json.Marshal(req)
+	return fmt.Sprintf("hotels:%s:%x", provider, sha1.Sum(data))
+}
+
+// searchHotelAPI queries the hotel API at HOTEL_API_URL and returns the cheapest available THB stay
 func searchHotelAPI(city string, nights int, apiKey string) (price int, name string) {
-	// This is a placeholder for actual API integration
-	// In production, implement actual API calls to:
-	// - Booking.com Affiliate API
-	// - Trip.com Affiliate API
-	// - Agoda API
-	
-	log.Printf("Searching hotels in %s via API (not implemented yet)", city)
-	
-	// TODO: Implement actual API call
-	// Example structure:
-	// - Make HTTP request to Booking.com API
-	// - Parse response JSON
-	// - Extract cheapest hotel
-	// - Return price and name
-	
-	return 0, ""
+	provider := NewHTTPHotelProvider(config.HotelConfig{
+		APIKey: apiKey,
+		URL:    os.Getenv("HOTEL_API_URL"),
+	})
+	if provider == nil {
+		return 0, ""
+	}
+
+	checkIn := time.Now().AddDate(0, 0, 1)
+	offers, err := provider.SearchHotels(ctx, HotelSearchRequest{
+		City:     city,
+		CheckIn:  checkIn,
+		CheckOut: checkIn.AddDate(0, 0, nights),
+	})
+	if err != nil {
+		log.Printf("Hotel API search failed for %s: %v", city, err)
+		return 0, ""
+	}
+
+	for _, offer := range offers {
+		if !offer.Available || offer.Currency != "THB" || offer.TotalPrice <= 0 {
+			continue
+		}
+		if price == 0 || int(offer.TotalPrice) < price {
+			price = int(offer.TotalPrice)
+			name = offer.Name
+		}
+	}
+	return price, name
 }
 
 // estimateHotelPricePerNight returns estimated hotel price per night based on city
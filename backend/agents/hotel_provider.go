@@ -0,0 +1,134 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
+)
+
+// HotelOffer is a priced room offer returned by a hotel provider
+type HotelOffer struct {
+	HotelRecommendation
+	ID        string `json:"id"`
+	Available bool   `json:"available"`
+}
+
+// HotelProvider searches a hotel inventory for availability and prices
+type HotelProvider interface {
+	Name() string
+	SearchHotels(ctx context.Context, req HotelSearchRequest) ([]HotelOffer, error)
+}
+
+// HTTPHotelProvider queries a hotel search API over HTTP:
+// GET {URL}/hotels/search?city=&checkin=YYYY-MM-DD&checkout=YYYY-MM-DD&guests=&rooms=&currency=
+// plus optional stars, amenities, neighborhood and refundable filters
+type HTTPHotelProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// hotelSearchResponse is the body returned by the hotel search API
+type hotelSearchResponse struct {
+	Hotels []HotelOffer `json:"hotels"`
+}
+
+// NewHTTPHotelProvider creates a hotel provider for the configured API, or nil when no API key is set
+func NewHTTPHotelProvider(cfg config.HotelConfig) *HTTPHotelProvider {
+	if cfg.APIKey == "" || cfg.URL == "" {
+		log.Println("Warning: Hotel API not configured, using estimated hotels")
+		return nil
+	}
+
+	return &HTTPHotelProvider{
+		apiKey:  cfg.APIKey,
+		baseURL: strings.TrimRight(cfg.URL, "/"),
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+// Name implements HotelProvider
+func (p *HTTPHotelProvider) Name() string { return "http" }
+
+// SearchHotels implements HotelProvider
+func (p *HTTPHotelProvider) SearchHotels(ctx context.Context, req HotelSearchRequest) ([]HotelOffer, error) {
+	if req.City == "" {
+		return nil, fmt.Errorf("city is required")
+	}
+	req = req.WithDefaults()
+
+	params := url.Values{}
+	params.Add("city", req.City)
+	params.Add("checkin", req.CheckIn.Format("2006-01-02"))
+	params.Add("checkout", req.CheckOut.Format("2006-01-02"))
+	params.Add("guests", strconv.Itoa(req.Guests))
+	params.Add("rooms", strconv.Itoa(req.Rooms))
+	params.Add("currency", req.Currency)
+	if req.MinStars > 0 {
+		params.Add("stars", strconv.Itoa(req.MinStars))
+	}
+	if len(req.Amenities) > 0 {
+		params.Add("amenities", strings.Join(req.Amenities, ","))
+	}
+	if req.Neighborhood != "" {
+		params.Add("neighborhood", req.Neighborhood)
+	}
+	if req.RefundableOnly {
+		params.Add("refundable", "true")
+	}
+
+	apiURL := fmt.Sprintf("%s/hotels/search?%s", p.baseURL, params.Encode())
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build hotel request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch hotel data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("hotel API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var searchResp hotelSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
+		return nil, fmt.Errorf("failed to parse hotel response: %w", err)
+	}
+
+	// Fill in whichever price the API left out
+	nights := req.Nights()
+	for i := range searchResp.Hotels {
+		offer := &searchResp.Hotels[i]
+		offer.Nights = nights
+		offer.Source = p.Name()
+		if offer.TotalPrice == 0 && offer.PricePerNight > 0 {
+			offer.TotalPrice = offer.PricePerNight * float64(nights)
+		}
+		if offer.PricePerNight == 0 && offer.TotalPrice > 0 {
+			offer.PricePerNight = offer.TotalPrice / float64(nights)
+		}
+		if offer.Currency == "" {
+			offer.Currency = req.Currency
+		}
+	}
+
+	return searchResp.Hotels, nil
+}
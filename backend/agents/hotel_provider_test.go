@@ -0,0 +1,163 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeHotelAPI serves /hotels/search with the given offers and records the last query
+func newFakeHotelAPI(t *testing.T, offers []HotelOffer) (*httptest.Server, *http.Request) {
+	t.Helper()
+	last := &http.Request{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*last = *r
+		if r.URL.Path != "/hotels/search" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"hotels": offers})
+	}))
+	t.Cleanup(server.Close)
+	return server, last
+}
+
+func TestHTTPHotelProvider_SearchHotels(t *testing.T) {
+	server, last := newFakeHotelAPI(t, []HotelOffer{
+		{ID: "h1", Available: true, HotelRecommendation: HotelRecommendation{Name: "Shinjuku Stay", PricePerNight: 2000, Currency: "THB"}},
+		{ID: "h2", Available: false, HotelRecommendation: HotelRecommendation{Name: "Ginza Grand", TotalPrice: 15000}},
+	})
+	provider := NewHTTPHotelProvider(config.HotelConfig{APIKey: "test-key", URL: server.URL + "/"})
+	require.NotNil(t, provider)
+
+	offers, err := provider.SearchHotels(context.Background(), HotelSearchRequest{
+		City:     "Tokyo",
+		CheckIn:  date("2025-04-01"),
+		CheckOut: date("2025-04-04"),
+		Guests:   3,
+		Rooms:    2,
+	})
+	require.NoError(t, err)
+
+	query := last.URL.Query()
+	assert.Equal(t, "Tokyo", query.Get("city"))
+	assert.Equal(t, "2025-04-01", query.Get("checkin"))
+	assert.Equal(t, "2025-04-04", query.Get("checkout"))
+	assert.Equal(t, "3", query.Get("guests"))
+	assert.Equal(t, "2", query.Get("rooms"))
+	assert.Equal(t, "THB", query.Get("currency"))
+
+	require.Len(t, offers, 2)
+	assert.True(t, offers[0].Available)
+	assert.Equal(t, 6000.0, offers[0].TotalPrice, "Total should be filled in from the nightly price")
+	assert.False(t, offers[1].Available)
+	assert.Equal(t, 5000.0, offers[1].PricePerNight, "Nightly price should be filled in from the total")
+	assert.Equal(t, "THB", offers[1].Currency)
+}
+
+func TestHTTPHotelProvider_Errors(t *testing.T) {
+	server, _ := newFakeHotelAPI(t, nil)
+
+	t.Run("Not configured", func(t *testing.T) {
+		assert.Nil(t, NewHTTPHotelProvider(config.HotelConfig{URL: server.URL}))
+	})
+
+	t.Run("Rejected key", func(t *testing.T) {
+		provider := NewHTTPHotelProvider(config.HotelConfig{APIKey: "wrong", URL: server.URL})
+		_, err := provider.SearchHotels(context.Background(), HotelSearchRequest{City: "Tokyo"})
+		assert.ErrorContains(t, err, "status 401")
+	})
+
+	t.Run("Missing city", func(t *testing.T) {
+		provider := NewHTTPHotelProvider(config.HotelConfig{APIKey: "test-key", URL: server.URL})
+		_, err := provider.SearchHotels(context.Background(), HotelSearchRequest{})
+		assert.Error(t, err)
+	})
+}
+
+func TestHotelAgent_SearchHotelsWithProvider(t *testing.T) {
+	server, last := newFakeHotelAPI(t, []HotelOffer{
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Pricey Palace", PricePerNight: 9000, Currency: "THB"}},
+		{Available: false, HotelRecommendation: HotelRecommendation{Name: "Sold Out Inn", PricePerNight: 1000, Currency: "THB"}},
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Budget Box", PricePerNight: 1500, Currency: "THB"}},
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Mid Hotel", PricePerNight: 2500, Currency: "THB"}},
+	})
+
+	agent := NewHotelAgent("", "")
+	agent.SetProvider(NewHTTPHotelProvider(config.HotelConfig{APIKey: "test-key", URL: server.URL}))
+
+	hotels, err := agent.SearchHotels(context.Background(), "Tokyo", 3000)
+	require.NoError(t, err)
+	assert.Equal(t, "1", last.URL.Query().Get("rooms"))
+	require.Len(t, hotels, 2)
+	assert.Equal(t, "Budget Box", hotels[0].Name)
+	assert.Equal(t, "Mid Hotel", hotels[1].Name)
+	assert.Equal(t, "http", hotels[0].Source)
+	assert.Equal(t, 1500.0, hotels[0].TotalPrice)
+}
+
+func TestHotelAgent_ComparesOffersInOtherCurrencies(t *testing.T) {
+	server, _ := newFakeHotelAPI(t, []HotelOffer{
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Dollar Suites", PricePerNight: 200, Currency: "USD"}},
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Dollar Hostel", PricePerNight: 40, Currency: "USD"}},
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Baht Inn", PricePerNight: 2500, Currency: "THB"}},
+	})
+
+	agent := NewHotelAgent("", "")
+	agent.SetProvider(NewHTTPHotelProvider(config.HotelConfig{APIKey: "test-key", URL: server.URL}))
+	agent.SetRateProvider(currency.NewRates("THB", map[string]float64{"USD": 0.025}))
+
+	hotels, err := agent.SearchHotels(context.Background(), "Tokyo", 3000)
+	require.NoError(t, err)
+	require.Len(t, hotels, 2, "The 8000 THB suite is over budget")
+	assert.Equal(t, "Dollar Hostel", hotels[0].Name)
+	assert.Equal(t, 1600.0, hotels[0].PricePerNight)
+	assert.Equal(t, "THB", hotels[0].Currency)
+	assert.Equal(t, "Baht Inn", hotels[1].Name)
+}
+
+func TestHotelAgent_FallsBackWhenProviderFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	agent := NewHotelAgent("", "")
+	agent.SetProvider(NewHTTPHotelProvider(config.HotelConfig{APIKey: "test-key", URL: server.URL}))
+
+	hotels, err := agent.SearchHotels(context.Background(), "Tokyo", 3000)
+	require.NoError(t, err)
+	assert.Len(t, hotels, 3, "Should fall back to estimated hotels")
+	assert.Equal(t, "estimate", hotels[0].Source)
+}
+
+func TestGetHotelPrice_WithHotelAPI(t *testing.T) {
+	server, _ := newFakeHotelAPI(t, []HotelOffer{
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Cheap Inn", PricePerNight: 1200, Currency: "THB"}},
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Dollar Hotel", PricePerNight: 20, Currency: "USD"}},
+	})
+
+	os.Setenv("REDIS_HOST", "invalid-host-123456")
+	os.Setenv("HOTEL_API_KEY", "test-key")
+	os.Setenv("HOTEL_API_URL", server.URL)
+	defer os.Unsetenv("REDIS_HOST")
+	defer os.Unsetenv("HOTEL_API_KEY")
+	defer os.Unsetenv("HOTEL_API_URL")
+
+	price, name := GetHotelPrice("Bangkok", 3)
+	assert.Equal(t, 3600, price)
+	assert.Equal(t, "Cheap Inn", name)
+}
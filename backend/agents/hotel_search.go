@@ -0,0 +1,169 @@
+package agents
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Hotel sort orders
+const (
+	HotelSortPrice    = "price"
+	HotelSortRating   = "rating"
+	HotelSortDistance = "distance"
+	HotelSortStars    = "stars"
+)
+
+// defaultHotelResults is how many hotels a search returns when no limit is set
+const defaultHotelResults = 3
+
+// HotelSearchRequest describes the stay to search for and how to filter and sort the results
+type HotelSearchRequest struct {
+	City     string    `json:"city"`
+	CheckIn  time.Time `json:"check_in"`
+	CheckOut time.Time `json:"check_out"`
+	Guests   int       `json:"guests"`
+	Rooms    int       `json:"rooms"`
+	Currency string    `json:"currency"`
+	// BudgetPerNight is a soft limit: hotels within it are preferred, otherwise the cheapest are returned
+	BudgetPerNight float64 `json:"budget_per_night,omitempty"`
+
+	// Filters
+	MinStars       int      `json:"min_stars,omitempty"`
+	MinRating      float64  `json:"min_rating,omitempty"`
+	Amenities      []string `json:"amenities,omitempty"`
+	Neighborhood   string   `json:"neighborhood,omitempty"`
+	RefundableOnly bool     `json:"refundable_only,omitempty"`
+
+	SortBy string `json:"sort_by,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// Nights returns the number of nights between check-in and check-out (at least 1)
+func (r HotelSearchRequest) Nights() int {
+	nights := int(r.CheckOut.Sub(r.CheckIn).Hours() / 24)
+	if nights < 1 {
+		return 1
+	}
+	return nights
+}
+
+// WithDefaults fills in tomorrow's check-in, one night, two guests, enough rooms and THB
+func (r HotelSearchRequest) WithDefaults() HotelSearchRequest {
+	if r.CheckIn.IsZero() {
+		r.CheckIn = time.Now().AddDate(0, 0, 1)
+	}
+	r.CheckIn = time.Date(r.CheckIn.Year(), r.CheckIn.Month(), r.CheckIn.Day(), 0, 0, 0, 0, time.UTC)
+	if r.CheckOut.IsZero() || !r.CheckOut.After(r.CheckIn) {
+		r.CheckOut = r.CheckIn.AddDate(0, 0, 1)
+	}
+	r.CheckOut = time.Date(r.CheckOut.Year(), r.CheckOut.Month(), r.CheckOut.Day(), 0, 0, 0, 0, time.UTC)
+	if r.Guests <= 0 {
+		r.Guests = 2
+	}
+	if r.Rooms <= 0 {
+		// Two guests per room
+		r.Rooms = (r.Guests + 1) / 2
+	}
+	if r.Currency == "" {
+		r.Currency = "THB"
+	}
+	if r.SortBy == "" {
+		r.SortBy = HotelSortPrice
+	}
+	if r.Limit <= 0 {
+		r.Limit = defaultHotelResults
+	}
+	return r
+}
+
+// Matches reports whether a hotel passes the request's filters
+func (r HotelSearchRequest) Matches(hotel HotelRecommendation) bool {
+	if r.MinStars > 0 && hotel.Stars < r.MinStars {
+		return false
+	}
+	if r.MinRating > 0 && hotel.Rating < r.MinRating {
+		return false
+	}
+	if r.RefundableOnly && !hotel.Refundable {
+		return false
+	}
+	if r.Neighborhood != "" && !strings.Contains(strings.ToLower(hotel.Neighborhood+" "+hotel.Address), strings.ToLower(r.Neighborhood)) {
+		return false
+	}
+	for _, amenity := range r.Amenities {
+		if !hotel.HasAmenity(amenity) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAmenity reports whether the hotel lists an amenity (case-insensitive)
+func (h HotelRecommendation) HasAmenity(amenity string) bool {
+	amenity = strings.ToLower(strings.TrimSpace(amenity))
+	for _, have := range h.Amenities {
+		if strings.ToLower(have) == amenity {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterHotels applies the filters, prefers hotels within the nightly budget, sorts and trims the list
+func FilterHotels(hotels []HotelRecommendation, req HotelSearchRequest) []HotelRecommendation {
+	req = req.WithDefaults()
+
+	var withinBudget, overBudget []HotelRecommendation
+	for _, hotel := range hotels {
+		if !req.Matches(hotel) {
+			continue
+		}
+		comparable := hotel.Currency == "" || hotel.Currency == req.Currency
+		if req.BudgetPerNight <= 0 || !comparable || hotel.PricePerNight <= req.BudgetPerNight {
+			withinBudget = append(withinBudget, hotel)
+		} else {
+			overBudget = append(overBudget, hotel)
+		}
+	}
+
+	filtered := withinBudget
+	if len(filtered) == 0 {
+		filtered = overBudget
+	}
+
+	SortHotels(filtered, req.SortBy)
+	if len(filtered) > req.Limit {
+		filtered = filtered[:req.Limit]
+	}
+	return filtered
+}
+
+// SortHotels orders hotels by price (cheapest first), rating or stars (best first) or distance (closest first)
+func SortHotels(hotels []HotelRecommendation, sortBy string) {
+	sort.SliceStable(hotels, func(i, j int) bool {
+		switch sortBy {
+		case HotelSortRating:
+			return hotels[i].Rating > hotels[j].Rating
+		case HotelSortDistance:
+			return hotels[i].Distance < hotels[j].Distance
+		case HotelSortStars:
+			return hotels[i].Stars > hotels[j].Stars
+		}
+		return hotels[i].PricePerNight < hotels[j].PricePerNight
+	})
+}
+
+// NormalizeHotelSort maps free text such as "cheapest" or "best rated" to a sort order (default price)
+func NormalizeHotelSort(sortBy string) string {
+	lower := strings.ToLower(strings.TrimSpace(sortBy))
+	switch {
+	case strings.Contains(lower, "rating") || strings.Contains(lower, "rated") || strings.Contains(lower, "review"):
+		return HotelSortRating
+	case strings.Contains(lower, "distance") || strings.Contains(lower, "closest") || strings.Contains(lower, "near"):
+		return HotelSortDistance
+	case strings.Contains(lower, "star") || strings.Contains(lower, "luxury"):
+		return HotelSortStars
+	}
+	return HotelSortPrice
+}
@@ -0,0 +1,197 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testHotels() []HotelRecommendation {
+	return []HotelRecommendation{
+		{Name: "Capsule", PricePerNight: 800, Rating: 4.1, Stars: 2, Distance: 0.5, Amenities: []string{"wifi"}, Neighborhood: "Shinjuku"},
+		{Name: "Business Inn", PricePerNight: 2200, Rating: 4.3, Stars: 3, Distance: 2.0, Amenities: []string{"wifi", "breakfast"}, Refundable: true, Neighborhood: "Shinjuku"},
+		{Name: "Grand Resort", PricePerNight: 6500, Rating: 4.8, Stars: 5, Distance: 5.0, Amenities: []string{"WiFi", "Pool", "spa"}, Refundable: true, Neighborhood: "Odaiba"},
+		{Name: "Garden Hotel", PricePerNight: 3800, Rating: 4.6, Stars: 4, Distance: 1.2, Amenities: []string{"wifi", "pool", "gym"}, Neighborhood: "Shibuya"},
+	}
+}
+
+func names(hotels []HotelRecommendation) []string {
+	result := make([]string, len(hotels))
+	for i, hotel := range hotels {
+		result[i] = hotel.Name
+	}
+	return result
+}
+
+func TestFilterHotels(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      HotelSearchRequest
+		expected []string
+	}{
+		{
+			name:     "Cheapest first by default",
+			req:      HotelSearchRequest{},
+			expected: []string{"Capsule", "Business Inn", "Garden Hotel"},
+		},
+		{
+			name:     "Minimum stars",
+			req:      HotelSearchRequest{MinStars: 4},
+			expected: []string{"Garden Hotel", "Grand Resort"},
+		},
+		{
+			name:     "Amenities are case-insensitive",
+			req:      HotelSearchRequest{Amenities: []string{"pool"}, SortBy: HotelSortRating},
+			expected: []string{"Grand Resort", "Garden Hotel"},
+		},
+		{
+			name:     "Refundable in a neighborhood",
+			req:      HotelSearchRequest{RefundableOnly: true, Neighborhood: "shinjuku"},
+			expected: []string{"Business Inn"},
+		},
+		{
+			name:     "Within budget preferred",
+			req:      HotelSearchRequest{BudgetPerNight: 4000, SortBy: HotelSortDistance, Limit: 5},
+			expected: []string{"Capsule", "Garden Hotel", "Business Inn"},
+		},
+		{
+			name:     "Cheapest over budget when nothing fits",
+			req:      HotelSearchRequest{BudgetPerNight: 1000, MinStars: 4},
+			expected: []string{"Garden Hotel", "Grand Resort"},
+		},
+		{
+			name:     "Nothing matches",
+			req:      HotelSearchRequest{Amenities: []string{"onsen"}},
+			expected: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, names(FilterHotels(testHotels(), tt.req)))
+		})
+	}
+}
+
+func TestNormalizeHotelSort(t *testing.T) {
+	assert.Equal(t, HotelSortPrice, NormalizeHotelSort(""))
+	assert.Equal(t, HotelSortPrice, NormalizeHotelSort("cheapest"))
+	assert.Equal(t, HotelSortRating, NormalizeHotelSort("best rated"))
+	assert.Equal(t, HotelSortDistance, NormalizeHotelSort("closest to the station"))
+	assert.Equal(t, HotelSortStars, NormalizeHotelSort("stars"))
+}
+
+func TestHotelSearchRequest_Defaults(t *testing.T) {
+	req := HotelSearchRequest{Guests: 5, CheckIn: date("2025-05-01"), CheckOut: date("2025-05-04")}.WithDefaults()
+	assert.Equal(t, 3, req.Rooms, "Two guests per room")
+	assert.Equal(t, 3, req.Nights())
+	assert.Equal(t, "THB", req.Currency)
+
+	req = HotelSearchRequest{CheckIn: date("2025-05-01")}.WithDefaults()
+	assert.Equal(t, 2, req.Guests)
+	assert.Equal(t, 1, req.Rooms)
+	assert.Equal(t, date("2025-05-02"), req.CheckOut)
+}
+
+func TestHotelAgent_SearchEstimatesMatchFilters(t *testing.T) {
+	agent := NewHotelAgent("", "")
+
+	hotels, err := agent.Search(context.Background(), HotelSearchRequest{
+		City:           "Tokyo",
+		CheckIn:        date("2025-05-01"),
+		CheckOut:       date("2025-05-04"),
+		Guests:         3,
+		MinStars:       4,
+		Amenities:      []string{"onsen"},
+		Neighborhood:   "Shinjuku",
+		RefundableOnly: true,
+	})
+	require.NoError(t, err)
+	require.Len(t, hotels, 3)
+
+	for _, hotel := range hotels {
+		assert.GreaterOrEqual(t, hotel.Stars, 4)
+		assert.True(t, hotel.HasAmenity("onsen"))
+		assert.True(t, hotel.Refundable)
+		assert.Equal(t, "Shinjuku", hotel.Neighborhood)
+		assert.Equal(t, 3, hotel.Nights)
+		assert.Equal(t, "Standard Double", hotel.RoomType)
+		assert.InDelta(t, hotel.PricePerNight*3*2, hotel.TotalPrice, 0.01, "Three nights in two rooms")
+	}
+	assert.LessOrEqual(t, hotels[0].PricePerNight, hotels[1].PricePerNight)
+}
+
+func TestHotelAgent_EstimatesAreNearTheCity(t *testing.T) {
+	agent := NewHotelAgent("", "")
+
+	hotels, err := agent.Search(context.Background(), HotelSearchRequest{City: "เชียงใหม่"})
+	require.NoError(t, err)
+	require.NotEmpty(t, hotels)
+
+	for _, hotel := range hotels {
+		// Within a few kilometres of Chiang Mai's old city
+		assert.InDelta(t, 18.79, hotel.Latitude, 0.05, hotel.Name)
+		assert.InDelta(t, 98.99, hotel.Longitude, 0.05, hotel.Name)
+	}
+}
+
+// countingHotelProvider returns fixed offers and counts searches
+type countingHotelProvider struct {
+	searches int
+}
+
+func (p *countingHotelProvider) Name() string { return "counting" }
+
+func (p *countingHotelProvider) SearchHotels(ctx context.Context, req HotelSearchRequest) ([]HotelOffer, error) {
+	p.searches++
+	return []HotelOffer{
+		{Available: true, HotelRecommendation: HotelRecommendation{Name: "Budget Box", PricePerNight: 1500, Currency: "THB"}},
+	}, nil
+}
+
+func TestHotelAgent_SearchUsesCache(t *testing.T) {
+	provider := &countingHotelProvider{}
+	agent := NewHotelAgent("", "")
+	agent.SetProvider(provider)
+	agent.SetCache(cache.NewMemoryCache())
+
+	req := HotelSearchRequest{City: "Tokyo", CheckIn: date("2025-05-01"), CheckOut: date("2025-05-03")}
+	first, err := agent.Search(context.Background(), req)
+	require.NoError(t, err)
+
+	req.City = " tokyo "
+	second, err := agent.Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, provider.searches, "The same search is served from the cache")
+
+	req.CheckOut = date("2025-05-04")
+	_, err = agent.Search(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, 2, provider.searches, "Different dates are a different search")
+	assert.Equal(t, int64(1), agent.CacheStats().Hits)
+}
+
+func TestHotelAgent_GetHotelPriceUsesCache(t *testing.T) {
+	c := cache.NewMemoryCache()
+	agent := NewHotelAgent("", "")
+	agent.SetCache(c)
+
+	price, name := agent.GetHotelPrice(context.Background(), "Vancouver", 2)
+	assert.Equal(t, 5000, price)
+
+	var cached struct {
+		Price int    `json:"price"`
+		Name  string `json:"name"`
+	}
+	require.NoError(t, c.GetJSON(context.Background(), "hotel:vancouver:2", &cached))
+	assert.Equal(t, name, cached.Name)
+
+	require.NoError(t, c.SetJSON(context.Background(), "hotel:vancouver:2", map[string]interface{}{"price": 4200, "name": "Cached Inn"}, 0))
+	price, name = agent.GetHotelPrice(context.Background(), "Vancouver", 2)
+	assert.Equal(t, 4200, price)
+	assert.Equal(t, "Cached Inn", name)
+}
@@ -6,16 +6,22 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 )
 
 // IntentResult represents the detected intent and extracted entities
 type IntentResult struct {
 	Intent   string                 `json:"intent"`
 	Entities map[string]interface{} `json:"entities"`
+	// Message is the user's original text, set by the orchestrator before dispatch
+	Message string `json:"-"`
 }
 
 // IntentAgent handles intent detection and entity extraction
@@ -35,6 +41,12 @@ func NewIntentAgent(apiKey string) *IntentAgent {
 
 // Detect analyzes user input and classifies intent with entity extraction
 func (a *IntentAgent) Detect(ctx context.Context, userInput string) (*IntentResult, error) {
+	return a.DetectWithContext(ctx, userInput, nil)
+}
+
+// DetectWithContext classifies user input like Detect, but also gives the model
+// the previous turn of the conversation so follow-ups can be resolved
+func (a *IntentAgent) DetectWithContext(ctx context.Context, userInput string, previous *IntentResult) (*IntentResult, error) {
 	if a.client == nil {
 		log.Println("IntentAgent: OpenAI client not initialized, using fallback")
 		return a.fallbackDetect(userInput), nil
@@ -43,14 +55,25 @@ func (a *IntentAgent) Detect(ctx context.Context, userInput string) (*IntentResu
 	// Get current time in UTC for context
 	currentTime := time.Now().UTC().Format("2006-01-02 15:04:05")
 
+	// Describe the previous turn so follow-ups like "make it 5 days instead" keep their context
+	conversationContext := ""
+	if previous != nil && previous.Intent != "" {
+		previousEntities, _ := json.Marshal(previous.Entities)
+		conversationContext = fmt.Sprintf(`
+Previous intent: %s
+Known entities from earlier messages: %s
+If the message is a follow-up to the previous intent, keep that intent and return only the entities the user mentioned or changed.
+`, previous.Intent, string(previousEntities))
+	}
+
 	// Construct the prompt
 	prompt := fmt.Sprintf(`Current Date and Time (UTC - YYYY-MM-DD HH:MM:SS formatted): %s
 Current User's Login: smithisrealdev
 
 You are an intent detection model for an AI travel assistant.
 Classify the user message into one of the following:
-[plan_trip, flight_check, weather_check, hotel_search, local_recommendation, budget_inquiry, plan_update, general_chat]
-
+[plan_trip, flight_check, flight_search, weather_check, hotel_search, local_recommendation, budget_inquiry, plan_update, visa_check, general_chat]
+%s
 Message: "%s"
 
 Return ONLY valid JSON:
@@ -58,16 +81,29 @@ Return ONLY valid JSON:
   "intent": "one_of_the_intents_above",
   "entities": {
     "destination": "city or country",
+    "origin": "departure city or airport (flight_search only)",
     "duration": number_of_days,
-    "budget": amount_in_thb,
+    "budget": amount,
+    "budget_currency": "ISO currency code of the budget, if the user named one",
+    "currency": "ISO currency code the user wants prices shown in, if mentioned",
     "date_from": "YYYY-MM-DD",
     "date_to": "YYYY-MM-DD",
     "travelers": number,
+    "style": "backpacker, mid or luxury, if the user describes how they travel",
     "interests": ["interest1"],
     "location": {"lat": 0.0, "lng": 0.0},
-    "flight_code": "flight number"
+    "flight_code": "flight number",
+    "nationality": "passport country (visa_check only)",
+    "purpose": "tourism, business, study or transit (visa_check only)",
+    "stay_days": number_of_days_in_destination,
+    "stars": minimum_hotel_stars,
+    "amenities": ["pool", "breakfast"],
+    "neighborhood": "area of the city (hotel_search only)",
+    "refundable": true_if_free_cancellation_is_required,
+    "direct_only": true_if_only_non_stop_flights (flight_search only),
+    "sort_by": "price, rating, distance or stars for hotel_search; price, duration or stops for flight_search"
   }
-}`, currentTime, userInput)
+}`, currentTime, conversationContext, userInput)
 
 	// Make API request
 	resp, err := a.client.CreateChatCompletion(
@@ -122,7 +158,9 @@ func (a *IntentAgent) fallbackDetect(userInput string) *IntentResult {
 	intent := "general_chat"
 	entities := make(map[string]interface{})
 
-	if strings.Contains(lowerInput, "flight") && (strings.Contains(lowerInput, "status") || strings.Contains(lowerInput, "check") || strings.Contains(lowerInput, "on time") || strings.Contains(lowerInput, "is flight")) {
+	if strings.Contains(lowerInput, "visa") || strings.Contains(lowerInput, "วีซ่า") {
+		intent = "visa_check"
+	} else if strings.Contains(lowerInput, "flight") && (strings.Contains(lowerInput, "status") || strings.Contains(lowerInput, "check") || strings.Contains(lowerInput, "on time") || strings.Contains(lowerInput, "is flight")) {
 		intent = "flight_check"
 		// Try to extract flight code (simple pattern matching)
 		words := strings.Fields(userInput)
@@ -136,28 +174,49 @@ func (a *IntentAgent) fallbackDetect(userInput string) *IntentResult {
 				break
 			}
 		}
+	} else if isFlightSearchRequest(lowerInput) {
+		intent = "flight_search"
 	} else if strings.Contains(lowerInput, "weather") || strings.Contains(lowerInput, "forecast") || strings.Contains(lowerInput, "rain") || strings.Contains(lowerInput, "ฝน") {
 		intent = "weather_check"
 	} else if strings.Contains(lowerInput, "hotel") || strings.Contains(lowerInput, "accommodation") || strings.Contains(lowerInput, "โรงแรม") {
 		intent = "hotel_search"
 	} else if strings.Contains(lowerInput, "restaurant") || strings.Contains(lowerInput, "cafe") || strings.Contains(lowerInput, "nearby") || strings.Contains(lowerInput, "ร้านอาหาร") || strings.Contains(lowerInput, "ใกล้") || strings.Contains(lowerInput, "ราเมน") {
 		intent = "local_recommendation"
+	} else if isBudgetQuestion(lowerInput) {
+		// Checked before plan_trip so "how much is a trip to Tokyo" is priced rather than planned
+		intent = "budget_inquiry"
+	} else if isPlanUpdateRequest(lowerInput) {
+		// Checked before plan_trip so "change my trip" is not treated as a new plan
+		intent = "plan_update"
 	} else if strings.Contains(lowerInput, "plan") || strings.Contains(lowerInput, "trip") || strings.Contains(lowerInput, "travel") || strings.Contains(lowerInput, "visit") || strings.Contains(lowerInput, "เที่ยว") || strings.Contains(lowerInput, "ไป") {
 		intent = "plan_trip"
-		// Extract basic entities for plan_trip
-		entities["duration"] = 7
-		entities["budget"] = 50000
 	} else if strings.Contains(lowerInput, "budget") || strings.Contains(lowerInput, "cost") || strings.Contains(lowerInput, "price") {
 		// Only set budget_inquiry if not already a trip plan
 		if !strings.Contains(lowerInput, "trip") && !strings.Contains(lowerInput, "travel") && !strings.Contains(lowerInput, "เที่ยว") {
 			intent = "budget_inquiry"
 		} else {
 			intent = "plan_trip"
-			entities["duration"] = 7
-			entities["budget"] = 50000
 		}
-	} else if strings.Contains(lowerInput, "update") || strings.Contains(lowerInput, "change") || strings.Contains(lowerInput, "modify") || strings.Contains(lowerInput, "เปลี่ยน") {
-		intent = "plan_update"
+	}
+
+	// Extract basic trip entities (destination, duration, budget) for every intent
+	for key, value := range extractFallbackEntities(userInput) {
+		entities[key] = value
+	}
+	if intent == "visa_check" {
+		for key, value := range extractVisaEntities(lowerInput) {
+			entities[key] = value
+		}
+	}
+	if intent == "flight_search" || intent == "budget_inquiry" {
+		for key, value := range extractRouteEntities(lowerInput) {
+			entities[key] = value
+		}
+	}
+	if intent == "hotel_search" {
+		for key, value := range extractHotelEntities(lowerInput) {
+			entities[key] = value
+		}
 	}
 
 	return &IntentResult{
@@ -166,6 +225,319 @@ func (a *IntentAgent) fallbackDetect(userInput string) *IntentResult {
 	}
 }
 
+// knownNationalities maps phrases that name the traveller's passport to a country code
+var knownNationalities = map[string]string{
+	"thai citizen": "TH", "thai passport": "TH", "thai national": "TH", "i'm thai": "TH", "i am thai": "TH",
+	"คนไทย": "TH", "พาสปอร์ตไทย": "TH", "หนังสือเดินทางไทย": "TH",
+	"us citizen": "US", "us passport": "US", "american citizen": "US", "american passport": "US", "i'm american": "US",
+	"uk citizen": "GB", "uk passport": "GB", "british citizen": "GB", "british passport": "GB",
+	"japanese citizen": "JP", "japanese passport": "JP",
+	"canadian citizen": "CA", "canadian passport": "CA",
+}
+
+// extractVisaEntities pulls nationality, destination, purpose and stay length out of a visa question
+func extractVisaEntities(lowerInput string) map[string]interface{} {
+	entities := make(map[string]interface{})
+
+	bestPhrase := ""
+	for phrase, code := range knownNationalities {
+		if strings.Contains(lowerInput, phrase) && len(phrase) > len(bestPhrase) {
+			bestPhrase = phrase
+			entities["nationality"] = code
+		}
+	}
+
+	// Look for the destination outside the nationality phrase ("american" is not a trip to America)
+	entities["destination"] = ""
+	remaining := lowerInput
+	if bestPhrase != "" {
+		remaining = strings.ReplaceAll(lowerInput, bestPhrase, " ")
+	}
+	bestName := ""
+	for name, destination := range knownDestinations {
+		if strings.Contains(remaining, name) && len(name) > len(bestName) {
+			bestName = name
+			entities["destination"] = destination
+		}
+	}
+
+	for _, purpose := range []string{"business", "ธุรกิจ", "study", "student", "เรียน", "transit", "ต่อเครื่อง"} {
+		if strings.Contains(lowerInput, purpose) {
+			entities["purpose"] = NormalizeVisaPurpose(purpose)
+			break
+		}
+	}
+
+	if match := durationPattern.FindStringSubmatch(lowerInput); match != nil {
+		if days, err := strconv.Atoi(match[1]); err == nil && days > 0 {
+			entities["stay_days"] = float64(days)
+		}
+	}
+
+	return entities
+}
+
+// extractHotelEntities pulls star rating, amenities, cancellation policy and sort order out of a hotel search
+func extractHotelEntities(lowerInput string) map[string]interface{} {
+	entities := make(map[string]interface{})
+
+	if match := starsPattern.FindStringSubmatch(lowerInput); match != nil {
+		if stars, err := strconv.Atoi(match[1]); err == nil && stars > 0 && stars <= 5 {
+			entities["stars"] = float64(stars)
+		}
+	}
+
+	var amenities []interface{}
+	seen := make(map[string]bool)
+	for word, amenity := range hotelAmenities {
+		if containsWord(lowerInput, word) && !seen[amenity] {
+			seen[amenity] = true
+			amenities = append(amenities, amenity)
+		}
+	}
+	if len(amenities) > 0 {
+		sort.Slice(amenities, func(i, j int) bool { return amenities[i].(string) < amenities[j].(string) })
+		entities["amenities"] = amenities
+	}
+
+	for _, phrase := range []string{"free cancellation", "refundable", "cancel for free", "ยกเลิกฟรี", "ยกเลิกได้"} {
+		if strings.Contains(lowerInput, phrase) {
+			entities["refundable"] = true
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(lowerInput, "cheapest") || strings.Contains(lowerInput, "ถูกที่สุด"):
+		entities["sort_by"] = "price"
+	case strings.Contains(lowerInput, "best rated") || strings.Contains(lowerInput, "highest rated") || strings.Contains(lowerInput, "top rated"):
+		entities["sort_by"] = "rating"
+	case strings.Contains(lowerInput, "closest") || strings.Contains(lowerInput, "nearest"):
+		entities["sort_by"] = "distance"
+	}
+
+	return entities
+}
+
+// isFlightSearchRequest reports whether the message asks for flights on a route rather than a trip plan
+func isFlightSearchRequest(lowerInput string) bool {
+	if strings.Contains(lowerInput, "plan") || strings.Contains(lowerInput, "trip") || strings.Contains(lowerInput, "แผน") {
+		return false
+	}
+	for _, keyword := range []string{"flight", "airfare", "plane ticket", "air ticket", "เที่ยวบิน", "ตั๋วเครื่องบิน"} {
+		if strings.Contains(lowerInput, keyword) {
+			return true
+		}
+	}
+	return containsWord(lowerInput, "fly")
+}
+
+// extractRouteEntities finds the origin ("from Bangkok", "จากกรุงเทพ"), destination, sort order and
+// direct-only preference of a flight search
+func extractRouteEntities(lowerInput string) map[string]interface{} {
+	entities := make(map[string]interface{})
+
+	originName := ""
+	for name, place := range knownDestinations {
+		idx := strings.Index(lowerInput, name)
+		if idx < 0 || len(name) <= len(originName) {
+			continue
+		}
+		before := strings.TrimSpace(lowerInput[:idx])
+		if strings.HasSuffix(before, "from") || strings.HasSuffix(before, "จาก") {
+			originName = name
+			entities["origin"] = place
+		}
+	}
+
+	// The destination is the longest other place name
+	bestName := ""
+	for name, place := range knownDestinations {
+		if name == originName || strings.Contains(originName, name) || place == entities["origin"] {
+			continue
+		}
+		if strings.Contains(lowerInput, name) && len(name) > len(bestName) {
+			bestName = name
+			entities["destination"] = place
+		}
+	}
+
+	switch {
+	case strings.Contains(lowerInput, "cheapest") || strings.Contains(lowerInput, "ถูกที่สุด"):
+		entities["sort_by"] = "price"
+	case strings.Contains(lowerInput, "fastest") || strings.Contains(lowerInput, "shortest"):
+		entities["sort_by"] = "duration"
+	}
+	if strings.Contains(lowerInput, "direct") || strings.Contains(lowerInput, "non-stop") || strings.Contains(lowerInput, "nonstop") || strings.Contains(lowerInput, "บินตรง") {
+		entities["direct_only"] = true
+	}
+
+	return entities
+}
+
+// containsWord reports whether text contains word on its own, so "pool" does not match "liverpool".
+// Thai has no spaces between words, so Thai words match anywhere.
+func containsWord(text, word string) bool {
+	if word == "" || word[0] >= 0x80 {
+		return strings.Contains(text, word)
+	}
+	return regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`).MatchString(text)
+}
+
+// isBudgetQuestion reports whether the message asks what a trip costs or whether a budget is enough
+func isBudgetQuestion(lowerInput string) bool {
+	keywords := []string{"how much", "afford", "enough", "cost of", "costs", "ค่าใช้จ่าย", "เท่าไหร่", "เท่าไร", "พอไหม"}
+	for _, keyword := range keywords {
+		if strings.Contains(lowerInput, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPlanUpdateRequest reports whether the message asks to change an existing plan
+func isPlanUpdateRequest(lowerInput string) bool {
+	keywords := []string{"update", "change", "modify", "swap", "replace", "shorten", "extend", "remove", "เปลี่ยน", "สลับ"}
+	for _, keyword := range keywords {
+		if strings.Contains(lowerInput, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	durationPattern        = regexp.MustCompile(`(?i)(\d+)\s*-?\s*(days?|nights?|วัน|คืน)`)
+	budgetPattern          = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(` + currencyWords + `)(?:[^a-z]|$)`)
+	budgetSymbolPattern    = regexp.MustCompile(`([$€£¥฿])\s?(\d[\d,]*(?:\.\d+)?)`)
+	budgetAfterPattern     = regexp.MustCompile(`(?i)(budget|งบ)\D{0,10}?(\d[\d,]*)`)
+	displayCurrencyPattern = regexp.MustCompile(`(?i)(?:\bin\s+|เป็น(?:เงิน)?\s*)(` + currencyWords + `)(?:[^a-z]|$)`)
+	travelersPattern       = regexp.MustCompile(`(?i)(\d+)\s*(people|persons?|guests?|travell?ers?|passengers?|adults?|pax|คน)`)
+	isoDatePattern         = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	starsPattern           = regexp.MustCompile(`(?i)(\d)\s*-?\s*(stars?|ดาว)`)
+	backpackerPattern      = regexp.MustCompile(`(?i)backpack|shoestring|cheap|hostel|แบ็คแพ็ค|ประหยัด`)
+	luxuryPattern          = regexp.MustCompile(`(?i)luxur|5-star|five-star|หรู`)
+	midRangePattern        = regexp.MustCompile(`(?i)mid-?range|moderate|ปานกลาง`)
+)
+
+// currencyWords are the currency codes, names and symbols recognized after an amount
+const currencyWords = `thb|baht|บาท|฿|usd|us\$|dollars?|ดอลลาร์|eur|euros?|ยูโร|gbp|pounds?|ปอนด์|jpy|yen|เยน|cny|yuan|หยวน|krw|วอน|sgd|myr|ringgit|hkd|twd|vnd|idr|php|inr|aud|nzd|cad|chf|aed`
+
+// hotelAmenities maps English and Thai amenity words to the amenity names used in hotel searches
+var hotelAmenities = map[string]string{
+	"pool": "pool", "สระว่ายน้ำ": "pool",
+	"breakfast": "breakfast", "อาหารเช้า": "breakfast",
+	"wifi": "wifi", "wi-fi": "wifi",
+	"gym": "gym", "fitness": "gym", "ฟิตเนส": "gym",
+	"parking": "parking", "ที่จอดรถ": "parking",
+	"spa": "spa", "สปา": "spa",
+	"onsen": "onsen", "ออนเซ็น": "onsen",
+	"kitchen": "kitchen", "ห้องครัว": "kitchen",
+}
+
+// knownDestinations maps English and Thai place names to the destination name used by the agents
+var knownDestinations = map[string]string{
+	"vancouver": "Vancouver", "แวนคูเวอร์": "Vancouver",
+	"canada": "Canada", "แคนาดา": "Canada",
+	"tokyo": "Tokyo", "โตเกียว": "Tokyo",
+	"kyoto": "Kyoto", "เกียวโต": "Kyoto",
+	"osaka": "Osaka", "โอซาก้า": "Osaka",
+	"japan": "Japan", "ญี่ปุ่น": "Japan",
+	"seoul": "Seoul", "โซล": "Seoul",
+	"busan": "Busan", "ปูซาน": "Busan",
+	"korea": "South Korea", "เกาหลี": "South Korea",
+	"singapore": "Singapore", "สิงคโปร์": "Singapore",
+	"hong kong": "Hong Kong", "ฮ่องกง": "Hong Kong",
+	"taipei": "Taipei", "ไทเป": "Taipei",
+	"taiwan": "Taiwan", "ไต้หวัน": "Taiwan",
+	"kuala lumpur": "Kuala Lumpur", "กัวลาลัมเปอร์": "Kuala Lumpur",
+	"bali": "Bali", "บาหลี": "Bali",
+	"hanoi": "Hanoi", "ฮานอย": "Hanoi",
+	"vietnam": "Vietnam", "เวียดนาม": "Vietnam",
+	"london": "London", "ลอนดอน": "London",
+	"paris": "Paris", "ปารีส": "Paris",
+	"sydney": "Sydney", "ซิดนีย์": "Sydney",
+	"new york": "New York", "นิวยอร์ก": "New York",
+	"dubai": "Dubai", "ดูไบ": "Dubai",
+	"bangkok": "Bangkok", "กรุงเทพ": "Bangkok",
+	"chiang mai": "Chiang Mai", "เชียงใหม่": "Chiang Mai",
+	"phuket": "Phuket", "ภูเก็ต": "Phuket",
+	"krabi": "Krabi", "กระบี่": "Krabi",
+	"pattaya": "Pattaya", "พัทยา": "Pattaya",
+	"thailand": "Thailand", "ประเทศไทย": "Thailand",
+	"united states": "United States", "usa": "United States", "america": "United States", "อเมริกา": "United States",
+	"united kingdom": "United Kingdom", "england": "United Kingdom", "อังกฤษ": "United Kingdom",
+}
+
+// extractFallbackEntities pulls destination, duration, budget and travel style out of free text
+func extractFallbackEntities(userInput string) map[string]interface{} {
+	entities := make(map[string]interface{})
+	lowerInput := strings.ToLower(userInput)
+
+	if match := durationPattern.FindStringSubmatch(lowerInput); match != nil {
+		if days, err := strconv.Atoi(match[1]); err == nil && days > 0 {
+			entities["duration"] = float64(days)
+		}
+	}
+
+	// Budgets without a named currency are read in the caller's display currency
+	budgetMatch, budgetCurrency := "", ""
+	if match := budgetPattern.FindStringSubmatch(lowerInput); match != nil {
+		budgetMatch, budgetCurrency = match[1], match[2]
+	} else if match := budgetSymbolPattern.FindStringSubmatch(lowerInput); match != nil {
+		budgetMatch, budgetCurrency = match[2], match[1]
+	} else if match := budgetAfterPattern.FindStringSubmatch(lowerInput); match != nil {
+		budgetMatch = match[2]
+	}
+	if budgetMatch != "" {
+		if budget, err := strconv.ParseFloat(strings.ReplaceAll(budgetMatch, ",", ""), 64); err == nil && budget > 0 {
+			entities["budget"] = budget
+			if code, ok := currency.Normalize(budgetCurrency); ok {
+				entities["budget_currency"] = code
+			}
+		}
+	}
+
+	if match := displayCurrencyPattern.FindStringSubmatch(lowerInput); match != nil {
+		if code, ok := currency.Normalize(match[1]); ok {
+			entities["currency"] = code
+		}
+	}
+
+	if match := travelersPattern.FindStringSubmatch(lowerInput); match != nil {
+		if travelers, err := strconv.Atoi(match[1]); err == nil && travelers > 0 {
+			entities["travelers"] = float64(travelers)
+		}
+	}
+
+	switch {
+	case backpackerPattern.MatchString(lowerInput):
+		entities["style"] = StyleBackpacker
+	case luxuryPattern.MatchString(lowerInput):
+		entities["style"] = StyleLuxury
+	case midRangePattern.MatchString(lowerInput):
+		entities["style"] = StyleMid
+	}
+
+	if dates := isoDatePattern.FindAllString(lowerInput, 2); len(dates) > 0 {
+		entities["date_from"] = dates[0]
+		if len(dates) > 1 {
+			entities["date_to"] = dates[1]
+		}
+	}
+
+	// Prefer the longest matching name so "hong kong" wins over shorter overlaps
+	bestName := ""
+	for name, destination := range knownDestinations {
+		if strings.Contains(lowerInput, name) && len(name) > len(bestName) {
+			bestName = name
+			entities["destination"] = destination
+		}
+	}
+
+	return entities
+}
+
 // Legacy function for backward compatibility
 func AnalyzeIntent(message string) (destination string, budgetTHB int, durationDays int) {
 	apiKey := os.Getenv("OPENAI_API_KEY")
@@ -186,7 +558,7 @@ func AnalyzeIntent(message string) (destination string, budgetTHB int, durationD
 
 	// Extract budget
 	if budget, ok := result.Entities["budget"].(float64); ok && budget > 0 {
-		budgetTHB = int(budget)
+		budgetTHB = int(budgetInBase(budget, result.Entities))
 	} else {
 		budgetTHB = 50000
 	}
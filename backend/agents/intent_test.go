@@ -75,6 +75,11 @@ func TestIntentAgent_Detect_English(t *testing.T) {
 			input:          "What's the weather in Tokyo?",
 			expectedIntent: "weather_check",
 		},
+		{
+			name:           "English flight search",
+			input:          "Find cheap flights from Bangkok to Tokyo",
+			expectedIntent: "flight_search",
+		},
 		{
 			name:           "English hotel search",
 			input:          "Find hotels in Bangkok",
@@ -130,3 +135,265 @@ func TestIntentAgent_LegacyFunction(t *testing.T) {
 	assert.Greater(t, budget, 0, "Budget should be positive")
 	assert.Greater(t, duration, 0, "Duration should be positive")
 }
+
+func TestExtractFallbackEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "Thai trip with budget and duration",
+			input: "อยากไปเที่ยวแคนาดา 7 วัน งบ 100,000 บาท",
+			expected: map[string]interface{}{
+				"destination":     "Canada",
+				"duration":        7.0,
+				"budget":          100000.0,
+				"budget_currency": "THB",
+			},
+		},
+		{
+			name:  "English trip",
+			input: "I want to visit Tokyo for 5 days with 80000 baht",
+			expected: map[string]interface{}{
+				"destination":     "Tokyo",
+				"duration":        5.0,
+				"budget":          80000.0,
+				"budget_currency": "THB",
+			},
+		},
+		{
+			name:  "Budget before amount",
+			input: "Plan Hong Kong with a budget of 30,000",
+			expected: map[string]interface{}{
+				"destination": "Hong Kong",
+				"budget":      30000.0,
+			},
+		},
+		{
+			name:     "Follow-up with only a duration",
+			input:    "make it 5 days instead",
+			expected: map[string]interface{}{"duration": 5.0},
+		},
+		{
+			name:  "Dates and travelers",
+			input: "Tokyo from 2025-05-01 to 2025-05-04 for 3 people",
+			expected: map[string]interface{}{
+				"destination": "Tokyo",
+				"date_from":   "2025-05-01",
+				"date_to":     "2025-05-04",
+				"travelers":   3.0,
+			},
+		},
+		{
+			name:  "Budget in another currency",
+			input: "Paris for 4 days, budget 1,500 euros",
+			expected: map[string]interface{}{
+				"destination":     "Paris",
+				"duration":        4.0,
+				"budget":          1500.0,
+				"budget_currency": "EUR",
+			},
+		},
+		{
+			name:  "Currency symbol and display currency",
+			input: "Tokyo trip for $2000, show prices in yen",
+			expected: map[string]interface{}{
+				"destination":     "Tokyo",
+				"budget":          2000.0,
+				"budget_currency": "USD",
+				"currency":        "JPY",
+			},
+		},
+		{
+			name:  "Travel style",
+			input: "Luxury honeymoon in Bali for 6 nights",
+			expected: map[string]interface{}{
+				"destination": "Bali",
+				"duration":    6.0,
+				"style":       "luxury",
+			},
+		},
+		{
+			name:     "Thai display currency",
+			input:    "ขอราคาเป็นเงินดอลลาร์",
+			expected: map[string]interface{}{"currency": "USD"},
+		},
+		{
+			name:     "Nothing to extract",
+			input:    "Hello!",
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractFallbackEntities(tt.input))
+		})
+	}
+}
+
+func TestExtractHotelEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "Stars, amenities and cancellation",
+			input: "4-star hotel with a pool and breakfast, free cancellation please",
+			expected: map[string]interface{}{
+				"stars":      4.0,
+				"amenities":  []interface{}{"breakfast", "pool"},
+				"refundable": true,
+			},
+		},
+		{
+			name:  "Thai amenities and sort",
+			input: "โรงแรม 5 ดาว มีสระว่ายน้ำ ถูกที่สุด",
+			expected: map[string]interface{}{
+				"stars":     5.0,
+				"amenities": []interface{}{"pool"},
+				"sort_by":   "price",
+			},
+		},
+		{
+			name:     "Place names are not amenities",
+			input:    "best rated hotel in liverpool",
+			expected: map[string]interface{}{"sort_by": "rating"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractHotelEntities(tt.input))
+		})
+	}
+}
+
+func TestExtractRouteEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Origin after from",
+			input:    "flights from tokyo to seoul on 2025-05-01",
+			expected: map[string]interface{}{"origin": "Tokyo", "destination": "Seoul"},
+		},
+		{
+			name:     "Destination only",
+			input:    "cheapest direct flight to osaka",
+			expected: map[string]interface{}{"destination": "Osaka", "sort_by": "price", "direct_only": true},
+		},
+		{
+			name:     "Thai",
+			input:    "หาเที่ยวบินจากกรุงเทพไปโตเกียว",
+			expected: map[string]interface{}{"origin": "Bangkok", "destination": "Tokyo"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, extractRouteEntities(tt.input))
+		})
+	}
+}
+
+func TestIntentAgent_Detect_VisaCheck(t *testing.T) {
+	agent := NewIntentAgent("")
+
+	tests := []struct {
+		name     string
+		input    string
+		entities map[string]interface{}
+	}{
+		{
+			name:  "English with nationality and stay",
+			input: "Do Thai citizens need a visa for Japan for 10 days?",
+			entities: map[string]interface{}{
+				"nationality": "TH",
+				"destination": "Japan",
+				"stay_days":   10.0,
+			},
+		},
+		{
+			name:  "Nationality phrase is not the destination",
+			input: "I'm American, do I need a business visa for Thailand?",
+			entities: map[string]interface{}{
+				"nationality": "US",
+				"destination": "Thailand",
+				"purpose":     "business",
+			},
+		},
+		{
+			name:  "Thai",
+			input: "คนไทยไปแคนาดาต้องขอวีซ่าไหม",
+			entities: map[string]interface{}{
+				"nationality": "TH",
+				"destination": "Canada",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := agent.Detect(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, "visa_check", result.Intent)
+			for key, value := range tt.entities {
+				assert.Equal(t, value, result.Entities[key], key)
+			}
+		})
+	}
+}
+
+func TestIntentAgent_Detect_BudgetInquiry(t *testing.T) {
+	agent := NewIntentAgent("")
+
+	tests := []struct {
+		name     string
+		input    string
+		entities map[string]interface{}
+	}{
+		{
+			name:  "Trip cost with style and origin",
+			input: "How much does a 5 day backpacking trip from Chiang Mai to Tokyo cost?",
+			entities: map[string]interface{}{
+				"origin":      "Chiang Mai",
+				"destination": "Tokyo",
+				"duration":    5.0,
+				"style":       "backpacker",
+			},
+		},
+		{
+			name:  "Is the budget enough",
+			input: "Is 40000 baht enough for 7 days in Seoul for 2 people?",
+			entities: map[string]interface{}{
+				"destination": "Seoul",
+				"budget":      40000.0,
+				"travelers":   2.0,
+			},
+		},
+		{
+			name:  "Thai",
+			input: "ไปญี่ปุ่น 5 วัน ค่าใช้จ่ายเท่าไหร่",
+			entities: map[string]interface{}{
+				"destination": "Japan",
+				"duration":    5.0,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := agent.Detect(context.Background(), tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, "budget_inquiry", result.Intent)
+			for key, value := range tt.entities {
+				assert.Equal(t, value, result.Entities[key], key)
+			}
+		})
+	}
+}
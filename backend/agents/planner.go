@@ -3,10 +3,17 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 )
 
 // TripPlan represents a complete travel itinerary
@@ -25,6 +32,16 @@ type ItineraryDay struct {
 	Budget     float64  `json:"budget"`
 }
 
+// PlanDayChange describes how a single itinerary day differs between two plans
+type PlanDayChange struct {
+	Day          int      `json:"day"`
+	Status       string   `json:"status"` // added, removed, modified
+	Added        []string `json:"added,omitempty"`
+	Removed      []string `json:"removed,omitempty"`
+	BudgetBefore float64  `json:"budget_before"`
+	BudgetAfter  float64  `json:"budget_after"`
+}
+
 // PlannerAgent creates and updates travel itineraries
 type PlannerAgent struct {
 	client *openai.Client
@@ -117,9 +134,13 @@ Return ONLY valid JSON:
 
 // UpdatePlan modifies an existing itinerary based on new conditions
 func (a *PlannerAgent) UpdatePlan(ctx context.Context, currentPlan *TripPlan, condition string) (*TripPlan, error) {
+	if currentPlan == nil {
+		return nil, fmt.Errorf("no plan to update")
+	}
+
 	if a.client == nil {
-		log.Println("PlannerAgent: OpenAI client not initialized, returning current plan")
-		return currentPlan, nil
+		log.Println("PlannerAgent: OpenAI client not initialized, using rule-based update")
+		return a.fallbackUpdate(currentPlan, condition), nil
 	}
 
 	// Convert current plan to JSON
@@ -130,7 +151,7 @@ func (a *PlannerAgent) UpdatePlan(ctx context.Context, currentPlan *TripPlan, co
 	}
 
 	// Construct the prompt
-	prompt := fmt.Sprintf(`You are PlannerAgent. The user currently has this plan:
+	prompt := fmt.Sprintf(`You are PlannerAgent. The user currently has this plan (amounts in THB):
 %s
 
 Update it according to this condition: %s
@@ -158,14 +179,14 @@ Return the revised plan in JSON with activities for each day.`, string(planJSON)
 	)
 
 	if err != nil {
-		log.Printf("PlannerAgent: OpenAI API error: %v, returning current plan", err)
-		return currentPlan, err
+		log.Printf("PlannerAgent: OpenAI API error: %v, using rule-based update", err)
+		return a.fallbackUpdate(currentPlan, condition), nil
 	}
 
 	// Parse the response
 	if len(resp.Choices) == 0 {
-		log.Println("PlannerAgent: No response from OpenAI, returning current plan")
-		return currentPlan, nil
+		log.Println("PlannerAgent: No response from OpenAI, using rule-based update")
+		return a.fallbackUpdate(currentPlan, condition), nil
 	}
 
 	content := resp.Choices[0].Message.Content
@@ -173,15 +194,383 @@ Return the revised plan in JSON with activities for each day.`, string(planJSON)
 	// Try to parse JSON response
 	var updatedPlan TripPlan
 	err = json.Unmarshal([]byte(content), &updatedPlan)
-	if err != nil {
-		log.Printf("PlannerAgent: Failed to parse OpenAI response: %v, returning current plan", err)
-		return currentPlan, err
+	if err != nil || len(updatedPlan.Itinerary) == 0 {
+		log.Printf("PlannerAgent: Failed to parse OpenAI response: %v, using rule-based update", err)
+		return a.fallbackUpdate(currentPlan, condition), nil
+	}
+
+	// Keep fields the model left out
+	if updatedPlan.Destination == "" {
+		updatedPlan.Destination = currentPlan.Destination
+	}
+	if updatedPlan.Duration == 0 {
+		updatedPlan.Duration = len(updatedPlan.Itinerary)
+	}
+	if updatedPlan.TotalBudget == 0 {
+		updatedPlan.TotalBudget = currentPlan.TotalBudget
 	}
 
 	log.Printf("PlannerAgent: Updated plan for %s", currentPlan.Destination)
 	return &updatedPlan, nil
 }
 
+var (
+	replaceDayPattern     = regexp.MustCompile(`(?i)(?:swap|replace|change|make)\s+day\s+(\d+)\s+(?:with|for|to|into)\s+(?:a\s+|an\s+|the\s+)?(.+)`)
+	swapActivityPattern   = regexp.MustCompile(`(?i)(?:swap|replace|change)\s+(?:the\s+)?(.+?)\s+(?:with|for|to)\s+(?:a\s+|an\s+|the\s+)?(.+)`)
+	removeActivityPattern = regexp.MustCompile(`(?i)(?:remove|skip|drop|cancel)\s+(?:the\s+)?(.+)`)
+	addActivityPattern    = regexp.MustCompile(`(?i)\badd\s+(?:a\s+|an\s+|the\s+)?(.+)`)
+	dayReferencePattern   = regexp.MustCompile(`(?i)\s*(?:on|to|from|in)?\s*day\s+(\d+)\s*`)
+)
+
+// StreamSummary writes a short markdown overview of a plan, passing each generated token to onToken.
+// Without an OpenAI client the plan's existing summary is streamed word by word.
+func (a *PlannerAgent) StreamSummary(ctx context.Context, plan *TripPlan, onToken func(string)) (string, error) {
+	if plan == nil {
+		return "", fmt.Errorf("no plan to summarize")
+	}
+
+	if a.client == nil {
+		log.Println("PlannerAgent: OpenAI client not initialized, streaming default summary")
+		return streamText(plan.Summary, onToken), nil
+	}
+
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal plan: %w", err)
+	}
+
+	stream, err := a.client.CreateChatCompletionStream(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: "gpt-4o-mini",
+			Messages: []openai.ChatCompletionMessage{
+				{
+					Role:    openai.ChatMessageRoleSystem,
+					Content: "You are an expert travel planner. Write friendly, concise trip overviews in markdown.",
+				},
+				{
+					Role:    openai.ChatMessageRoleUser,
+					Content: fmt.Sprintf("Write a short overview (under 120 words) of this itinerary:\n%s", string(planJSON)),
+				},
+			},
+			Temperature: 0.7,
+			MaxTokens:   300,
+			Stream:      true,
+		},
+	)
+	if err != nil {
+		log.Printf("PlannerAgent: OpenAI stream error: %v, streaming default summary", err)
+		return streamText(plan.Summary, onToken), nil
+	}
+	defer stream.Close()
+
+	var summary strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			if summary.Len() == 0 {
+				log.Printf("PlannerAgent: OpenAI stream error: %v, streaming default summary", err)
+				return streamText(plan.Summary, onToken), nil
+			}
+			log.Printf("PlannerAgent: OpenAI stream interrupted: %v", err)
+			break
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		token := chunk.Choices[0].Delta.Content
+		summary.WriteString(token)
+		if onToken != nil {
+			onToken(token)
+		}
+	}
+
+	return summary.String(), nil
+}
+
+// streamText passes text to onToken one word at a time and returns it unchanged
+func streamText(text string, onToken func(string)) string {
+	if onToken != nil {
+		for _, word := range strings.SplitAfter(text, " ") {
+			if word != "" {
+				onToken(word)
+			}
+		}
+	}
+	return text
+}
+
+// outdoorKeywords identify activities that are swapped for indoor ones when rain is mentioned
+var outdoorKeywords = []string{"explore", "park", "beach", "hike", "walk", "island", "garden", "mountain", "tour", "landmark"}
+
+// fallbackUpdate applies simple rule-based edits to a plan: duration and budget changes,
+// swapping, removing and adding activities, and indoor alternatives for rain
+func (a *PlannerAgent) fallbackUpdate(currentPlan *TripPlan, condition string) *TripPlan {
+	updated := copyPlan(currentPlan)
+	entities := extractFallbackEntities(condition)
+	lowerCondition := strings.ToLower(condition)
+
+	// An explicit "day N" limits activity edits to that day
+	targetDay := 0
+	if match := dayReferencePattern.FindStringSubmatch(condition); match != nil {
+		targetDay, _ = strconv.Atoi(match[1])
+	}
+	activityText := strings.TrimSpace(dayReferencePattern.ReplaceAllString(condition, " "))
+
+	if match := replaceDayPattern.FindStringSubmatch(condition); match != nil {
+		// "Change day 2 to a cooking class" replaces everything planned for the day
+		day, _ := strconv.Atoi(match[1])
+		replaceDay(updated, day, cleanActivity(match[2]))
+	} else if match := swapActivityPattern.FindStringSubmatch(activityText); match != nil {
+		replaceActivities(updated, targetDay, cleanActivity(match[1]), cleanActivity(match[2]))
+	} else if match := removeActivityPattern.FindStringSubmatch(activityText); match != nil {
+		replaceActivities(updated, targetDay, cleanActivity(match[1]), "")
+	} else if match := addActivityPattern.FindStringSubmatch(activityText); match != nil {
+		addActivity(updated, targetDay, cleanActivity(match[1]))
+	}
+
+	if strings.Contains(lowerCondition, "rain") || strings.Contains(lowerCondition, "ฝน") {
+		for i := range updated.Itinerary {
+			if targetDay > 0 && updated.Itinerary[i].Day != targetDay {
+				continue
+			}
+			for j, activity := range updated.Itinerary[i].Activities {
+				if isOutdoorActivity(activity) {
+					updated.Itinerary[i].Activities[j] = fmt.Sprintf("Indoor alternative: museums or shopping in %s (instead of: %s)", updated.Destination, activity)
+				}
+			}
+		}
+	}
+
+	budget := updated.TotalBudget
+	if value, ok := entities["budget"].(float64); ok {
+		budget = budgetInBase(value, entities)
+	}
+	duration := updated.Duration
+	if value, ok := entities["duration"].(float64); ok {
+		duration = int(value)
+	}
+	if duration != updated.Duration || budget != updated.TotalBudget {
+		resizePlan(updated, duration, budget)
+	}
+
+	log.Printf("PlannerAgent: Applied rule-based update to %s plan: %q", updated.Destination, condition)
+	return updated
+}
+
+// budgetInBase converts a budget entity given in "budget_currency" into THB using the bundled rates
+func budgetInBase(amount float64, entities map[string]interface{}) float64 {
+	code, ok := entities["budget_currency"].(string)
+	if !ok || code == currency.Base {
+		return amount
+	}
+	converted, err := currency.NewConverter(currency.DefaultRates()).Convert(context.Background(), currency.Money{Amount: amount, Currency: code}, currency.Base)
+	if err != nil {
+		log.Printf("PlannerAgent: Cannot convert budget from %s, assuming THB: %v", code, err)
+		return amount
+	}
+	return math.Round(converted.Amount)
+}
+
+// copyPlan returns a deep copy of a plan so updates never modify the original
+func copyPlan(plan *TripPlan) *TripPlan {
+	copied := *plan
+	copied.Itinerary = make([]ItineraryDay, len(plan.Itinerary))
+	for i, day := range plan.Itinerary {
+		copied.Itinerary[i] = ItineraryDay{
+			Day:        day.Day,
+			Activities: append([]string(nil), day.Activities...),
+			Budget:     day.Budget,
+		}
+	}
+	return &copied
+}
+
+// replaceActivities replaces (or removes, when replacement is empty) activities matching target
+func replaceActivities(plan *TripPlan, day int, target, replacement string) {
+	if target == "" {
+		return
+	}
+	matcher, err := regexp.Compile(`(?i)\b` + regexp.QuoteMeta(target) + `\b`)
+	if err != nil {
+		return
+	}
+
+	for i := range plan.Itinerary {
+		if day > 0 && plan.Itinerary[i].Day != day {
+			continue
+		}
+		activities := make([]string, 0, len(plan.Itinerary[i].Activities))
+		for _, activity := range plan.Itinerary[i].Activities {
+			if !matcher.MatchString(activity) {
+				activities = append(activities, activity)
+			} else if replacement != "" {
+				activities = append(activities, replacement)
+			}
+		}
+		plan.Itinerary[i].Activities = activities
+	}
+}
+
+// replaceDay replaces all activities of the given day with a single activity
+func replaceDay(plan *TripPlan, day int, activity string) {
+	if activity == "" {
+		return
+	}
+	for i := range plan.Itinerary {
+		if plan.Itinerary[i].Day == day {
+			plan.Itinerary[i].Activities = []string{activity}
+		}
+	}
+}
+
+// addActivity appends an activity to the given day, or to the least busy day when none is given
+func addActivity(plan *TripPlan, day int, activity string) {
+	if activity == "" || len(plan.Itinerary) == 0 {
+		return
+	}
+
+	index := 0
+	for i, itineraryDay := range plan.Itinerary {
+		if day > 0 {
+			if itineraryDay.Day == day {
+				index = i
+				break
+			}
+		} else if len(itineraryDay.Activities) < len(plan.Itinerary[index].Activities) {
+			index = i
+		}
+	}
+	plan.Itinerary[index].Activities = append(plan.Itinerary[index].Activities, activity)
+}
+
+// resizePlan changes the number of days and spreads the total budget evenly across them
+func resizePlan(plan *TripPlan, duration int, budget float64) {
+	if duration <= 0 {
+		return
+	}
+
+	if duration < len(plan.Itinerary) {
+		plan.Itinerary = plan.Itinerary[:duration]
+	}
+	for day := len(plan.Itinerary) + 1; day <= duration; day++ {
+		plan.Itinerary = append(plan.Itinerary, ItineraryDay{
+			Day: day,
+			Activities: []string{
+				fmt.Sprintf("Explore %s attractions", plan.Destination),
+				"Try local cuisine",
+				"Visit popular landmarks",
+			},
+		})
+	}
+
+	dailyBudget := budget / float64(duration)
+	for i := range plan.Itinerary {
+		plan.Itinerary[i].Budget = dailyBudget
+	}
+
+	plan.Duration = duration
+	plan.TotalBudget = budget
+	plan.Summary = planSummary(plan.Destination, duration, budget)
+}
+
+// cleanActivity trims whitespace and trailing punctuation from an activity extracted from text
+func cleanActivity(activity string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(activity), ".!?,"))
+}
+
+// isOutdoorActivity reports whether an activity is likely affected by rain
+func isOutdoorActivity(activity string) bool {
+	lowerActivity := strings.ToLower(activity)
+	if strings.HasPrefix(lowerActivity, "indoor alternative") {
+		return false
+	}
+	for _, keyword := range outdoorKeywords {
+		if strings.Contains(lowerActivity, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffPlans compares two plans day by day and returns the days that changed
+func DiffPlans(before, after *TripPlan) []PlanDayChange {
+	changes := make([]PlanDayChange, 0)
+	if before == nil || after == nil {
+		return changes
+	}
+
+	beforeDays := make(map[int]ItineraryDay, len(before.Itinerary))
+	for _, day := range before.Itinerary {
+		beforeDays[day.Day] = day
+	}
+	afterDays := make(map[int]ItineraryDay, len(after.Itinerary))
+	for _, day := range after.Itinerary {
+		afterDays[day.Day] = day
+	}
+
+	for _, day := range after.Itinerary {
+		previous, existed := beforeDays[day.Day]
+		if !existed {
+			changes = append(changes, PlanDayChange{
+				Day:         day.Day,
+				Status:      "added",
+				Added:       day.Activities,
+				BudgetAfter: day.Budget,
+			})
+			continue
+		}
+
+		added := activityDifference(day.Activities, previous.Activities)
+		removed := activityDifference(previous.Activities, day.Activities)
+		if len(added) > 0 || len(removed) > 0 || previous.Budget != day.Budget {
+			changes = append(changes, PlanDayChange{
+				Day:          day.Day,
+				Status:       "modified",
+				Added:        added,
+				Removed:      removed,
+				BudgetBefore: previous.Budget,
+				BudgetAfter:  day.Budget,
+			})
+		}
+	}
+
+	for _, day := range before.Itinerary {
+		if _, stillExists := afterDays[day.Day]; !stillExists {
+			changes = append(changes, PlanDayChange{
+				Day:          day.Day,
+				Status:       "removed",
+				Removed:      day.Activities,
+				BudgetBefore: day.Budget,
+			})
+		}
+	}
+
+	return changes
+}
+
+// activityDifference returns activities in a that are not in b, keeping duplicates and order
+func activityDifference(a, b []string) []string {
+	remaining := make(map[string]int, len(b))
+	for _, activity := range b {
+		remaining[activity]++
+	}
+
+	difference := make([]string, 0)
+	for _, activity := range a {
+		if remaining[activity] > 0 {
+			remaining[activity]--
+			continue
+		}
+		difference = append(difference, activity)
+	}
+	return difference
+}
+
 // fallbackPlan generates a simple default itinerary
 func (a *PlannerAgent) fallbackPlan(destination string, duration int, budget float64) *TripPlan {
 	dailyBudget := budget / float64(duration)
@@ -202,14 +591,17 @@ func (a *PlannerAgent) fallbackPlan(destination string, duration int, budget flo
 		}
 	}
 
-	summary := fmt.Sprintf("## %d-Day Trip to %s\n\nExplore the best of %s with daily activities and local experiences. Budget: %.0f THB", 
-		duration, destination, destination, budget)
-
 	return &TripPlan{
 		Destination: destination,
 		Duration:    duration,
 		TotalBudget: budget,
 		Itinerary:   itinerary,
-		Summary:     summary,
+		Summary:     planSummary(destination, duration, budget),
 	}
 }
+
+// planSummary builds the default markdown overview for a plan
+func planSummary(destination string, duration int, budget float64) string {
+	return fmt.Sprintf("## %d-Day Trip to %s\n\nExplore the best of %s with daily activities and local experiences. Budget: %.0f THB",
+		duration, destination, destination, budget)
+}
@@ -0,0 +1,163 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePlan_Fallback(t *testing.T) {
+	agent := NewPlannerAgent("")
+	base := agent.fallbackPlan("Tokyo", 3, 30000)
+
+	tests := []struct {
+		name      string
+		condition string
+		check     func(t *testing.T, updated *TripPlan)
+	}{
+		{
+			name:      "Shorten trip",
+			condition: "Shorten it to 2 days",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Equal(t, 2, updated.Duration)
+				assert.Len(t, updated.Itinerary, 2)
+				assert.Equal(t, 15000.0, updated.Itinerary[0].Budget)
+			},
+		},
+		{
+			name:      "Extend trip",
+			condition: "Extend the trip to 5 days",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Equal(t, 5, updated.Duration)
+				assert.Len(t, updated.Itinerary, 5)
+				assert.Equal(t, 5, updated.Itinerary[4].Day)
+				assert.Equal(t, 6000.0, updated.Itinerary[4].Budget)
+			},
+		},
+		{
+			name:      "Change budget",
+			condition: "Change the budget to 60,000 THB",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Equal(t, 60000.0, updated.TotalBudget)
+				assert.Equal(t, 20000.0, updated.Itinerary[0].Budget)
+			},
+		},
+		{
+			name:      "Change budget in another currency",
+			condition: "Change the budget to 1,000 USD",
+			check: func(t *testing.T, updated *TripPlan) {
+				rate, err := currency.DefaultRates().Rate(context.Background(), "USD", "THB")
+				require.NoError(t, err)
+				assert.InDelta(t, 1000*rate, updated.TotalBudget, 1)
+			},
+		},
+		{
+			name:      "Swap activity on one day",
+			condition: "Swap the landmarks for a sushi class on day 2",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Contains(t, updated.Itinerary[1].Activities, "sushi class")
+				assert.NotContains(t, updated.Itinerary[1].Activities, "Visit popular landmarks")
+				assert.Contains(t, updated.Itinerary[0].Activities, "Visit popular landmarks")
+			},
+		},
+		{
+			name:      "Change a whole day",
+			condition: "Change day 2 to a cooking class",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Equal(t, []string{"cooking class"}, updated.Itinerary[1].Activities)
+				assert.Contains(t, updated.Itinerary[0].Activities, "Visit popular landmarks")
+				assert.Contains(t, updated.Itinerary[2].Activities, "Visit popular landmarks")
+			},
+		},
+		{
+			name:      "Remove activity everywhere",
+			condition: "Remove local cuisine",
+			check: func(t *testing.T, updated *TripPlan) {
+				for _, day := range updated.Itinerary {
+					assert.NotContains(t, day.Activities, "Try local cuisine")
+				}
+			},
+		},
+		{
+			name:      "Add activity to a day",
+			condition: "Add a teamLab visit on day 3",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Contains(t, updated.Itinerary[2].Activities, "teamLab visit")
+			},
+		},
+		{
+			name:      "Rain swaps outdoor activities",
+			condition: "It will rain on day 1, please change activities",
+			check: func(t *testing.T, updated *TripPlan) {
+				assert.Contains(t, updated.Itinerary[0].Activities[0], "Indoor alternative")
+				assert.Equal(t, "Explore Tokyo attractions", updated.Itinerary[1].Activities[0])
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := agent.UpdatePlan(context.Background(), base, tt.condition)
+			require.NoError(t, err)
+			require.NotNil(t, updated)
+			tt.check(t, updated)
+
+			// The original plan must never be modified
+			assert.Equal(t, 3, base.Duration)
+			assert.Len(t, base.Itinerary, 3)
+			assert.Equal(t, "Visit popular landmarks", base.Itinerary[1].Activities[2])
+		})
+	}
+}
+
+func TestUpdatePlan_NilPlan(t *testing.T) {
+	agent := NewPlannerAgent("")
+	_, err := agent.UpdatePlan(context.Background(), nil, "make it shorter")
+	assert.Error(t, err)
+}
+
+func TestDiffPlans(t *testing.T) {
+	before := &TripPlan{
+		Itinerary: []ItineraryDay{
+			{Day: 1, Activities: []string{"A", "B"}, Budget: 1000},
+			{Day: 2, Activities: []string{"C"}, Budget: 1000},
+			{Day: 3, Activities: []string{"D"}, Budget: 1000},
+		},
+	}
+	after := &TripPlan{
+		Itinerary: []ItineraryDay{
+			{Day: 1, Activities: []string{"A", "B"}, Budget: 1000},
+			{Day: 2, Activities: []string{"E"}, Budget: 1500},
+		},
+	}
+
+	changes := DiffPlans(before, after)
+	require.Len(t, changes, 2)
+
+	assert.Equal(t, PlanDayChange{Day: 2, Status: "modified", Added: []string{"E"}, Removed: []string{"C"}, BudgetBefore: 1000, BudgetAfter: 1500}, changes[0])
+	assert.Equal(t, PlanDayChange{Day: 3, Status: "removed", Removed: []string{"D"}, BudgetBefore: 1000}, changes[1])
+
+	assert.Empty(t, DiffPlans(before, before))
+}
+
+func TestPlannerAgent_StreamSummaryFallback(t *testing.T) {
+	agent := NewPlannerAgent("")
+	plan := agent.fallbackPlan("Tokyo", 3, 30000)
+
+	var tokens []string
+	summary, err := agent.StreamSummary(context.Background(), plan, func(token string) {
+		tokens = append(tokens, token)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, plan.Summary, summary)
+	assert.Greater(t, len(tokens), 1, "Summary should arrive in several tokens")
+	assert.Equal(t, summary, strings.Join(tokens, ""))
+
+	_, err = agent.StreamSummary(context.Background(), nil, nil)
+	assert.Error(t, err)
+}
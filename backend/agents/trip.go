@@ -0,0 +1,103 @@
+package agents
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Trip is a saved travel plan owned by a user. Budgets are in THB.
+type Trip struct {
+	ID          int64      `json:"id"`
+	UserID      string     `json:"user_id"`
+	Title       string     `json:"title"`
+	Destination string     `json:"destination"`
+	StartDate   *time.Time `json:"start_date,omitempty"`
+	Duration    int        `json:"duration_days"`
+	Budget      float64    `json:"budget"`
+	Summary     string     `json:"summary,omitempty"`
+	Days        []TripDay  `json:"days"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TripDay is one day of a saved itinerary
+type TripDay struct {
+	Day        int            `json:"day"`
+	Budget     float64        `json:"budget"`
+	Activities []TripActivity `json:"activities"`
+}
+
+// TripActivity is one activity of a day, in the order it is planned
+type TripActivity struct {
+	Position    int    `json:"position"`
+	Description string `json:"description"`
+}
+
+// NewTripFromPlan turns a planner itinerary into a trip ready to be saved
+func NewTripFromPlan(plan *TripPlan) Trip {
+	trip := Trip{
+		Destination: plan.Destination,
+		Duration:    plan.Duration,
+		Budget:      plan.TotalBudget,
+		Summary:     plan.Summary,
+		Days:        make([]TripDay, 0, len(plan.Itinerary)),
+	}
+	for _, day := range plan.Itinerary {
+		tripDay := TripDay{Day: day.Day, Budget: day.Budget, Activities: make([]TripActivity, 0, len(day.Activities))}
+		for _, activity := range day.Activities {
+			tripDay.Activities = append(tripDay.Activities, TripActivity{Description: activity})
+		}
+		trip.Days = append(trip.Days, tripDay)
+	}
+	trip.Normalize()
+	return trip
+}
+
+// Plan returns the trip as a planner itinerary, so it can be updated like a freshly created plan
+func (t *Trip) Plan() *TripPlan {
+	plan := &TripPlan{
+		Destination: t.Destination,
+		Duration:    t.Duration,
+		TotalBudget: t.Budget,
+		Summary:     t.Summary,
+		Itinerary:   make([]ItineraryDay, 0, len(t.Days)),
+	}
+	for _, day := range t.Days {
+		itineraryDay := ItineraryDay{Day: day.Day, Budget: day.Budget, Activities: make([]string, 0, len(day.Activities))}
+		for _, activity := range day.Activities {
+			itineraryDay.Activities = append(itineraryDay.Activities, activity.Description)
+		}
+		plan.Itinerary = append(plan.Itinerary, itineraryDay)
+	}
+	return plan
+}
+
+// Normalize sorts the days, numbers activities in order, drops empty activities, fills in the
+// duration from the days and names untitled trips after their destination
+func (t *Trip) Normalize() {
+	sort.SliceStable(t.Days, func(i, j int) bool { return t.Days[i].Day < t.Days[j].Day })
+	for i := range t.Days {
+		activities := make([]TripActivity, 0, len(t.Days[i].Activities))
+		for _, activity := range t.Days[i].Activities {
+			activity.Description = strings.TrimSpace(activity.Description)
+			if activity.Description == "" {
+				continue
+			}
+			activity.Position = len(activities) + 1
+			activities = append(activities, activity)
+		}
+		t.Days[i].Activities = activities
+		if t.Days[i].Day > t.Duration {
+			t.Duration = t.Days[i].Day
+		}
+	}
+	if t.Days == nil {
+		t.Days = []TripDay{}
+	}
+
+	t.Title = strings.TrimSpace(t.Title)
+	if t.Title == "" && t.Destination != "" {
+		t.Title = "Trip to " + t.Destination
+	}
+}
@@ -0,0 +1,46 @@
+package agents
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrip_PlanRoundTrip(t *testing.T) {
+	plan := &TripPlan{
+		Destination: "Tokyo",
+		Duration:    2,
+		TotalBudget: 40000,
+		Summary:     "Food and temples",
+		Itinerary: []ItineraryDay{
+			{Day: 2, Activities: []string{"Asakusa", " ", "Ueno Park"}, Budget: 20000},
+			{Day: 1, Activities: []string{"Shibuya"}, Budget: 20000},
+		},
+	}
+
+	trip := NewTripFromPlan(plan)
+	assert.Equal(t, "Trip to Tokyo", trip.Title)
+	require.Len(t, trip.Days, 2)
+	assert.Equal(t, 1, trip.Days[0].Day, "Days are sorted")
+	assert.Equal(t, []TripActivity{{Position: 1, Description: "Asakusa"}, {Position: 2, Description: "Ueno Park"}},
+		trip.Days[1].Activities, "Blank activities are dropped and the rest numbered in order")
+
+	back := trip.Plan()
+	assert.Equal(t, "Tokyo", back.Destination)
+	assert.Equal(t, 2, back.Duration)
+	assert.Equal(t, 40000.0, back.TotalBudget)
+	assert.Equal(t, []string{"Asakusa", "Ueno Park"}, back.Itinerary[1].Activities)
+}
+
+func TestTrip_NormalizeExtendsDuration(t *testing.T) {
+	trip := Trip{Title: "  Honeymoon ", Destination: "Bali", Duration: 2, Days: []TripDay{{Day: 4}}}
+	trip.Normalize()
+	assert.Equal(t, "Honeymoon", trip.Title)
+	assert.Equal(t, 4, trip.Duration)
+	assert.NotNil(t, trip.Days[0].Activities)
+
+	empty := Trip{Destination: "Bali"}
+	empty.Normalize()
+	assert.Equal(t, []TripDay{}, empty.Days)
+}
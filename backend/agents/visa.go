@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 )
 
 // VisaRequirement represents visa requirements for a country pair
@@ -20,6 +23,15 @@ type VisaRequirement struct {
 	Validity        string                 `json:"validity,omitempty"`
 	MaxStayDays     int                    `json:"max_stay_days,omitempty"`
 	Disclaimer      string                 `json:"disclaimer"`
+	// Provenance of the answer; unverified answers were generated by the LLM or not yet checked
+	// against official sources, and are not authoritative
+	Verified     bool       `json:"verified"`
+	Source       string     `json:"source,omitempty"`
+	SourceURL    string     `json:"source_url,omitempty"`
+	LastVerified *time.Time `json:"last_verified,omitempty"`
+	// Evaluation of the requested stay against this requirement
+	Verdict  string   `json:"verdict,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
 }
 
 // ChecklistItem represents a document requirement
@@ -40,42 +52,72 @@ type FeeInfo struct {
 	Currency string  `json:"currency"`
 }
 
+// llmRuleTTL is how long an unverified LLM answer is reused before asking again
+const llmRuleTTL = 30 * 24 * time.Hour
+
+// Notices prepended to the disclaimer of unverified answers: generatedNotice for LLM-sourced
+// answers, unverifiedNotice for imported and seeded rules nobody has verified yet
+const (
+	generatedNotice  = "⚠️ Unverified: this answer was generated by AI and has not been checked against official sources. "
+	unverifiedNotice = "⚠️ Unverified: this information has not yet been verified against official sources. "
+)
+
 // VisaDocAgent provides visa requirement information
 type VisaDocAgent struct {
 	client *openai.Client
-	db     map[string]*VisaRequirement // In-memory database (would be SQL in production)
+	store  VisaStore
 }
 
-// NewVisaDocAgent creates a new visa documentation agent
+// NewVisaDocAgent creates a new visa documentation agent backed by an in-memory store with the default rules
 func NewVisaDocAgent(apiKey string) *VisaDocAgent {
 	var client *openai.Client
 	if apiKey != "" {
 		client = openai.NewClient(apiKey)
 	}
-	
-	agent := &VisaDocAgent{
+
+	return &VisaDocAgent{
 		client: client,
-		db:     make(map[string]*VisaRequirement),
+		store:  NewMemoryVisaStore(DefaultVisaRules()...),
 	}
-	
-	// Initialize with seed data
-	agent.initializeSeedData()
-	
-	return agent
 }
 
-// CheckVisa checks visa requirements for a given route
+// SetStore replaces the visa rules store (e.g. with the Postgres store)
+func (a *VisaDocAgent) SetStore(store VisaStore) {
+	a.store = store
+}
+
+// CheckVisa checks visa requirements for a given route and evaluates the stay length against them
 func (a *VisaDocAgent) CheckVisa(ctx context.Context, nationality, destination string, stayDays int, purpose string) (*VisaRequirement, error) {
 	log.Printf("VisaDocAgent: Checking visa requirements for %s → %s, %d days, purpose: %s", 
 		nationality, destination, stayDays, purpose)
 
-	// Generate cache key
-	key := fmt.Sprintf("%s_%s_%s", nationality, destination, purpose)
-	
-	// Check internal database first
-	if req, exists := a.db[key]; exists {
-		log.Printf("VisaDocAgent: Found in database")
-		return req, nil
+	nationality = NormalizeCountry(nationality)
+	destination = NormalizeCountry(destination)
+	purpose = NormalizeVisaPurpose(purpose)
+
+	requirement, err := a.findRequirement(ctx, nationality, destination, stayDays, purpose, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	evaluateStay(requirement, destination, stayDays)
+	return requirement, nil
+}
+
+// findRequirement returns the requirement for a route on the given day: a stored rule,
+// the transit rules, an OpenAI answer or the generic fallback
+func (a *VisaDocAgent) findRequirement(ctx context.Context, nationality, destination string, stayDays int, purpose string, on time.Time) (*VisaRequirement, error) {
+	// Check the rules store first
+	rule, err := a.store.FindRule(ctx, nationality, destination, purpose, on)
+	if err != nil {
+		log.Printf("VisaDocAgent: Failed to look up visa rule: %v", err)
+	} else if rule != nil {
+		log.Printf("VisaDocAgent: Found rule in store (verified=%t)", rule.Verified)
+		return rule.Answer(), nil
+	}
+
+	if purpose == "transit" {
+		return a.transitRequirement(ctx, nationality, destination, on)
 	}
 
 	// If OpenAI client available, use it as fallback
@@ -134,7 +176,7 @@ Provide accurate information. If uncertain, set visa_required to true and sugges
 	)
 
 	if err != nil {
-		log.Printf("VisaDocAgent: OpenAI API error: %%v", err)
+		log.Printf("VisaDocAgent: OpenAI API error: %v", err)
 		return a.getFallbackResponse(nationality, destination), nil
 	}
 
@@ -147,15 +189,28 @@ Provide accurate information. If uncertain, set visa_required to true and sugges
 	var requirement VisaRequirement
 	err = json.Unmarshal([]byte(content), &requirement)
 	if err != nil {
-		log.Printf("VisaDocAgent: Failed to parse OpenAI response: %%v", err)
+		log.Printf("VisaDocAgent: Failed to parse OpenAI response: %v", err)
 		return a.getFallbackResponse(nationality, destination), nil
 	}
 
-	// Cache the response
-	key := fmt.Sprintf("%%s_%%s_%%s", nationality, destination, purpose)
-	a.db[key] = &requirement
+	// Keep the answer for a while, flagged as unverified
+	now := time.Now()
+	expires := now.Add(llmRuleTTL)
+	rule := &VisaRule{
+		Nationality:   nationality,
+		Destination:   destination,
+		Purpose:       purpose,
+		Requirement:   requirement,
+		EffectiveFrom: now,
+		EffectiveTo:   &expires,
+		Source:        VisaSourceLLM,
+		Verified:      false,
+	}
+	if err := a.store.SaveRule(ctx, rule); err != nil {
+		log.Printf("VisaDocAgent: Failed to store LLM answer: %v", err)
+	}
 
-	return &requirement, nil
+	return rule.Answer(), nil
 }
 
 // getFallbackResponse returns a generic response when data is not available
@@ -179,115 +234,319 @@ func (a *VisaDocAgent) getFallbackResponse(nationality, destination string) *Vis
 		},
 		Forms:          []FormInfo{},
 		ProcessingTime: "Unknown",
+		Source:         VisaSourceFallback,
 		Disclaimer:     "⚠️ Visa requirements could not be verified from our database. Please contact the embassy or consulate of " + destination + " for accurate information. This is not legal advice.",
 	}
 }
 
-// initializeSeedData populates the database with common visa requirements
-func (a *VisaDocAgent) initializeSeedData() {
-	// Thailand → Canada (Tourist)
-	a.db["TH_CA_tourism"] = &VisaRequirement{
-		VisaRequired: true,
-		VisaType:     "Temporary Resident Visa (TRV)",
-		Checklist: []ChecklistItem{
-			{Item: "Valid passport", Notes: "Valid for at least 6 months beyond stay"},
-			{Item: "Completed application form", Notes: "IMM 5257 or IMM 5257E"},
-			{Item: "Passport photos", Notes: "2 recent photos (35mm x 45mm)"},
-			{Item: "Proof of financial support", Notes: "Bank statements for last 6 months"},
-			{Item: "Travel itinerary", Notes: "Flight bookings and accommodation"},
-			{Item: "Employment letter", Notes: "From current employer (if employed)"},
-			{Item: "Invitation letter", Notes: "If visiting family/friends"},
-		},
-		Forms: []FormInfo{
-			{Name: "IMM 5257 - Application for Visitor Visa", DownloadURL: "https://www.canada.ca/en/immigration-refugees-citizenship/services/application/application-forms-guides/imm5257e.html"},
-			{Name: "IMM 5645 - Family Information", DownloadURL: "https://www.canada.ca/en/immigration-refugees-citizenship/services/application/application-forms-guides/imm5645e.html"},
-		},
-		ProcessingTime: "14-21 days",
-		Fees:           &FeeInfo{Amount: 100, Currency: "CAD"},
-		Validity:       "Up to 10 years (multiple entry)",
-		MaxStayDays:    180,
-		Disclaimer:     "This is not legal advice. Please verify with official Canadian government sources at canada.ca",
+// DefaultVisaRules returns the curated rules for common routes. They name their official source
+// but carry no verification date, so like imports without one they are served as unverified.
+func DefaultVisaRules() []VisaRule {
+	effectiveFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rule := func(nationality, destination, sourceURL string, requirement VisaRequirement) VisaRule {
+		return VisaRule{
+			Nationality:   nationality,
+			Destination:   destination,
+			Purpose:       "tourism",
+			Requirement:   requirement,
+			EffectiveFrom: effectiveFrom,
+			SourceURL:     sourceURL,
+			Source:        VisaSourceSeed,
+		}
 	}
 
-	// Thailand → Japan (Tourist)
-	a.db["TH_JP_tourism"] = &VisaRequirement{
-		VisaRequired: false,
-		VisaType:     "Visa Exemption",
-		Checklist: []ChecklistItem{
-			{Item: "Valid passport", Notes: "Valid for duration of stay"},
-			{Item: "Return ticket", Notes: "Proof of onward travel"},
-			{Item: "Proof of accommodation", Notes: "Hotel bookings or invitation letter"},
-			{Item: "Sufficient funds", Notes: "Approximately 100,000 JPY or equivalent"},
-		},
-		Forms:          []FormInfo{},
-		ProcessingTime: "Not applicable",
-		MaxStayDays:    15,
-		Validity:       "15 days per entry",
-		Disclaimer:     "This is not legal advice. Visa exemption allows 15-day stay for Thai passport holders. Please verify with Japanese embassy.",
+	return []VisaRule{
+		// Thailand → Canada (Tourist)
+		rule("TH", "CA", "https://www.canada.ca/en/immigration-refugees-citizenship/services/visit-canada.html", VisaRequirement{
+			VisaRequired: true,
+			VisaType:     "Temporary Resident Visa (TRV)",
+			Checklist: []ChecklistItem{
+				{Item: "Valid passport", Notes: "Valid for at least 6 months beyond stay"},
+				{Item: "Completed application form", Notes: "IMM 5257 or IMM 5257E"},
+				{Item: "Passport photos", Notes: "2 recent photos (35mm x 45mm)"},
+				{Item: "Proof of financial support", Notes: "Bank statements for last 6 months"},
+				{Item: "Travel itinerary", Notes: "Flight bookings and accommodation"},
+				{Item: "Employment letter", Notes: "From current employer (if employed)"},
+				{Item: "Invitation letter", Notes: "If visiting family/friends"},
+			},
+			Forms: []FormInfo{
+				{Name: "IMM 5257 - Application for Visitor Visa", DownloadURL: "https://www.canada.ca/en/immigration-refugees-citizenship/services/application/application-forms-guides/imm5257e.html"},
+				{Name: "IMM 5645 - Family Information", DownloadURL: "https://www.canada.ca/en/immigration-refugees-citizenship/services/application/application-forms-guides/imm5645e.html"},
+			},
+			ProcessingTime: "14-21 days",
+			Fees:           &FeeInfo{Amount: 100, Currency: "CAD"},
+			Validity:       "Up to 10 years (multiple entry)",
+			MaxStayDays:    180,
+			Disclaimer:     "This is not legal advice. Please verify with official Canadian government sources at canada.ca",
+		}),
+
+		// Thailand → Japan (Tourist)
+		rule("TH", "JP", "https://www.mofa.go.jp/j_info/visit/visa/short/novisa.html", VisaRequirement{
+			VisaRequired: false,
+			VisaType:     "Visa Exemption",
+			Checklist: []ChecklistItem{
+				{Item: "Valid passport", Notes: "Valid for duration of stay"},
+				{Item: "Return ticket", Notes: "Proof of onward travel"},
+				{Item: "Proof of accommodation", Notes: "Hotel bookings or invitation letter"},
+				{Item: "Sufficient funds", Notes: "Approximately 100,000 JPY or equivalent"},
+			},
+			Forms:          []FormInfo{},
+			ProcessingTime: "Not applicable",
+			MaxStayDays:    15,
+			Validity:       "15 days per entry",
+			Disclaimer:     "This is not legal advice. Visa exemption allows 15-day stay for Thai passport holders. Please verify with Japanese embassy.",
+		}),
+
+		// Thailand → United States (Tourist)
+		rule("TH", "US", "https://th.usembassy.gov/visas/", VisaRequirement{
+			VisaRequired: true,
+			VisaType:     "B-2 Tourist Visa",
+			Checklist: []ChecklistItem{
+				{Item: "Valid passport", Notes: "Valid for at least 6 months beyond stay"},
+				{Item: "DS-160 form", Notes: "Online nonimmigrant visa application"},
+				{Item: "Passport photo", Notes: "Recent 2x2 inch photo"},
+				{Item: "Interview appointment", Notes: "Schedule at US Embassy Bangkok"},
+				{Item: "Proof of ties to Thailand", Notes: "Employment letter, property ownership, family ties"},
+				{Item: "Financial documents", Notes: "Bank statements, income tax returns"},
+				{Item: "Travel itinerary", Notes: "Detailed travel plans"},
+			},
+			Forms: []FormInfo{
+				{Name: "DS-160 - Online Nonimmigrant Visa Application", DownloadURL: "https://ceac.state.gov/genniv/"},
+			},
+			ProcessingTime: "3-5 weeks after interview",
+			Fees:           &FeeInfo{Amount: 185, Currency: "USD"},
+			Validity:       "Up to 10 years (multiple entry)",
+			MaxStayDays:    180,
+			Disclaimer:     "This is not legal advice. Please verify with the US Embassy in Bangkok at th.usembassy.gov",
+		}),
+
+		// Thailand → UK (Tourist)
+		rule("TH", "GB", "https://www.gov.uk/standard-visitor-visa", VisaRequirement{
+			VisaRequired: true,
+			VisaType:     "Standard Visitor Visa",
+			Checklist: []ChecklistItem{
+				{Item: "Valid passport", Notes: "Valid for at least 6 months"},
+				{Item: "Online application form", Notes: "Complete on gov.uk"},
+				{Item: "Passport photos", Notes: "Color photo 45mm x 35mm"},
+				{Item: "Financial evidence", Notes: "Bank statements for last 6 months"},
+				{Item: "Employment documents", Notes: "Letter from employer, payslips"},
+				{Item: "Accommodation proof", Notes: "Hotel bookings or invitation letter"},
+				{Item: "Travel itinerary", Notes: "Flight bookings"},
+				{Item: "Tuberculosis test", Notes: "From approved clinic if staying >6 months"},
+			},
+			Forms: []FormInfo{
+				{Name: "Online Visa Application", DownloadURL: "https://www.gov.uk/standard-visitor-visa"},
+			},
+			ProcessingTime: "15-21 working days",
+			Fees:           &FeeInfo{Amount: 115, Currency: "GBP"},
+			Validity:       "6 months",
+			MaxStayDays:    180,
+			Disclaimer:     "This is not legal advice. Please verify with UK Visas and Immigration at gov.uk",
+		}),
+
+		// USA → Thailand (Tourist - Visa Exemption Example)
+		rule("US", "TH", "https://www.thaiembdc.org/visa-exemption/", VisaRequirement{
+			VisaRequired: false,
+			VisaType:     "Visa Exemption",
+			Checklist: []ChecklistItem{
+				{Item: "Valid US passport", Notes: "Valid for at least 6 months"},
+				{Item: "Return ticket", Notes: "Proof of onward travel within 30 days"},
+				{Item: "Proof of accommodation", Notes: "Hotel booking or invitation letter"},
+			},
+			Forms:          []FormInfo{},
+			ProcessingTime: "Not applicable",
+			MaxStayDays:    30,
+			Validity:       "30 days per entry",
+			Disclaimer:     "This is not legal advice. US passport holders can stay visa-free for 30 days. Please verify with Thai embassy.",
+		}),
 	}
+}
 
-	// Thailand → United States (Tourist)
-	a.db["TH_US_tourism"] = &VisaRequirement{
-		VisaRequired: true,
-		VisaType:     "B-2 Tourist Visa",
-		Checklist: []ChecklistItem{
-			{Item: "Valid passport", Notes: "Valid for at least 6 months beyond stay"},
-			{Item: "DS-160 form", Notes: "Online nonimmigrant visa application"},
-			{Item: "Passport photo", Notes: "Recent 2x2 inch photo"},
-			{Item: "Interview appointment", Notes: "Schedule at US Embassy Bangkok"},
-			{Item: "Proof of ties to Thailand", Notes: "Employment letter, property ownership, family ties"},
-			{Item: "Financial documents", Notes: "Bank statements, income tax returns"},
-			{Item: "Travel itinerary", Notes: "Detailed travel plans"},
-		},
-		Forms: []FormInfo{
-			{Name: "DS-160 - Online Nonimmigrant Visa Application", DownloadURL: "https://ceac.state.gov/genniv/"},
-		},
-		ProcessingTime: "3-5 weeks after interview",
-		Fees:           &FeeInfo{Amount: 185, Currency: "USD"},
-		Validity:       "Up to 10 years (multiple entry)",
-		MaxStayDays:    180,
-		Disclaimer:     "This is not legal advice. Please verify with the US Embassy in Bangkok at th.usembassy.gov",
+// countryCodes maps English and Thai country, nationality and major city names to ISO country codes
+var countryCodes = map[string]string{
+	"thailand": "TH", "thai": "TH", "ไทย": "TH", "ประเทศไทย": "TH", "bangkok": "TH", "กรุงเทพ": "TH",
+	"phuket": "TH", "chiang mai": "TH",
+	"canada": "CA", "canadian": "CA", "แคนาดา": "CA", "vancouver": "CA", "toronto": "CA",
+	"japan": "JP", "japanese": "JP", "ญี่ปุ่น": "JP", "tokyo": "JP", "osaka": "JP", "kyoto": "JP",
+	"united states": "US", "usa": "US", "america": "US", "american": "US", "อเมริกา": "US", "new york": "US",
+	"united kingdom": "GB", "uk": "GB", "britain": "GB", "british": "GB", "england": "GB", "อังกฤษ": "GB",
+	"london": "GB",
+	"south korea": "KR", "korea": "KR", "korean": "KR", "เกาหลี": "KR", "seoul": "KR",
+	"singapore": "SG", "singaporean": "SG", "สิงคโปร์": "SG",
+	"france": "FR", "french": "FR", "ฝรั่งเศส": "FR", "paris": "FR",
+	"germany": "DE", "german": "DE", "เยอรมนี": "DE", "berlin": "DE", "munich": "DE",
+	"italy": "IT", "italian": "IT", "อิตาลี": "IT", "rome": "IT", "milan": "IT",
+	"spain": "ES", "spanish": "ES", "สเปน": "ES", "madrid": "ES", "barcelona": "ES",
+	"netherlands": "NL", "dutch": "NL", "เนเธอร์แลนด์": "NL", "amsterdam": "NL",
+	"switzerland": "CH", "swiss": "CH", "สวิตเซอร์แลนด์": "CH", "zurich": "CH",
+	"australia": "AU", "australian": "AU", "ออสเตรเลีย": "AU", "sydney": "AU",
+	"vietnam": "VN", "vietnamese": "VN", "เวียดนาม": "VN", "hanoi": "VN",
+	"taiwan": "TW", "ไต้หวัน": "TW", "taipei": "TW",
+	"hong kong": "HK", "ฮ่องกง": "HK",
+	"china": "CN", "chinese": "CN", "จีน": "CN",
+}
+
+// NormalizeCountry converts a country, nationality or city name to its ISO country code.
+// Two-letter codes are upper-cased; unknown names are returned trimmed.
+func NormalizeCountry(name string) string {
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	if code, ok := countryCodes[lower]; ok {
+		return code
+	}
+	if len(name) == 2 {
+		return strings.ToUpper(name)
 	}
+	return name
+}
 
-	// Thailand → UK (Tourist)
-	a.db["TH_GB_tourism"] = &VisaRequirement{
-		VisaRequired: true,
-		VisaType:     "Standard Visitor Visa",
-		Checklist: []ChecklistItem{
-			{Item: "Valid passport", Notes: "Valid for at least 6 months"},
-			{Item: "Online application form", Notes: "Complete on gov.uk"},
-			{Item: "Passport photos", Notes: "Color photo 45mm x 35mm"},
-			{Item: "Financial evidence", Notes: "Bank statements for last 6 months"},
-			{Item: "Employment documents", Notes: "Letter from employer, payslips"},
-			{Item: "Accommodation proof", Notes: "Hotel bookings or invitation letter"},
-			{Item: "Travel itinerary", Notes: "Flight bookings"},
-			{Item: "Tuberculosis test", Notes: "From approved clinic if staying >6 months"},
-		},
-		Forms: []FormInfo{
-			{Name: "Online Visa Application", DownloadURL: "https://www.gov.uk/standard-visitor-visa"},
-		},
-		ProcessingTime: "15-21 working days",
-		Fees:           &FeeInfo{Amount: 115, Currency: "GBP"},
-		Validity:       "6 months",
-		MaxStayDays:    180,
-		Disclaimer:     "This is not legal advice. Please verify with UK Visas and Immigration at gov.uk",
+// NormalizeVisaPurpose maps a free-text purpose to tourism, business, study or transit (default tourism)
+func NormalizeVisaPurpose(purpose string) string {
+	lower := strings.ToLower(strings.TrimSpace(purpose))
+	switch {
+	case strings.Contains(lower, "business") || strings.Contains(lower, "work") || strings.Contains(lower, "ธุรกิจ"):
+		return "business"
+	case strings.Contains(lower, "study") || strings.Contains(lower, "student") || strings.Contains(lower, "เรียน"):
+		return "study"
+	case strings.Contains(lower, "transit") || strings.Contains(lower, "ต่อเครื่อง"):
+		return "transit"
 	}
+	return "tourism"
+}
 
-	// USA → Thailand (Tourist - Visa Exemption Example)
-	a.db["US_TH_tourism"] = &VisaRequirement{
-		VisaRequired: false,
-		VisaType:     "Visa Exemption",
-		Checklist: []ChecklistItem{
-			{Item: "Valid US passport", Notes: "Valid for at least 6 months"},
-			{Item: "Return ticket", Notes: "Proof of onward travel within 30 days"},
-			{Item: "Proof of accommodation", Notes: "Hotel booking or invitation letter"},
-		},
-		Forms:          []FormInfo{},
-		ProcessingTime: "Not applicable",
-		MaxStayDays:    30,
-		Validity:       "30 days per entry",
-		Disclaimer:     "This is not legal advice. US passport holders can stay visa-free for 30 days. Please verify with Thai embassy.",
+// Name implements Agent
+func (a *VisaDocAgent) Name() string { return "visa" }
+
+// SupportedIntents implements Agent
+func (a *VisaDocAgent) SupportedIntents() []string { return []string{"visa_check"} }
+
+// Handle implements Agent for visa_check intents.
+// Nationality defaults to Thai and purpose to tourism; stay_days falls back to the trip duration.
+func (a *VisaDocAgent) Handle(ctx context.Context, intent *IntentResult) (Result, error) {
+	nationality := stringEntity(intent.Entities, "nationality", "TH")
+	destination := stringEntity(intent.Entities, "destination", "")
+	purpose := stringEntity(intent.Entities, "purpose", "tourism")
+	stayDays := intEntity(intent.Entities, "stay_days", intEntity(intent.Entities, "duration", 0))
+
+	if destination == "" {
+		return &VisaCheckResult{Message: "Which country are you travelling to? For example: 'Do Thai citizens need a visa for Japan?'"}, nil
+	}
+
+	requirement, err := a.CheckVisa(ctx, nationality, destination, stayDays, purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	return &VisaCheckResult{
+		Nationality: NormalizeCountry(nationality),
+		Destination: NormalizeCountry(destination),
+		Purpose:     NormalizeVisaPurpose(purpose),
+		StayDays:    stayDays,
+		Requirement: requirement,
+	}, nil
+}
+
+// VisaCheckResult is the visa requirement for a trip, rendered as a checklist for chat
+type VisaCheckResult struct {
+	Nationality string           `json:"nationality,omitempty"`
+	Destination string           `json:"destination,omitempty"`
+	Purpose     string           `json:"purpose,omitempty"`
+	StayDays    int              `json:"stay_days,omitempty"`
+	Requirement *VisaRequirement `json:"requirement,omitempty"`
+	Message     string           `json:"message,omitempty"`
+	// LocalFee is the visa fee in the display currency, when it differs from the fee's currency
+	LocalFee *currency.Money `json:"local_fee,omitempty"`
+}
+
+// Type implements Result
+func (r *VisaCheckResult) Type() string { return "visa_requirement" }
+
+// Markdown implements Result
+func (r *VisaCheckResult) Markdown() string {
+	if r.Requirement == nil {
+		return r.Message
 	}
 
-	log.Printf("VisaDocAgent: Initialized with %%d visa requirement entries", len(a.db))
+	req := r.Requirement
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# Visa Requirements: %s → %s\n\n", r.Nationality, r.Destination))
+	if req.VisaRequired {
+		md.WriteString(fmt.Sprintf("**Visa required:** Yes (%s)\n", req.VisaType))
+	} else {
+		md.WriteString(fmt.Sprintf("**Visa required:** No (%s)\n", req.VisaType))
+	}
+	if req.MaxStayDays > 0 {
+		md.WriteString(fmt.Sprintf("**Maximum stay:** %d days\n", req.MaxStayDays))
+	}
+	if r.StayDays > 0 {
+		md.WriteString(fmt.Sprintf("**Your stay:** %d days\n", r.StayDays))
+	}
+	if req.ProcessingTime != "" {
+		md.WriteString(fmt.Sprintf("**Processing time:** %s\n", req.ProcessingTime))
+	}
+	if req.Validity != "" {
+		md.WriteString(fmt.Sprintf("**Validity:** %s\n", req.Validity))
+	}
+
+	if len(req.Warnings) > 0 {
+		md.WriteString("\n## Warnings\n")
+		for _, warning := range req.Warnings {
+			md.WriteString(fmt.Sprintf("- ⚠️ %s\n", warning))
+		}
+	}
+
+	if len(req.Checklist) > 0 {
+		md.WriteString("\n## Document Checklist\n")
+		for _, item := range req.Checklist {
+			if item.Notes != "" {
+				md.WriteString(fmt.Sprintf("- [ ] %s - %s\n", item.Item, item.Notes))
+			} else {
+				md.WriteString(fmt.Sprintf("- [ ] %s\n", item.Item))
+			}
+		}
+	}
+
+	if len(req.Forms) > 0 {
+		md.WriteString("\n## Forms\n")
+		for _, form := range req.Forms {
+			md.WriteString(fmt.Sprintf("- [%s](%s)\n", form.Name, form.DownloadURL))
+		}
+	}
+
+	if req.Fees != nil {
+		md.WriteString("\n## Fees\n")
+		if r.LocalFee != nil {
+			md.WriteString(fmt.Sprintf("%.0f %s (≈ %s)\n", req.Fees.Amount, req.Fees.Currency, r.LocalFee))
+		} else {
+			md.WriteString(fmt.Sprintf("%.0f %s\n", req.Fees.Amount, req.Fees.Currency))
+		}
+	}
+
+	md.WriteString(fmt.Sprintf("\n---\n*%s*\n", req.Disclaimer))
+
+	return md.String()
+}
+
+// stringEntity reads a string entity, falling back when it is missing or empty
+func stringEntity(entities map[string]interface{}, key, defaultVal string) string {
+	if val, ok := entities[key].(string); ok && val != "" {
+		return val
+	}
+	return defaultVal
+}
+
+// intEntity reads a numeric entity as an int, falling back when it is missing or zero
+func intEntity(entities map[string]interface{}, key string, defaultVal int) int {
+	switch val := entities[key].(type) {
+	case float64:
+		if val > 0 {
+			return int(val)
+		}
+	case int:
+		if val > 0 {
+			return val
+		}
+	}
+	return defaultVal
 }
@@ -0,0 +1,348 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Visa verdicts for a single country
+const (
+	VisaVerdictVisaFree     = "visa_free"
+	VisaVerdictVisaRequired = "visa_required"
+	VisaVerdictStayTooLong  = "stay_exceeds_limit"
+	VisaVerdictTransit      = "transit"
+	VisaVerdictUnknown      = "unknown"
+)
+
+// schengenMaxDays is the maximum short stay in the Schengen area within any 180-day window
+const schengenMaxDays = 90
+
+// passportValidityMonths is the passport validity most countries require beyond the return date
+const passportValidityMonths = 6
+
+// maxTransitHours is the longest connection treated as transit rather than an entry
+const maxTransitHours = 24
+
+// schengenCountries are the members of the Schengen area
+var schengenCountries = map[string]bool{
+	"AT": true, "BE": true, "BG": true, "CH": true, "CZ": true, "DE": true, "DK": true, "EE": true,
+	"ES": true, "FI": true, "FR": true, "GR": true, "HR": true, "HU": true, "IS": true, "IT": true,
+	"LI": true, "LT": true, "LU": true, "LV": true, "MT": true, "NL": true, "NO": true, "PL": true,
+	"PT": true, "RO": true, "SE": true, "SI": true, "SK": true,
+}
+
+// transitVisaCountries require a visa even for connecting flights
+var transitVisaCountries = map[string]string{
+	"US": "C-1 Transit Visa or ESTA (the US has no airside transit)",
+	"CA": "Transit visa or eTA (Canada has no visa-free transit for most nationalities)",
+}
+
+// VisaItinerary is a trip through one or more countries
+type VisaItinerary struct {
+	Nationality    string     `json:"nationality"`
+	PassportExpiry *time.Time `json:"passport_expiry,omitempty"`
+	ReturnDate     *time.Time `json:"return_date,omitempty"`
+	Stops          []VisaStop `json:"stops"`
+}
+
+// VisaStop is a visit to one country. The exit date defaults to the next stop's entry date,
+// then to the return date, then to EntryDate + StayDays.
+type VisaStop struct {
+	Country   string    `json:"country"`
+	EntryDate time.Time `json:"entry_date"`
+	ExitDate  time.Time `json:"exit_date,omitempty"`
+	StayDays  int       `json:"stay_days,omitempty"`
+	Purpose   string    `json:"purpose,omitempty"`
+	Transit   bool      `json:"transit,omitempty"`
+}
+
+// CountryVisaVerdict is the visa outcome for one stop of an itinerary
+type CountryVisaVerdict struct {
+	Country     string           `json:"country"`
+	EntryDate   time.Time        `json:"entry_date"`
+	ExitDate    time.Time        `json:"exit_date"`
+	StayDays    int              `json:"stay_days"`
+	Transit     bool             `json:"transit"`
+	Verdict     string           `json:"verdict"`
+	Requirement *VisaRequirement `json:"requirement"`
+}
+
+// ItineraryVisaCheck is the visa outcome for a whole itinerary
+type ItineraryVisaCheck struct {
+	Nationality  string               `json:"nationality"`
+	VisaRequired bool                 `json:"visa_required"`
+	Countries    []CountryVisaVerdict `json:"countries"`
+	Warnings     []string             `json:"warnings"`
+}
+
+// CheckItinerary checks every stop of an itinerary and consolidates the warnings:
+// overstays, passport validity against the return date and the Schengen 90/180 rule
+func (a *VisaDocAgent) CheckItinerary(ctx context.Context, itinerary VisaItinerary) (*ItineraryVisaCheck, error) {
+	if len(itinerary.Stops) == 0 {
+		return nil, fmt.Errorf("itinerary has no stops")
+	}
+
+	nationality := NormalizeCountry(itinerary.Nationality)
+	log.Printf("VisaDocAgent: Checking %d-stop itinerary for %s", len(itinerary.Stops), nationality)
+
+	check := &ItineraryVisaCheck{
+		Nationality: nationality,
+		Countries:   make([]CountryVisaVerdict, 0, len(itinerary.Stops)),
+		Warnings:    []string{},
+	}
+
+	for i, stop := range itinerary.Stops {
+		if stop.EntryDate.IsZero() {
+			return nil, fmt.Errorf("stop %d (%s) has no entry date", i+1, stop.Country)
+		}
+
+		country := NormalizeCountry(stop.Country)
+		exit := stopExitDate(itinerary, i)
+		if exit.Before(stop.EntryDate) {
+			return nil, fmt.Errorf("stop %d (%s) ends before it starts", i+1, stop.Country)
+		}
+		stayDays := daysBetween(stop.EntryDate, exit)
+
+		// Long layovers are entries, not transits
+		purpose := NormalizeVisaPurpose(stop.Purpose)
+		transit := stop.Transit || purpose == "transit"
+		var transitWarning string
+		if transit && exit.Sub(stop.EntryDate) > maxTransitHours*time.Hour {
+			transitWarning = fmt.Sprintf("Your stop in %s lasts more than %d hours, so it is treated as an entry rather than a transit.", country, maxTransitHours)
+			transit = false
+			if purpose == "transit" {
+				purpose = "tourism"
+			}
+		} else if transit {
+			purpose = "transit"
+		}
+
+		requirement, err := a.findRequirement(ctx, nationality, country, stayDays, purpose, stop.EntryDate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", country, err)
+		}
+		evaluateStay(requirement, country, stayDays)
+		if transitWarning != "" {
+			requirement.Warnings = append([]string{transitWarning}, requirement.Warnings...)
+		}
+
+		if requirement.VisaRequired || requirement.Verdict == VisaVerdictStayTooLong {
+			check.VisaRequired = true
+		}
+		check.Warnings = append(check.Warnings, requirement.Warnings...)
+		check.Countries = append(check.Countries, CountryVisaVerdict{
+			Country:     country,
+			EntryDate:   stop.EntryDate,
+			ExitDate:    exit,
+			StayDays:    stayDays,
+			Transit:     transit,
+			Verdict:     requirement.Verdict,
+			Requirement: requirement,
+		})
+	}
+
+	check.Warnings = append(check.Warnings, schengenWarnings(nationality, check.Countries)...)
+	check.Warnings = append(check.Warnings, passportWarnings(itinerary, check.Countries)...)
+
+	return check, nil
+}
+
+// stopExitDate works out when the traveller leaves the i-th stop
+func stopExitDate(itinerary VisaItinerary, i int) time.Time {
+	stop := itinerary.Stops[i]
+	switch {
+	case !stop.ExitDate.IsZero():
+		return stop.ExitDate
+	case stop.StayDays > 0:
+		return stop.EntryDate.AddDate(0, 0, stop.StayDays-1)
+	case i+1 < len(itinerary.Stops) && !itinerary.Stops[i+1].EntryDate.IsZero():
+		return itinerary.Stops[i+1].EntryDate
+	case itinerary.ReturnDate != nil:
+		return *itinerary.ReturnDate
+	}
+	return stop.EntryDate
+}
+
+// daysBetween counts calendar days from entry to exit, both included
+func daysBetween(entry, exit time.Time) int {
+	entryDay := time.Date(entry.Year(), entry.Month(), entry.Day(), 0, 0, 0, 0, time.UTC)
+	exitDay := time.Date(exit.Year(), exit.Month(), exit.Day(), 0, 0, 0, 0, time.UTC)
+	return int(exitDay.Sub(entryDay).Hours()/24) + 1
+}
+
+// evaluateStay sets the verdict for a stay and warns about overstays
+func evaluateStay(requirement *VisaRequirement, country string, stayDays int) {
+	switch {
+	case requirement.Source == VisaSourceFallback:
+		requirement.Verdict = VisaVerdictUnknown
+	case requirement.Verdict == VisaVerdictTransit:
+		// Set by the transit rules
+	case requirement.VisaRequired:
+		requirement.Verdict = VisaVerdictVisaRequired
+	default:
+		requirement.Verdict = VisaVerdictVisaFree
+	}
+
+	if stayDays <= 0 || requirement.MaxStayDays <= 0 {
+		return
+	}
+
+	allowance := requirement.VisaType
+	if allowance == "" {
+		allowance = "entry"
+	}
+
+	switch {
+	case stayDays > requirement.MaxStayDays:
+		requirement.Verdict = VisaVerdictStayTooLong
+		requirement.Warnings = append(requirement.Warnings, fmt.Sprintf(
+			"Overstay risk: your %d-day stay in %s is longer than the %d days allowed by %s. Apply for a visa that covers the full stay or shorten your trip.",
+			stayDays, country, requirement.MaxStayDays, allowance))
+	case stayDays == requirement.MaxStayDays:
+		requirement.Warnings = append(requirement.Warnings, fmt.Sprintf(
+			"Your %d-day stay in %s uses the full allowance of %s; a delayed flight could mean overstaying.",
+			stayDays, country, allowance))
+	}
+}
+
+// transitRequirement answers a transit check from the destination's entry rules
+func (a *VisaDocAgent) transitRequirement(ctx context.Context, nationality, country string, on time.Time) (*VisaRequirement, error) {
+	checklist := []ChecklistItem{
+		{Item: "Valid passport", Notes: "Valid for the whole journey"},
+		{Item: "Onward ticket", Notes: "Confirmed connecting flight within 24 hours"},
+	}
+
+	if visaType, ok := transitVisaCountries[country]; ok {
+		return &VisaRequirement{
+			VisaRequired: true,
+			VisaType:     visaType,
+			Checklist:    checklist,
+			Forms:        []FormInfo{},
+			Verdict:      VisaVerdictTransit,
+			Source:       VisaSourceSeed,
+			Verified:     true,
+			Disclaimer:   "This is not legal advice. Please verify transit rules with the airline and the embassy of " + country + ".",
+		}, nil
+	}
+
+	// Visa-free entry also covers a connection
+	rule, err := a.store.FindRule(ctx, nationality, country, "tourism", on)
+	if err != nil {
+		log.Printf("VisaDocAgent: Failed to look up visa rule: %v", err)
+	}
+	if rule != nil && !rule.Requirement.VisaRequired {
+		return &VisaRequirement{
+			VisaRequired: false,
+			VisaType:     "Visa-free transit",
+			Checklist:    checklist,
+			Forms:        []FormInfo{},
+			Verdict:      VisaVerdictTransit,
+			Source:       rule.Source,
+			SourceURL:    rule.SourceURL,
+			Verified:     rule.Verified,
+			Disclaimer:   rule.Requirement.Disclaimer,
+		}, nil
+	}
+
+	return &VisaRequirement{
+		VisaRequired: false,
+		VisaType:     "Airside transit",
+		Checklist:    checklist,
+		Forms:        []FormInfo{},
+		Verdict:      VisaVerdictTransit,
+		Source:       VisaSourceFallback,
+		Warnings: []string{fmt.Sprintf(
+			"Airside transit in %s is usually visa-free only if you stay in the international area and do not change airports; confirm with your airline.", country)},
+		Disclaimer: "This is not legal advice. Please verify transit rules with the airline and the embassy of " + country + ".",
+	}, nil
+}
+
+// schengenWarnings applies the 90/180 rule across all Schengen stops
+func schengenWarnings(nationality string, countries []CountryVisaVerdict) []string {
+	if schengenCountries[nationality] {
+		return nil
+	}
+
+	// Collect every calendar day spent in the Schengen area
+	days := make(map[time.Time]bool)
+	var visited []string
+	for _, country := range countries {
+		if !schengenCountries[country.Country] || country.Transit {
+			continue
+		}
+		visited = append(visited, country.Country)
+		for day := truncateDay(country.EntryDate); !day.After(truncateDay(country.ExitDate)); day = day.AddDate(0, 0, 1) {
+			days[day] = true
+		}
+	}
+	if len(days) <= schengenMaxDays {
+		return nil
+	}
+
+	// Find the busiest 180-day window ending on any Schengen day
+	worst := 0
+	var worstEnd time.Time
+	for end := range days {
+		count := 0
+		for day := range days {
+			if !day.After(end) && day.After(end.AddDate(0, 0, -180)) {
+				count++
+			}
+		}
+		if count > worst || (count == worst && end.Before(worstEnd)) {
+			worst = count
+			worstEnd = end
+		}
+	}
+	if worst <= schengenMaxDays {
+		return nil
+	}
+
+	return []string{fmt.Sprintf(
+		"Schengen 90/180 rule: your stays in %s add up to %d days in the 180 days up to %s, more than the %d days allowed.",
+		strings.Join(uniqueStrings(visited), ", "), worst, worstEnd.Format("2006-01-02"), schengenMaxDays)}
+}
+
+// passportWarnings checks the passport expiry against the return date
+func passportWarnings(itinerary VisaItinerary, countries []CountryVisaVerdict) []string {
+	if itinerary.PassportExpiry == nil {
+		return nil
+	}
+
+	returnDate := countries[len(countries)-1].ExitDate
+	if itinerary.ReturnDate != nil {
+		returnDate = *itinerary.ReturnDate
+	}
+	expiry := *itinerary.PassportExpiry
+
+	if expiry.Before(returnDate) {
+		return []string{fmt.Sprintf("Your passport expires on %s, before your return on %s. Renew it before you travel.",
+			expiry.Format("2006-01-02"), returnDate.Format("2006-01-02"))}
+	}
+	if expiry.Before(returnDate.AddDate(0, passportValidityMonths, 0)) {
+		return []string{fmt.Sprintf("Your passport expires on %s, less than %d months after your return on %s. Many countries refuse entry in that case; consider renewing it.",
+			expiry.Format("2006-01-02"), passportValidityMonths, returnDate.Format("2006-01-02"))}
+	}
+	return nil
+}
+
+// truncateDay drops the time of day
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// uniqueStrings removes duplicates, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,183 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisaDocAgent_CheckVisaEvaluatesStay(t *testing.T) {
+	agent := NewVisaDocAgent("")
+	ctx := context.Background()
+
+	t.Run("Stay within the exemption", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "JP", 10, "tourism")
+		require.NoError(t, err)
+		assert.Equal(t, VisaVerdictVisaFree, requirement.Verdict)
+		assert.Empty(t, requirement.Warnings)
+	})
+
+	t.Run("Stay longer than the exemption", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "JP", 30, "tourism")
+		require.NoError(t, err)
+		assert.Equal(t, VisaVerdictStayTooLong, requirement.Verdict)
+		require.Len(t, requirement.Warnings, 1)
+		assert.Contains(t, requirement.Warnings[0], "30-day stay in JP is longer than the 15 days")
+	})
+
+	t.Run("Stay uses the full allowance", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "JP", 15, "tourism")
+		require.NoError(t, err)
+		assert.Equal(t, VisaVerdictVisaFree, requirement.Verdict)
+		require.Len(t, requirement.Warnings, 1)
+		assert.Contains(t, requirement.Warnings[0], "full allowance")
+	})
+
+	t.Run("Unknown route", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "Peru", 10, "tourism")
+		require.NoError(t, err)
+		assert.Equal(t, VisaVerdictUnknown, requirement.Verdict)
+	})
+}
+
+func TestVisaDocAgent_CheckVisaTransit(t *testing.T) {
+	agent := NewVisaDocAgent("")
+	ctx := context.Background()
+
+	t.Run("Visa-free destination", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "JP", 0, "transit")
+		require.NoError(t, err)
+		assert.False(t, requirement.VisaRequired)
+		assert.Equal(t, VisaVerdictTransit, requirement.Verdict)
+		assert.Equal(t, VisaSourceSeed, requirement.Source)
+	})
+
+	t.Run("Country without airside transit", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "US", 0, "transit")
+		require.NoError(t, err)
+		assert.True(t, requirement.VisaRequired)
+		assert.Contains(t, requirement.VisaType, "C-1")
+	})
+
+	t.Run("Unknown country warns to check with the airline", func(t *testing.T) {
+		requirement, err := agent.CheckVisa(ctx, "TH", "SG", 0, "transit")
+		require.NoError(t, err)
+		assert.False(t, requirement.VisaRequired)
+		assert.False(t, requirement.Verified)
+		require.Len(t, requirement.Warnings, 1)
+		assert.Contains(t, requirement.Warnings[0], "airline")
+	})
+}
+
+func TestVisaDocAgent_CheckItinerary(t *testing.T) {
+	agent := NewVisaDocAgent("")
+	ctx := context.Background()
+
+	t.Run("Per-country verdicts", func(t *testing.T) {
+		returnDate := date("2025-03-25")
+		check, err := agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality: "Thai",
+			ReturnDate:  &returnDate,
+			Stops: []VisaStop{
+				{Country: "Japan", EntryDate: date("2025-03-01")},
+				{Country: "United States", EntryDate: date("2025-03-20"), Transit: true},
+				{Country: "Canada", EntryDate: date("2025-03-20")},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, check.Countries, 3)
+
+		japan := check.Countries[0]
+		assert.Equal(t, "JP", japan.Country)
+		assert.Equal(t, 20, japan.StayDays)
+		assert.Equal(t, VisaVerdictStayTooLong, japan.Verdict)
+
+		assert.Equal(t, "US", check.Countries[1].Country)
+		assert.True(t, check.Countries[1].Transit)
+		assert.Equal(t, VisaVerdictTransit, check.Countries[1].Verdict)
+
+		assert.Equal(t, VisaVerdictVisaRequired, check.Countries[2].Verdict)
+		assert.Equal(t, 6, check.Countries[2].StayDays)
+
+		assert.True(t, check.VisaRequired)
+		assert.Contains(t, check.Warnings[0], "20-day stay in JP")
+	})
+
+	t.Run("Long layover counts as an entry", func(t *testing.T) {
+		check, err := agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality: "TH",
+			Stops: []VisaStop{
+				{Country: "JP", EntryDate: date("2025-03-01"), ExitDate: date("2025-03-03"), Transit: true},
+			},
+		})
+		require.NoError(t, err)
+		assert.False(t, check.Countries[0].Transit)
+		assert.Equal(t, VisaVerdictVisaFree, check.Countries[0].Verdict)
+		assert.Contains(t, check.Warnings[0], "more than 24 hours")
+	})
+
+	t.Run("Schengen 90/180 aggregation", func(t *testing.T) {
+		check, err := agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality: "TH",
+			Stops: []VisaStop{
+				{Country: "France", EntryDate: date("2025-01-01"), ExitDate: date("2025-02-19")},
+				{Country: "Japan", EntryDate: date("2025-02-20"), ExitDate: date("2025-02-28")},
+				{Country: "Germany", EntryDate: date("2025-03-01"), ExitDate: date("2025-04-15")},
+			},
+		})
+		require.NoError(t, err)
+
+		var schengen string
+		for _, warning := range check.Warnings {
+			if strings.HasPrefix(warning, "Schengen") {
+				schengen = warning
+			}
+		}
+		require.NotEmpty(t, schengen)
+		assert.Contains(t, schengen, "FR, DE")
+		assert.Contains(t, schengen, "96 days")
+	})
+
+	t.Run("Schengen stays within the limit", func(t *testing.T) {
+		check, err := agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality: "TH",
+			Stops: []VisaStop{
+				{Country: "France", EntryDate: date("2025-01-01"), ExitDate: date("2025-02-15")},
+				{Country: "Germany", EntryDate: date("2025-07-01"), ExitDate: date("2025-08-15")},
+			},
+		})
+		require.NoError(t, err)
+		for _, warning := range check.Warnings {
+			assert.NotContains(t, warning, "Schengen")
+		}
+	})
+
+	t.Run("Passport validity", func(t *testing.T) {
+		expiry := date("2025-06-01")
+		check, err := agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality:    "TH",
+			PassportExpiry: &expiry,
+			Stops:          []VisaStop{{Country: "JP", EntryDate: date("2025-03-01"), StayDays: 7}},
+		})
+		require.NoError(t, err)
+		require.Len(t, check.Warnings, 1)
+		assert.Contains(t, check.Warnings[0], "less than 6 months after your return on 2025-03-07")
+
+		expiry = date("2025-03-05")
+		check, err = agent.CheckItinerary(ctx, VisaItinerary{
+			Nationality:    "TH",
+			PassportExpiry: &expiry,
+			Stops:          []VisaStop{{Country: "JP", EntryDate: date("2025-03-01"), StayDays: 7}},
+		})
+		require.NoError(t, err)
+		assert.Contains(t, check.Warnings[0], "before your return")
+	})
+
+	t.Run("Rejects empty itineraries", func(t *testing.T) {
+		_, err := agent.CheckItinerary(ctx, VisaItinerary{Nationality: "TH"})
+		assert.Error(t, err)
+	})
+}
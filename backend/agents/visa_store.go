@@ -0,0 +1,291 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Visa rule sources
+const (
+	VisaSourceSeed   = "seed"
+	VisaSourceImport = "import"
+	VisaSourceLLM    = "llm"
+	// VisaSourceFallback marks the generic answer given when nothing is known about a route
+	VisaSourceFallback = "fallback"
+)
+
+// VisaRule is a visa requirement for a route, valid over a date range
+type VisaRule struct {
+	ID            int             `json:"id,omitempty"`
+	Nationality   string          `json:"nationality"`
+	Destination   string          `json:"destination"`
+	Purpose       string          `json:"purpose"`
+	Requirement   VisaRequirement `json:"requirement"`
+	EffectiveFrom time.Time       `json:"effective_from"`
+	EffectiveTo   *time.Time      `json:"effective_to,omitempty"`
+	SourceURL     string          `json:"source_url,omitempty"`
+	LastVerified  *time.Time      `json:"last_verified,omitempty"`
+	Source        string          `json:"source"`
+	Verified      bool            `json:"verified"`
+}
+
+// ActiveOn reports whether the rule applies on the given day
+func (r *VisaRule) ActiveOn(day time.Time) bool {
+	if day.Before(r.EffectiveFrom) {
+		return false
+	}
+	return r.EffectiveTo == nil || !day.After(*r.EffectiveTo)
+}
+
+// Answer returns the rule's requirement with its provenance filled in.
+// Unverified answers carry a warning in the disclaimer, saying whether they came from AI.
+func (r *VisaRule) Answer() *VisaRequirement {
+	answer := r.Requirement
+	answer.Checklist = append([]ChecklistItem(nil), r.Requirement.Checklist...)
+	answer.Forms = append([]FormInfo(nil), r.Requirement.Forms...)
+	if answer.Forms == nil {
+		answer.Forms = []FormInfo{}
+	}
+	answer.Verified = r.Verified
+	answer.Source = r.Source
+	answer.SourceURL = r.SourceURL
+	answer.LastVerified = r.LastVerified
+	if !r.Verified {
+		notice := unverifiedNotice
+		if r.Source == VisaSourceLLM {
+			notice = generatedNotice
+		}
+		if !strings.HasPrefix(answer.Disclaimer, notice) {
+			answer.Disclaimer = notice + answer.Disclaimer
+		}
+	}
+	return &answer
+}
+
+// VisaStore persists visa rules
+type VisaStore interface {
+	// FindRule returns the rule for a route active on the given day, preferring verified rules,
+	// or nil if there is none
+	FindRule(ctx context.Context, nationality, destination, purpose string, on time.Time) (*VisaRule, error)
+	// SaveRule inserts or replaces the rule with the same route and effective-from date.
+	// An unverified rule never replaces a verified one.
+	SaveRule(ctx context.Context, rule *VisaRule) error
+}
+
+// MemoryVisaStore keeps visa rules in process memory
+type MemoryVisaStore struct {
+	mu    sync.RWMutex
+	rules []VisaRule
+}
+
+// NewMemoryVisaStore creates an in-memory store holding the given rules
+func NewMemoryVisaStore(rules ...VisaRule) *MemoryVisaStore {
+	store := &MemoryVisaStore{}
+	for i := range rules {
+		store.save(&rules[i])
+	}
+	return store
+}
+
+// FindRule implements VisaStore
+func (s *MemoryVisaStore) FindRule(ctx context.Context, nationality, destination, purpose string, on time.Time) (*VisaRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var best *VisaRule
+	for i := range s.rules {
+		rule := &s.rules[i]
+		if rule.Nationality != nationality || rule.Destination != destination || rule.Purpose != purpose || !rule.ActiveOn(on) {
+			continue
+		}
+		if best == nil || preferRule(rule, best) {
+			best = rule
+		}
+	}
+
+	if best == nil {
+		return nil, nil
+	}
+	found := *best
+	return &found, nil
+}
+
+// SaveRule implements VisaStore
+func (s *MemoryVisaStore) SaveRule(ctx context.Context, rule *VisaRule) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.save(rule)
+	return nil
+}
+
+// save stores a rule; the caller holds the lock
+func (s *MemoryVisaStore) save(rule *VisaRule) {
+	for i := range s.rules {
+		existing := &s.rules[i]
+		if existing.Nationality == rule.Nationality && existing.Destination == rule.Destination &&
+			existing.Purpose == rule.Purpose && existing.EffectiveFrom.Equal(rule.EffectiveFrom) {
+			if existing.Verified && !rule.Verified {
+				return
+			}
+			rule.ID = existing.ID
+			*existing = *rule
+			return
+		}
+	}
+	rule.ID = len(s.rules) + 1
+	s.rules = append(s.rules, *rule)
+}
+
+// preferRule reports whether a should be served instead of b: verified first, then the most recent
+func preferRule(a, b *VisaRule) bool {
+	if a.Verified != b.Verified {
+		return a.Verified
+	}
+	return a.EffectiveFrom.After(b.EffectiveFrom)
+}
+
+// visaRuleRecord is the file format for bulk-loading rules
+type visaRuleRecord struct {
+	Nationality   string          `json:"nationality"`
+	Destination   string          `json:"destination"`
+	Purpose       string          `json:"purpose"`
+	EffectiveFrom string          `json:"effective_from"`
+	EffectiveTo   string          `json:"effective_to"`
+	SourceURL     string          `json:"source_url"`
+	LastVerified  string          `json:"last_verified"`
+	Verified      *bool           `json:"verified"`
+	Requirement   json.RawMessage `json:"requirement"`
+}
+
+// LoadVisaRulesFile reads visa rules from a .yaml, .yml or .json file
+func LoadVisaRulesFile(path string) ([]VisaRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read visa rules: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return ParseVisaRulesYAML(data)
+	case ".json":
+		return ParseVisaRulesJSON(data)
+	}
+	return nil, fmt.Errorf("unsupported visa rules file %q: use .yaml, .yml or .json", path)
+}
+
+// ParseVisaRulesYAML parses a YAML document with a top-level "rules" list
+func ParseVisaRulesYAML(data []byte) ([]VisaRule, error) {
+	// Convert to JSON so requirements use the same field names as the API
+	var document interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
+	}
+	jsonData, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	return ParseVisaRulesJSON(jsonData)
+}
+
+// ParseVisaRulesJSON parses a JSON document with a top-level "rules" list
+func ParseVisaRulesJSON(data []byte) ([]VisaRule, error) {
+	var document struct {
+		Rules []visaRuleRecord `json:"rules"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	rules := make([]VisaRule, 0, len(document.Rules))
+	for i, record := range document.Rules {
+		rule, err := record.toRule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// toRule validates a file record and converts it to a rule
+func (r visaRuleRecord) toRule() (VisaRule, error) {
+	rule := VisaRule{
+		Nationality: NormalizeCountry(r.Nationality),
+		Destination: NormalizeCountry(r.Destination),
+		Purpose:     NormalizeVisaPurpose(r.Purpose),
+		SourceURL:   r.SourceURL,
+		Source:      VisaSourceImport,
+	}
+	if rule.Nationality == "" || rule.Destination == "" {
+		return rule, fmt.Errorf("nationality and destination are required")
+	}
+
+	if len(r.Requirement) == 0 {
+		return rule, fmt.Errorf("requirement is required")
+	}
+	if err := json.Unmarshal(r.Requirement, &rule.Requirement); err != nil {
+		return rule, fmt.Errorf("invalid requirement: %w", err)
+	}
+	if rule.Requirement.Disclaimer == "" {
+		rule.Requirement.Disclaimer = "This is not legal advice. Please verify with official government sources."
+	}
+
+	var err error
+	if rule.EffectiveFrom, err = parseRuleDate(r.EffectiveFrom); err != nil {
+		return rule, fmt.Errorf("invalid effective_from: %w", err)
+	}
+	if rule.EffectiveFrom.IsZero() {
+		return rule, fmt.Errorf("effective_from is required")
+	}
+	if r.EffectiveTo != "" {
+		effectiveTo, err := parseRuleDate(r.EffectiveTo)
+		if err != nil {
+			return rule, fmt.Errorf("invalid effective_to: %w", err)
+		}
+		if effectiveTo.Before(rule.EffectiveFrom) {
+			return rule, fmt.Errorf("effective_to is before effective_from")
+		}
+		rule.EffectiveTo = &effectiveTo
+	}
+	if r.LastVerified != "" {
+		lastVerified, err := parseRuleDate(r.LastVerified)
+		if err != nil {
+			return rule, fmt.Errorf("invalid last_verified: %w", err)
+		}
+		rule.LastVerified = &lastVerified
+	}
+	// Imports are unreviewed unless they say when they were checked, so a later import can still
+	// replace them
+	rule.Verified = r.Verified != nil && *r.Verified && rule.LastVerified != nil
+
+	return rule, nil
+}
+
+// parseRuleDate accepts YYYY-MM-DD or RFC 3339 timestamps; an empty string is the zero time
+func parseRuleDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if day, err := time.Parse("2006-01-02", value); err == nil {
+		return day, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// ImportVisaRules saves rules into a store and returns how many were saved
+func ImportVisaRules(ctx context.Context, store VisaStore, rules []VisaRule) (int, error) {
+	for i := range rules {
+		if err := store.SaveRule(ctx, &rules[i]); err != nil {
+			return i, fmt.Errorf("failed to save %s→%s rule: %w", rules[i].Nationality, rules[i].Destination, err)
+		}
+	}
+	return len(rules), nil
+}
@@ -0,0 +1,197 @@
+package agents
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(value string) time.Time {
+	day, _ := time.Parse("2006-01-02", value)
+	return day
+}
+
+func TestMemoryVisaStore_FindRule(t *testing.T) {
+	ctx := context.Background()
+	oldEnd := date("2024-12-31")
+	store := NewMemoryVisaStore(
+		VisaRule{Nationality: "TH", Destination: "KR", Purpose: "tourism", EffectiveFrom: date("2024-01-01"), EffectiveTo: &oldEnd,
+			Verified: true, Requirement: VisaRequirement{VisaType: "old"}},
+		VisaRule{Nationality: "TH", Destination: "KR", Purpose: "tourism", EffectiveFrom: date("2025-01-01"),
+			Verified: true, Requirement: VisaRequirement{VisaType: "new"}},
+	)
+
+	rule, err := store.FindRule(ctx, "TH", "KR", "tourism", date("2024-06-01"))
+	require.NoError(t, err)
+	require.NotNil(t, rule)
+	assert.Equal(t, "old", rule.Requirement.VisaType)
+
+	rule, err = store.FindRule(ctx, "TH", "KR", "tourism", date("2025-06-01"))
+	require.NoError(t, err)
+	assert.Equal(t, "new", rule.Requirement.VisaType)
+
+	rule, err = store.FindRule(ctx, "TH", "KR", "tourism", date("2023-06-01"))
+	require.NoError(t, err)
+	assert.Nil(t, rule, "No rule was effective yet")
+
+	rule, err = store.FindRule(ctx, "TH", "KR", "business", date("2025-06-01"))
+	require.NoError(t, err)
+	assert.Nil(t, rule)
+}
+
+func TestMemoryVisaStore_VerifiedRulesWin(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryVisaStore(VisaRule{
+		Nationality: "TH", Destination: "JP", Purpose: "tourism", EffectiveFrom: date("2024-01-01"),
+		Verified: true, Requirement: VisaRequirement{VisaType: "official"},
+	})
+
+	// A newer LLM answer does not shadow the verified rule
+	require.NoError(t, store.SaveRule(ctx, &VisaRule{
+		Nationality: "TH", Destination: "JP", Purpose: "tourism", EffectiveFrom: date("2025-01-01"),
+		Source: VisaSourceLLM, Requirement: VisaRequirement{VisaType: "guess"},
+	}))
+	rule, err := store.FindRule(ctx, "TH", "JP", "tourism", date("2025-06-01"))
+	require.NoError(t, err)
+	assert.Equal(t, "official", rule.Requirement.VisaType)
+
+	// Nor can it overwrite the verified rule with the same effective date
+	require.NoError(t, store.SaveRule(ctx, &VisaRule{
+		Nationality: "TH", Destination: "JP", Purpose: "tourism", EffectiveFrom: date("2024-01-01"),
+		Source: VisaSourceLLM, Requirement: VisaRequirement{VisaType: "guess"},
+	}))
+	rule, err = store.FindRule(ctx, "TH", "JP", "tourism", date("2024-06-01"))
+	require.NoError(t, err)
+	assert.Equal(t, "official", rule.Requirement.VisaType)
+	assert.True(t, rule.Verified)
+}
+
+func TestVisaRule_Answer(t *testing.T) {
+	verifiedAt := date("2024-06-01")
+	rule := VisaRule{
+		SourceURL:    "https://example.gov",
+		LastVerified: &verifiedAt,
+		Source:       VisaSourceImport,
+		Verified:     true,
+		Requirement:  VisaRequirement{Disclaimer: "This is not legal advice."},
+	}
+
+	answer := rule.Answer()
+	assert.True(t, answer.Verified)
+	assert.Equal(t, "https://example.gov", answer.SourceURL)
+	assert.Equal(t, &verifiedAt, answer.LastVerified)
+	assert.Equal(t, "This is not legal advice.", answer.Disclaimer)
+
+	rule.Verified = false
+	answer = rule.Answer()
+	assert.False(t, answer.Verified)
+	assert.Equal(t, unverifiedNotice+"This is not legal advice.", answer.Disclaimer)
+	assert.NotContains(t, answer.Disclaimer, "AI", "Imported rules were not generated by AI")
+
+	rule.Source = VisaSourceLLM
+	answer = rule.Answer()
+	assert.Equal(t, generatedNotice+"This is not legal advice.", answer.Disclaimer)
+	assert.Equal(t, "This is not legal advice.", rule.Requirement.Disclaimer, "The stored rule is not modified")
+}
+
+func TestVisaDocAgent_ServesUnverifiedRulesWithWarning(t *testing.T) {
+	agent := NewVisaDocAgent("")
+	require.NoError(t, agent.store.SaveRule(context.Background(), &VisaRule{
+		Nationality: "TH", Destination: "VN", Purpose: "tourism", EffectiveFrom: date("2024-01-01"),
+		Source: VisaSourceLLM, Requirement: VisaRequirement{VisaType: "Visa Exemption", Disclaimer: "Check with the embassy."},
+	}))
+
+	requirement, err := agent.CheckVisa(context.Background(), "Thai", "Vietnam", 10, "tourism")
+	require.NoError(t, err)
+	assert.False(t, requirement.Verified)
+	assert.Equal(t, VisaSourceLLM, requirement.Source)
+	assert.Contains(t, requirement.Disclaimer, "Unverified")
+
+	requirement, err = agent.CheckVisa(context.Background(), "TH", "CA", 10, "tourism")
+	require.NoError(t, err)
+	assert.False(t, requirement.Verified, "Seeded rules have no verification date")
+	assert.Equal(t, VisaSourceSeed, requirement.Source)
+	assert.NotEmpty(t, requirement.SourceURL)
+	assert.Contains(t, requirement.Disclaimer, unverifiedNotice)
+}
+
+func TestLoadVisaRulesFile_Example(t *testing.T) {
+	rules, err := LoadVisaRulesFile("../data/visa_rules.example.yaml")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	japan := rules[0]
+	assert.Equal(t, "TH", japan.Nationality)
+	assert.Equal(t, "JP", japan.Destination)
+	assert.Equal(t, "tourism", japan.Purpose)
+	assert.Equal(t, date("2024-01-01"), japan.EffectiveFrom)
+	assert.Nil(t, japan.EffectiveTo)
+	require.NotNil(t, japan.LastVerified)
+	assert.True(t, japan.Verified)
+	assert.Equal(t, VisaSourceImport, japan.Source)
+	assert.Equal(t, 15, japan.Requirement.MaxStayDays)
+	assert.Len(t, japan.Requirement.Checklist, 2)
+
+	korea := rules[1]
+	assert.False(t, korea.Verified)
+	require.NotNil(t, korea.EffectiveTo)
+	assert.Equal(t, date("2024-12-31"), *korea.EffectiveTo)
+	assert.NotEmpty(t, korea.Requirement.Disclaimer, "A default disclaimer is added")
+
+	store := NewMemoryVisaStore()
+	imported, err := ImportVisaRules(context.Background(), store, rules)
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported)
+}
+
+func TestParseVisaRulesJSON(t *testing.T) {
+	rules, err := ParseVisaRulesJSON([]byte(`{"rules": [{
+		"nationality": "Thai", "destination": "Singapore", "effective_from": "2024-01-01T00:00:00Z",
+		"requirement": {"visa_required": false, "max_stay_days": 30}
+	}]}`))
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	assert.Equal(t, "TH", rules[0].Nationality)
+	assert.Equal(t, "SG", rules[0].Destination)
+	assert.Equal(t, 30, rules[0].Requirement.MaxStayDays)
+	assert.False(t, rules[0].Verified, "Imports are unverified by default")
+
+	verified := []struct {
+		name   string
+		fields string
+		want   bool
+	}{
+		{"Verified with date", `"verified": true, "last_verified": "2024-06-01"`, true},
+		{"Verified without date", `"verified": true`, false},
+		{"Date only", `"last_verified": "2024-06-01"`, false},
+		{"Explicitly unverified", `"verified": false, "last_verified": "2024-06-01"`, false},
+	}
+	for _, tt := range verified {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := ParseVisaRulesJSON([]byte(`{"rules": [{"nationality": "TH", "destination": "JP",
+				"effective_from": "2024-01-01", "requirement": {}, ` + tt.fields + `}]}`))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, rules[0].Verified)
+		})
+	}
+
+	invalid := []struct {
+		name string
+		data string
+	}{
+		{"Missing destination", `{"rules": [{"nationality": "TH", "effective_from": "2024-01-01", "requirement": {}}]}`},
+		{"Missing requirement", `{"rules": [{"nationality": "TH", "destination": "JP", "effective_from": "2024-01-01"}]}`},
+		{"Missing effective_from", `{"rules": [{"nationality": "TH", "destination": "JP", "requirement": {}}]}`},
+		{"Bad date", `{"rules": [{"nationality": "TH", "destination": "JP", "effective_from": "01/01/2024", "requirement": {}}]}`},
+		{"Range ends before it starts", `{"rules": [{"nationality": "TH", "destination": "JP", "effective_from": "2024-01-01", "effective_to": "2023-01-01", "requirement": {}}]}`},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseVisaRulesJSON([]byte(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
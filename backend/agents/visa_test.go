@@ -0,0 +1,82 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeCountry(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"TH", "TH"},
+		{"jp", "JP"},
+		{"Thai", "TH"},
+		{"Canada", "CA"},
+		{"แคนาดา", "CA"},
+		{"Tokyo", "JP"},
+		{"United Kingdom", "GB"},
+		{" United States ", "US"},
+		{"Atlantis", "Atlantis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeCountry(tt.input))
+		})
+	}
+}
+
+func TestNormalizeVisaPurpose(t *testing.T) {
+	assert.Equal(t, "tourism", NormalizeVisaPurpose(""))
+	assert.Equal(t, "tourism", NormalizeVisaPurpose("holiday"))
+	assert.Equal(t, "business", NormalizeVisaPurpose("Business meeting"))
+	assert.Equal(t, "study", NormalizeVisaPurpose("student exchange"))
+	assert.Equal(t, "transit", NormalizeVisaPurpose("transit"))
+}
+
+func TestVisaDocAgent_CheckVisaWithNames(t *testing.T) {
+	agent := NewVisaDocAgent("")
+
+	requirement, err := agent.CheckVisa(context.Background(), "Thai", "Canada", 14, "tourist")
+	require.NoError(t, err)
+	assert.True(t, requirement.VisaRequired)
+	assert.Equal(t, "Temporary Resident Visa (TRV)", requirement.VisaType)
+}
+
+func TestVisaDocAgent_Handle(t *testing.T) {
+	agent := NewVisaDocAgent("")
+	assert.Equal(t, []string{"visa_check"}, agent.SupportedIntents())
+
+	t.Run("Renders checklist, forms, fees and disclaimer", func(t *testing.T) {
+		result, err := agent.Handle(context.Background(), &IntentResult{
+			Intent:   "visa_check",
+			Entities: map[string]interface{}{"destination": "Canada", "duration": 10.0},
+		})
+		require.NoError(t, err)
+
+		visa, ok := result.(*VisaCheckResult)
+		require.True(t, ok)
+		assert.Equal(t, "TH", visa.Nationality, "Nationality defaults to Thai")
+		assert.Equal(t, "CA", visa.Destination)
+		assert.Equal(t, 10, visa.StayDays, "Stay falls back to the trip duration")
+
+		markdown := result.Markdown()
+		assert.Contains(t, markdown, "# Visa Requirements: TH → CA")
+		assert.Contains(t, markdown, "**Visa required:** Yes (Temporary Resident Visa (TRV))")
+		assert.Contains(t, markdown, "- [ ] Valid passport - Valid for at least 6 months beyond stay")
+		assert.Contains(t, markdown, "## Forms\n- [IMM 5257 - Application for Visitor Visa]")
+		assert.Contains(t, markdown, "## Fees\n100 CAD")
+		assert.Contains(t, markdown, "This is not legal advice")
+	})
+
+	t.Run("Asks for a destination", func(t *testing.T) {
+		result, err := agent.Handle(context.Background(), &IntentResult{Intent: "visa_check", Entities: map[string]interface{}{}})
+		require.NoError(t, err)
+		assert.Contains(t, result.Markdown(), "Which country")
+	})
+}
@@ -7,12 +7,14 @@ import (
 	"io"
 	"log"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"strings"
 	"time"
 
-	"github.com/redis/go-redis/v9"
 	"github.com/sashabaranov/go-openai"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
 )
 
 // DayForecast represents a single day's forecast
@@ -33,10 +35,28 @@ type WeatherForecast struct {
 	Suggestion  string        `json:"suggestion"`
 }
 
+// weatherSummaryCacheTTL is how long GetWeatherSummary results are cached
+const weatherSummaryCacheTTL = 24 * time.Hour
+
+// weatherCachePolicy keeps API forecasts fresh for 30 minutes, serves them for up to
+// 3 hours more while refreshing, and backs off the API for a minute after a failure
+var weatherCachePolicy = cache.Policy{Fresh: 30 * time.Minute, Stale: 3 * time.Hour, Negative: time.Minute}
+
+// openWeatherMapURL is the OpenWeatherMap API base URL (replaced in tests)
+var openWeatherMapURL = "https://api.openweathermap.org/data/2.5"
+
 // WeatherAgent handles weather forecasting and suggestions
 type WeatherAgent struct {
 	client *openai.Client
 	apiKey string
+	cache  cache.Cache
+	loader *cache.Loader
+}
+
+// apiForecast is the part of a forecast that comes from OpenWeatherMap
+type apiForecast struct {
+	Days     []DayForecast `json:"days"`
+	RainProb float64       `json:"rain_probability"`
 }
 
 // NewWeatherAgent creates a new weather agent
@@ -51,6 +71,17 @@ func NewWeatherAgent(openaiKey, weatherKey string) *WeatherAgent {
 	}
 }
 
+// SetCache makes the agent share OpenWeatherMap forecasts between requests for the same city
+func (a *WeatherAgent) SetCache(c cache.Cache) {
+	a.cache = c
+	a.loader = cache.NewLoader("weather", c, weatherCachePolicy)
+}
+
+// CacheStats reports how forecast lookups were served
+func (a *WeatherAgent) CacheStats() cache.Stats {
+	return a.loader.Stats()
+}
+
 // GetForecast gets 3-day weather forecast and suggestions
 func (a *WeatherAgent) GetForecast(ctx context.Context, city string) (*WeatherForecast, error) {
 	forecast := &WeatherForecast{
@@ -60,14 +91,18 @@ func (a *WeatherAgent) GetForecast(ctx context.Context, city string) (*WeatherFo
 
 	// Try to get forecast from OpenWeatherMap API
 	if a.apiKey != "" {
-		apiForecasts, avgRainProb := a.fetchForecastFromAPI(city)
-		if len(apiForecasts) > 0 {
-			forecast.Forecast = apiForecasts
-			forecast.RainProb = avgRainProb
-			if len(apiForecasts) > 0 {
-				forecast.Temperature = apiForecasts[0].Temperature
-				forecast.Condition = apiForecasts[0].Condition
-			}
+		var fetched apiForecast
+		key := "weather:forecast:" + strings.ToLower(strings.TrimSpace(city))
+		err := a.loader.Load(ctx, key, &fetched, func(ctx context.Context) (interface{}, error) {
+			return a.fetchForecastFromAPI(ctx, city)
+		})
+		if err != nil {
+			log.Printf("WeatherAgent: Forecast API unavailable for %s: %v", city, err)
+		} else if len(fetched.Days) > 0 {
+			forecast.Forecast = fetched.Days
+			forecast.RainProb = fetched.RainProb
+			forecast.Temperature = fetched.Days[0].Temperature
+			forecast.Condition = fetched.Days[0].Condition
 		}
 	}
 
@@ -95,29 +130,31 @@ func (a *WeatherAgent) GetForecast(ctx context.Context, city string) (*WeatherFo
 }
 
 // fetchForecastFromAPI gets forecast from OpenWeatherMap
-func (a *WeatherAgent) fetchForecastFromAPI(city string) ([]DayForecast, float64) {
+func (a *WeatherAgent) fetchForecastFromAPI(ctx context.Context, city string) (apiForecast, error) {
 	url := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/forecast?q=%s&appid=%s&units=metric",
-		city, a.apiKey,
+		"%s/forecast?%s&appid=%s&units=metric",
+		openWeatherMapURL, weatherLocationQuery(city), a.apiKey,
 	)
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return apiForecast{}, fmt.Errorf("failed to build request: %w", err)
+	}
+
 	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Get(url)
+	resp, err := client.Do(req)
 	if err != nil {
-		log.Printf("WeatherAgent: API request failed: %v", err)
-		return nil, 0
+		return apiForecast{}, fmt.Errorf("API request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		log.Printf("WeatherAgent: API returned status %d", resp.StatusCode)
-		return nil, 0
+		return apiForecast{}, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		log.Printf("WeatherAgent: Failed to read response: %v", err)
-		return nil, 0
+		return apiForecast{}, fmt.Errorf("failed to read response: %w", err)
 	}
 
 	var result struct {
@@ -134,8 +171,7 @@ func (a *WeatherAgent) fetchForecastFromAPI(city string) ([]DayForecast, float64
 	}
 
 	if err := json.Unmarshal(body, &result); err != nil {
-		log.Printf("WeatherAgent: Failed to parse response: %v", err)
-		return nil, 0
+		return apiForecast{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	// Group by day and get 3-day forecast
@@ -173,7 +209,7 @@ func (a *WeatherAgent) fetchForecastFromAPI(city string) ([]DayForecast, float64
 		avgRainProb = sum / float64(len(rainProbs))
 	}
 
-	return forecasts, avgRainProb
+	return apiForecast{Days: forecasts, RainProb: avgRainProb}, nil
 }
 
 // estimateForecast provides estimated 3-day forecast
@@ -266,7 +302,19 @@ Recommend 2-3 indoor activities suitable for rainy weather. Keep it brief and fr
 // Returns:
 //   - avgTemp: Average temperature in Celsius
 //   - condition: Main weather condition (e.g., "Sunny", "Rainy", "Cloudy")
+//
+// Results are not cached; use WeatherAgent.GetWeatherSummary to cache them.
 func GetWeatherSummary(city, month string) (avgTemp int, condition string) {
+	return getWeatherSummary(context.Background(), nil, city, month)
+}
+
+// GetWeatherSummary works like the package-level GetWeatherSummary and caches results for 24 hours
+func (a *WeatherAgent) GetWeatherSummary(ctx context.Context, city, month string) (avgTemp int, condition string) {
+	return getWeatherSummary(ctx, a.cache, city, month)
+}
+
+// getWeatherSummary looks up a month's weather in the cache (when one is given), the weather API, then estimates
+func getWeatherSummary(ctx context.Context, c cache.Cache, city, month string) (avgTemp int, condition string) {
 	// Default values
 	defaultTemp := 15
 	defaultCondition := "Sunny"
@@ -279,8 +327,8 @@ func GetWeatherSummary(city, month string) (avgTemp int, condition string) {
 	// Normalize month input
 	normalizedMonth := normalizeMonth(month)
 
-	// Try to get from Redis cache first
-	cachedTemp, cachedCondition := getCachedWeather(city, normalizedMonth)
+	// Try the cache first
+	cachedTemp, cachedCondition := getCachedWeather(ctx, c, city, normalizedMonth)
 	if cachedTemp != 0 {
 		log.Printf("Cache hit for weather in %s (%s): %d°C, %s", city, normalizedMonth, cachedTemp, cachedCondition)
 		return cachedTemp, cachedCondition
@@ -292,7 +340,7 @@ func GetWeatherSummary(city, month string) (avgTemp int, condition string) {
 		avgTemp, condition = fetchWeatherFromAPI(city, apiKey)
 		if avgTemp != 0 {
 			// Cache the result
-			cacheWeather(city, normalizedMonth, avgTemp, condition)
+			cacheWeather(ctx, c, city, normalizedMonth, avgTemp, condition)
 			log.Printf("API result for weather in %s: %d°C, %s", city, avgTemp, condition)
 			return avgTemp, condition
 		}
@@ -303,7 +351,7 @@ func GetWeatherSummary(city, month string) (avgTemp int, condition string) {
 	condition = estimateCondition(city, normalizedMonth)
 
 	// Cache the estimated result
-	cacheWeather(city, normalizedMonth, avgTemp, condition)
+	cacheWeather(ctx, c, city, normalizedMonth, avgTemp, condition)
 
 	log.Printf("Estimated weather for %s in %s: %d°C, %s", city, normalizedMonth, avgTemp, condition)
 
@@ -336,38 +384,9 @@ func normalizeMonth(month string) string {
 	return "january"
 }
 
-// getCachedWeather retrieves weather from Redis cache
-func getCachedWeather(city, month string) (avgTemp int, condition string) {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
-		DB:   0,
-	})
-	defer rdb.Close()
-
-	ctx := context.Background()
-
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Printf("Redis connection failed: %v", err)
-		return 0, ""
-	}
-
-	cacheKey := fmt.Sprintf("weather:%s:%s", strings.ToLower(city), strings.ToLower(month))
-
-	val, err := rdb.Get(ctx, cacheKey).Result()
-	if err == redis.Nil {
-		return 0, ""
-	} else if err != nil {
-		log.Printf("Redis get error: %v", err)
+// getCachedWeather retrieves weather from the cache
+func getCachedWeather(ctx context.Context, c cache.Cache, city, month string) (avgTemp int, condition string) {
+	if c == nil {
 		return 0, ""
 	}
 
@@ -375,42 +394,23 @@ func getCachedWeather(city, month string) (avgTemp int, condition string) {
 		AvgTemp   int    `json:"avg_temp"`
 		Condition string `json:"condition"`
 	}
-
-	if err := json.Unmarshal([]byte(val), &cached); err != nil {
-		log.Printf("Failed to parse cached weather data: %v", err)
+	err := c.GetJSON(ctx, weatherCacheKey(city, month), &cached)
+	if err == cache.ErrMiss {
+		return 0, ""
+	} else if err != nil {
+		log.Printf("Failed to read cached weather data: %v", err)
 		return 0, ""
 	}
 
 	return cached.AvgTemp, cached.Condition
 }
 
-// cacheWeather stores weather in Redis cache
-func cacheWeather(city, month string, avgTemp int, condition string) {
-	redisHost := os.Getenv("REDIS_HOST")
-	redisPort := os.Getenv("REDIS_PORT")
-
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
-		DB:   0,
-	})
-	defer rdb.Close()
-
-	ctx := context.Background()
-
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Printf("Redis connection failed, skipping cache: %v", err)
+// cacheWeather stores weather in the cache
+func cacheWeather(ctx context.Context, c cache.Cache, city, month string, avgTemp int, condition string) {
+	if c == nil {
 		return
 	}
 
-	cacheKey := fmt.Sprintf("weather:%s:%s", strings.ToLower(city), strings.ToLower(month))
-
 	data := struct {
 		AvgTemp   int    `json:"avg_temp"`
 		Condition string `json:"condition"`
@@ -419,15 +419,8 @@ func cacheWeather(city, month string, avgTemp int, condition string) {
 		Condition: condition,
 	}
 
-	jsonData, err := json.Marshal(data)
-	if err != nil {
-		log.Printf("Failed to marshal weather data: %v", err)
-		return
-	}
-
-	expiration := 24 * time.Hour
-
-	if err := rdb.Set(ctx, cacheKey, jsonData, expiration).Err(); err != nil {
+	cacheKey := weatherCacheKey(city, month)
+	if err := c.SetJSON(ctx, cacheKey, data, weatherSummaryCacheTTL); err != nil {
 		log.Printf("Failed to cache weather data: %v", err)
 		return
 	}
@@ -435,11 +428,25 @@ func cacheWeather(city, month string, avgTemp int, condition string) {
 	log.Printf("Cached weather data for %s in %s (key: %s)", city, month, cacheKey)
 }
 
+// weatherCacheKey has the format weather:<city>:<month>
+func weatherCacheKey(city, month string) string {
+	return fmt.Sprintf("weather:%s:%s", strings.ToLower(city), strings.ToLower(month))
+}
+
+// weatherLocationQuery locates a place for OpenWeatherMap: by coordinates when the airport dataset
+// knows it, so Thai names and countries work, otherwise by name
+func weatherLocationQuery(city string) string {
+	if lat, lng, ok := airports.Coordinates(city); ok {
+		return fmt.Sprintf("lat=%.4f&lon=%.4f", lat, lng)
+	}
+	return "q=" + neturl.QueryEscape(city)
+}
+
 // fetchWeatherFromAPI calls OpenWeatherMap API
 func fetchWeatherFromAPI(city, apiKey string) (avgTemp int, condition string) {
 	url := fmt.Sprintf(
-		"https://api.openweathermap.org/data/2.5/weather?q=%s&appid=%s&units=metric",
-		city, apiKey,
+		"%s/weather?%s&appid=%s&units=metric",
+		openWeatherMapURL, weatherLocationQuery(city), apiKey,
 	)
 
 	client := &http.Client{Timeout: 10 * time.Second}
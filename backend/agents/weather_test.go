@@ -0,0 +1,79 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWeatherLocationQuery(t *testing.T) {
+	assert.Equal(t, "lat=35.6762&lon=139.6503", weatherLocationQuery("โตเกียว"))
+	assert.Equal(t, "lat=49.2827&lon=-123.1207", weatherLocationQuery("Canada"), "Countries use their main city")
+	assert.Equal(t, "q=Hoi+An", weatherLocationQuery("Hoi An"))
+}
+
+// newFakeOpenWeatherMap serves /forecast with a fixed forecast, or 503 while failing is set
+func newFakeOpenWeatherMap(t *testing.T, requests, failing *int32) {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		if atomic.LoadInt32(failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"list": [
+			{"dt": 1746086400, "main": {"temp": 12.5}, "weather": [{"main": "Snow"}], "pop": 0.2},
+			{"dt": 1746172800, "main": {"temp": 14}, "weather": [{"main": "Clouds"}], "pop": 0.4}
+		]}`)
+	}))
+	t.Cleanup(server.Close)
+
+	original := openWeatherMapURL
+	openWeatherMapURL = server.URL
+	t.Cleanup(func() { openWeatherMapURL = original })
+}
+
+func TestWeatherAgent_GetForecastUsesCache(t *testing.T) {
+	var requests, failing int32
+	newFakeOpenWeatherMap(t, &requests, &failing)
+
+	agent := NewWeatherAgent("", "test-key")
+	agent.SetCache(cache.NewMemoryCache())
+
+	for i := 0; i < 3; i++ {
+		forecast, err := agent.GetForecast(context.Background(), "Tokyo")
+		require.NoError(t, err)
+		assert.Equal(t, "Snow", forecast.Condition)
+		assert.Equal(t, 12.5, forecast.Temperature)
+		assert.InDelta(t, 30, forecast.RainProb, 0.01)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "Repeated forecasts for a city share one API call")
+
+	stats := agent.CacheStats()
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(2), stats.Hits)
+}
+
+func TestWeatherAgent_BacksOffAfterAPIFailure(t *testing.T) {
+	var requests int32
+	failing := int32(1)
+	newFakeOpenWeatherMap(t, &requests, &failing)
+
+	agent := NewWeatherAgent("", "test-key")
+	agent.SetCache(cache.NewMemoryCache())
+
+	for i := 0; i < 2; i++ {
+		forecast, err := agent.GetForecast(context.Background(), "Tokyo")
+		require.NoError(t, err)
+		assert.Len(t, forecast.Forecast, 3, "Falls back to the estimated forecast")
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "The failure is cached")
+	assert.Equal(t, int64(1), agent.CacheStats().NegativeHits)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+)
+
+const commandUsage = `Commands:
+  migrate up                 Apply all pending schema migrations
+  migrate down [n]           Roll back the last n migrations (default 1)
+  migrate status             List migrations and whether they are applied
+  import-visa-rules <file>   Load visa rules from a YAML or JSON file
+`
+
+// runCommand runs a one-off command instead of starting the server
+func runCommand(db *database.PostgresDB, args []string) {
+	switch args[0] {
+	case "migrate":
+		migrate(db, args[1:])
+	case "import-visa-rules":
+		if len(args) != 2 {
+			log.Fatal("Usage: server import-visa-rules <rules.yaml|rules.json>")
+		}
+		if err := db.Migrate(context.Background()); err != nil {
+			log.Fatalf("Failed to migrate database: %v", err)
+		}
+		importVisaRules(db, args[1])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n%s", args[0], commandUsage)
+		os.Exit(2)
+	}
+}
+
+// migrate applies, rolls back or lists schema migrations
+func migrate(db *database.PostgresDB, args []string) {
+	usage := "Usage: server migrate up|down [n]|status"
+	if len(args) == 0 {
+		log.Fatal(usage)
+	}
+
+	migrations, err := database.Migrations()
+	if err != nil {
+		log.Fatalf("Failed to load migrations: %v", err)
+	}
+	migrator := database.NewMigrator(db.DB, migrations)
+	ctx := context.Background()
+
+	switch {
+	case args[0] == "up" && len(args) == 1:
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("Migration failed: %v", err)
+		}
+		log.Printf("Applied %d migrations", len(applied))
+	case args[0] == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps <= 0 {
+				log.Fatalf("%s: n must be a positive number", usage)
+			}
+		}
+		rolledBack, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("Rollback failed: %v", err)
+		}
+		log.Printf("Rolled back %d migrations", len(rolledBack))
+	case args[0] == "status" && len(args) == 1:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("Failed to read migration status: %v", err)
+		}
+		for _, status := range statuses {
+			state := "pending"
+			if status.Applied {
+				state = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-24s %s\n", status.Version, status.Name, state)
+		}
+	default:
+		log.Fatal(usage)
+	}
+}
+
+// importVisaRules bulk-loads visa rules from a file into Postgres
+func importVisaRules(db *database.PostgresDB, path string) {
+	rules, err := agents.LoadVisaRulesFile(path)
+	if err != nil {
+		log.Fatalf("Failed to load visa rules: %v", err)
+	}
+
+	imported, err := agents.ImportVisaRules(context.Background(), database.NewPostgresVisaStore(db), rules)
+	if err != nil {
+		log.Fatalf("Imported %d of %d visa rules before failing: %v", imported, len(rules), err)
+	}
+	log.Printf("Imported %d visa rules from %s", imported, path)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/handlers"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/orchestrator"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/services"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	// Initialize database connections
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	}
+	defer db.Close()
+
+	// One-off commands
+	if len(os.Args) > 1 {
+		runCommand(db, os.Args[1:])
+		return
+	}
+
+	// Bring the schema up to date; instances starting together wait for each other
+	if err := db.Migrate(context.Background()); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	redis, err := database.NewRedisCache(cfg)
+	if err != nil {
+		log.Fatalf("Failed to connect to Redis: %v", err)
+	}
+	defer redis.Close()
+
+	// Initialize services
+	openaiService := services.NewOpenAIService(cfg)
+	weatherService := services.NewWeatherService(cfg)
+	flightService := services.NewFlightService(cfg)
+	planService := services.NewPlanService(cfg)
+	socialService := services.NewSocialService(cfg)
+
+	// Initialize orchestrator
+	orch := orchestrator.New(
+		cfg.OpenAI.APIKey,
+		cfg.Weather.APIKey,
+		cfg.Flight.APIKey,
+		cfg.Hotel.APIKey,
+	)
+
+	// Serve visa rules from Postgres, seeding the default rules on first start
+	visaStore := database.NewPostgresVisaStore(db)
+	if err := visaStore.SeedIfEmpty(context.Background(), agents.DefaultVisaRules()); err != nil {
+		log.Printf("Warning: Failed to seed visa rules: %v", err)
+	}
+	orch.VisaAgent().SetStore(visaStore)
+
+	// Convert budgets and prices with the configured exchange rates
+	var rates currency.RateProvider = currency.DefaultRates()
+	if cfg.Currency.RatesFile != "" {
+		loaded, err := currency.LoadRates(cfg.Currency.RatesFile)
+		if err != nil {
+			log.Printf("Warning: Failed to load exchange rates, using bundled rates: %v", err)
+		} else {
+			rates = loaded
+		}
+	}
+	orch.SetRateProvider(rates)
+	orch.SetDefaultCurrency(cfg.Currency.Default)
+
+	// Search real hotel availability when a hotel API is configured
+	if hotelProvider := agents.NewHTTPHotelProvider(cfg.Hotel); hotelProvider != nil {
+		orch.SetHotelProvider(hotelProvider)
+	}
+
+	// Look up scheduled flights when a flight API is configured
+	if flightService != nil {
+		orch.FlightAgent().SetSource(flightService)
+	}
+
+	// Re-check watched flights in the background and notify subscribers of changes
+	watchInterval, err := time.ParseDuration(cfg.Flight.WatchInterval)
+	if err != nil {
+		log.Printf("Warning: Invalid FLIGHT_WATCH_INTERVAL %q, using %s", cfg.Flight.WatchInterval, agents.DefaultFlightWatchInterval)
+		watchInterval = agents.DefaultFlightWatchInterval
+	}
+	flightWatchStore := database.NewPostgresFlightWatchStore(db)
+	flightWatcher := agents.NewFlightWatcher(orch.FlightAgent(), flightWatchStore, agents.NewDefaultNotifier(), watchInterval)
+	flightWatcher.Start(context.Background())
+
+	// Share the Redis connection with the agents' caches
+	sharedCache := cache.NewRedisCache(redis.Client)
+	orch.SetCache(sharedCache)
+	if socialService != nil {
+		socialService.SetCache(sharedCache)
+	}
+
+	// Keep conversation state between messages
+	orch.SetSessionStore(orchestrator.NewRedisSessionStore(redis, orchestrator.DefaultSessionTTL))
+
+	// Set social service if available
+	if socialService != nil {
+		adapter := orchestrator.NewSocialServiceAdapter(socialService)
+		orch.SetSocialService(adapter)
+	}
+
+	// Sign access and refresh tokens for user accounts
+	accessTTL, err := time.ParseDuration(cfg.JWT.ExpiresIn)
+	if err != nil {
+		log.Printf("Warning: Invalid JWT_EXPIRES_IN %q, using %s", cfg.JWT.ExpiresIn, auth.DefaultAccessTTL)
+		accessTTL = auth.DefaultAccessTTL
+	}
+	refreshTTL, err := time.ParseDuration(cfg.JWT.RefreshExpiresIn)
+	if err != nil {
+		log.Printf("Warning: Invalid JWT_REFRESH_EXPIRES_IN %q, using %s", cfg.JWT.RefreshExpiresIn, auth.DefaultRefreshTTL)
+		refreshTTL = auth.DefaultRefreshTTL
+	}
+	tokens := auth.NewTokenManager(cfg.JWT.Secret, accessTTL, refreshTTL)
+	requireUser := auth.Middleware(tokens)
+
+	// Initialize handlers
+	authHandler := handlers.NewAuthHandler(database.NewPostgresUserStore(db), tokens)
+	repositories := storage.NewPostgresRepositories(db)
+	travelHandler := handlers.NewTravelHandler(
+		repositories.Searches,
+		repositories.Recommendations,
+		sharedCache,
+		openaiService,
+		weatherService,
+		flightService,
+	)
+	travelHandler.SetHealthChecks(db, redis)
+	travelHandler.SetRecommenders(orch.HotelAgent(), orch.LocalAgent(), socialService)
+	tripHandler := handlers.NewTripHandler(repositories.Trips, orch)
+	planHandler := handlers.NewPlanHandler(planService, orch)
+	planHandler.SetTripRepository(repositories.Trips)
+	socialHandler := handlers.NewSocialHandler(redis, socialService)
+	visaHandler := handlers.NewVisaHandler(orch.VisaAgent())
+	flightHandler := handlers.NewFlightHandler(orch.FlightAgent())
+	flightWatchHandler := handlers.NewFlightWatchHandler(flightWatchStore)
+	expenseHandler := handlers.NewExpenseHandler(repositories.Expenses, rates)
+	expenseHandler.SetTripRepository(repositories.Trips)
+	cacheHandler := handlers.NewCacheHandler(orch.CacheStats, func() []cache.Stats {
+		if socialService == nil {
+			return nil
+		}
+		return []cache.Stats{socialService.CacheStats()}
+	})
+
+	// Create Fiber app
+	app := fiber.New(fiber.Config{
+		AppName:      "Travel AI Agent API",
+		ServerHeader: "Travel-AI-Agent",
+		ErrorHandler: customErrorHandler,
+	})
+
+	// Middleware
+	app.Use(recover.New())
+	app.Use(logger.New(logger.Config{
+		Format: "[${time}] ${status} - ${method} ${path} - ${latency}\n",
+	}))
+	app.Use(cors.New(cors.Config{
+		AllowOrigins:     "*",
+		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
+		AllowHeaders:     "Origin,Content-Type,Accept,Authorization",
+		AllowCredentials: false,
+	}))
+
+	// API Routes
+	api := app.Group("/api")
+
+	// Plan endpoint; signed-in users can talk about a saved trip with trip_id
+	api.Use("/plan", auth.OptionalMiddleware(tokens))
+	api.Post("/plan", planHandler.CreateTravelPlan)
+	api.Get("/plan/stream", planHandler.StreamTravelPlan)
+	api.Post("/plan/stream", planHandler.StreamTravelPlan)
+
+	// Social places endpoint
+	api.Post("/social", socialHandler.GetSocialPlaces)
+
+	// API v1 Routes
+	apiv1 := app.Group("/api/v1")
+
+	// Account endpoints
+	apiv1.Post("/auth/register", authHandler.Register)
+	apiv1.Post("/auth/login", authHandler.Login)
+	apiv1.Post("/auth/refresh", authHandler.Refresh)
+	apiv1.Get("/auth/me", requireUser, authHandler.Me)
+
+	// Travel endpoints
+	apiv1.Post("/travel/search", auth.OptionalMiddleware(tokens), travelHandler.SearchTravel)
+	apiv1.Get("/travel/search/:id", auth.OptionalMiddleware(tokens), travelHandler.GetSearch)
+	apiv1.Get("/travel/history", requireUser, travelHandler.GetSearchHistory)
+
+	// Visa endpoint
+	apiv1.Post("/visa", visaHandler.CheckVisa)
+	apiv1.Post("/visa/itinerary", visaHandler.CheckItinerary)
+
+	// Flight search endpoint
+	apiv1.Get("/flights/search", flightHandler.SearchFlights)
+	apiv1.Post("/flights/search", flightHandler.SearchFlights)
+
+	// Flight watch and trip endpoints belong to the signed-in user
+	apiv1.Use("/flights/watches", requireUser)
+	apiv1.Use("/trips", requireUser)
+
+	// Flight watch endpoints
+	apiv1.Post("/flights/watches", flightWatchHandler.CreateWatch)
+	apiv1.Get("/flights/watches", flightWatchHandler.ListWatches)
+	apiv1.Get("/flights/watches/:id", flightWatchHandler.GetWatch)
+	apiv1.Delete("/flights/watches/:id", flightWatchHandler.DeleteWatch)
+
+	// Saved trip endpoints
+	apiv1.Get("/trips", tripHandler.ListTrips)
+	apiv1.Post("/trips", tripHandler.CreateTrip)
+	apiv1.Get("/trips/:id", tripHandler.GetTrip)
+	apiv1.Put("/trips/:id", tripHandler.UpdateTrip)
+	apiv1.Delete("/trips/:id", tripHandler.DeleteTrip)
+
+	// Trip budget and expense ledger endpoints, for the trip's owner only
+	requireOwner := tripHandler.RequireOwner
+	apiv1.Get("/trips/:id/budget", requireOwner, expenseHandler.GetBudget)
+	apiv1.Put("/trips/:id/budget", requireOwner, expenseHandler.SetBudget)
+	apiv1.Get("/trips/:id/expenses", requireOwner, expenseHandler.ListExpenses)
+	apiv1.Post("/trips/:id/expenses", requireOwner, expenseHandler.CreateExpense)
+	apiv1.Get("/trips/:id/expenses/summary", requireOwner, expenseHandler.GetSummary)
+	apiv1.Put("/trips/:id/expenses/:expenseId", requireOwner, expenseHandler.UpdateExpense)
+	apiv1.Delete("/trips/:id/expenses/:expenseId", requireOwner, expenseHandler.DeleteExpense)
+
+	// Group trip cost splitting endpoints
+	apiv1.Get("/trips/:id/members", requireOwner, expenseHandler.ListMembers)
+	apiv1.Post("/trips/:id/members", requireOwner, expenseHandler.AddMember)
+	apiv1.Delete("/trips/:id/members/:memberId", requireOwner, expenseHandler.DeleteMember)
+	apiv1.Get("/trips/:id/settlement", requireOwner, expenseHandler.GetSettlement)
+
+	// Cache counters for upstream API lookups
+	apiv1.Get("/cache/stats", requireUser, cacheHandler.GetStats)
+
+	// Health check endpoint
+	app.Get("/health", travelHandler.HealthCheck)
+
+	// Root endpoint
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"service": "Travel AI Agent API",
+			"version": "1.0.0",
+			"status":  "running",
+		})
+	})
+
+	// Graceful shutdown
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-c
+		log.Println("Gracefully shutting down...")
+		flightWatcher.Stop()
+		app.Shutdown()
+	}()
+
+	// Start server
+	address := fmt.Sprintf("%s:%s", cfg.Backend.Host, cfg.Backend.Port)
+	log.Printf("Starting server on %s", address)
+
+	if err := app.Listen(address); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+}
+
+// customErrorHandler handles application errors
+func customErrorHandler(c *fiber.Ctx, err error) error {
+	code := fiber.StatusInternalServerError
+
+	if e, ok := err.(*fiber.Error); ok {
+		code = e.Code
+	}
+
+	return c.Status(code).JSON(fiber.Map{
+		"error":   true,
+		"message": err.Error(),
+		"code":    code,
+	})
+}
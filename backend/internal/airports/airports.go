@@ -0,0 +1,280 @@
+// Package airports resolves city, country and airport names in English or Thai to IATA codes and
+// coordinates using an embedded dataset.
+package airports
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log"
+	"math"
+	"strings"
+	"sync"
+)
+
+// Location kinds
+const (
+	KindAirport = "airport"
+	KindCity    = "city"
+	KindCountry = "country"
+)
+
+//go:embed airports.json
+var datasetJSON []byte
+
+// Airport is an airport in the dataset
+type Airport struct {
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	City      string  `json:"city"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// City is a city and the airports that serve it, main airport first
+type City struct {
+	Name      string   `json:"name"`
+	Country   string   `json:"country"`
+	Aliases   []string `json:"aliases"`
+	Latitude  float64  `json:"latitude"`
+	Longitude float64  `json:"longitude"`
+	Airports  []string `json:"airports"`
+}
+
+// Country is a country and the airport most international flights use
+type Country struct {
+	Code           string   `json:"code"`
+	Name           string   `json:"name"`
+	Aliases        []string `json:"aliases"`
+	PrimaryAirport string   `json:"primary_airport"`
+}
+
+// Location is a resolved place name. For countries the city and coordinates are those of the
+// primary airport's city.
+type Location struct {
+	Name        string   `json:"name"`
+	Kind        string   `json:"kind"`
+	City        string   `json:"city"`
+	Country     string   `json:"country"`
+	CountryCode string   `json:"country_code"`
+	Airport     string   `json:"airport"`
+	Airports    []string `json:"airports"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+}
+
+// dataset is the parsed airport data with lookup indexes
+type dataset struct {
+	Countries []Country `json:"countries"`
+	Cities    []City    `json:"cities"`
+	Airports  []Airport `json:"airports"`
+
+	airportsByCode  map[string]Airport
+	citiesByName    map[string]City
+	countriesByName map[string]Country
+	countriesByCode map[string]Country
+}
+
+var (
+	loadOnce sync.Once
+	data     *dataset
+)
+
+// load parses the embedded dataset once
+func load() *dataset {
+	loadOnce.Do(func() {
+		data = &dataset{}
+		if err := json.Unmarshal(datasetJSON, data); err != nil {
+			log.Printf("Airports: Failed to parse embedded dataset: %v", err)
+		}
+		data.index()
+	})
+	return data
+}
+
+// index builds the lookup maps keyed by normalized names and codes
+func (d *dataset) index() {
+	d.airportsByCode = make(map[string]Airport, len(d.Airports))
+	d.citiesByName = make(map[string]City)
+	d.countriesByName = make(map[string]Country)
+	d.countriesByCode = make(map[string]Country, len(d.Countries))
+
+	for _, airport := range d.Airports {
+		d.airportsByCode[airport.Code] = airport
+	}
+	for _, city := range d.Cities {
+		d.citiesByName[normalize(city.Name)] = city
+		for _, alias := range city.Aliases {
+			d.citiesByName[normalize(alias)] = city
+		}
+	}
+	for _, country := range d.Countries {
+		d.countriesByCode[country.Code] = country
+		d.countriesByName[normalize(country.Name)] = country
+		for _, alias := range country.Aliases {
+			d.countriesByName[normalize(alias)] = country
+		}
+	}
+}
+
+// Resolve finds an airport code, city or country by name, e.g. "BKK", "Tokyo", "แคนาดา" or
+// "Kyoto, Japan". Airport codes win over cities, and cities over countries.
+func Resolve(name string) (Location, bool) {
+	d := load()
+	key := normalize(name)
+	if key == "" {
+		return Location{}, false
+	}
+
+	if len(key) == 3 {
+		if airport, ok := d.airportsByCode[strings.ToUpper(key)]; ok {
+			return d.airportLocation(airport), true
+		}
+	}
+	if city, ok := d.citiesByName[key]; ok {
+		return d.cityLocation(city), true
+	}
+	if country, ok := d.countriesByName[key]; ok {
+		return d.countryLocation(country)
+	}
+	if len(key) == 2 {
+		if country, ok := d.countriesByCode[strings.ToUpper(key)]; ok {
+			return d.countryLocation(country)
+		}
+	}
+
+	// "Kyoto, Japan" or "Narita airport"
+	if comma := strings.Index(key, ","); comma > 0 {
+		return Resolve(key[:comma])
+	}
+	if trimmed := strings.TrimSuffix(key, " airport"); trimmed != key {
+		return Resolve(trimmed)
+	}
+
+	return Location{}, false
+}
+
+// AirportCode returns the main IATA code for a place name, or "" when it is unknown
+func AirportCode(name string) string {
+	location, ok := Resolve(name)
+	if !ok {
+		return ""
+	}
+	return location.Airport
+}
+
+// Coordinates returns the latitude and longitude of a place name
+func Coordinates(name string) (lat, lng float64, ok bool) {
+	location, ok := Resolve(name)
+	if !ok {
+		return 0, 0, false
+	}
+	return location.Latitude, location.Longitude, true
+}
+
+// Lookup returns the airport with an IATA code
+func Lookup(code string) (Airport, bool) {
+	airport, ok := load().airportsByCode[strings.ToUpper(strings.TrimSpace(code))]
+	return airport, ok
+}
+
+// PrimaryAirport returns the main international airport of a country, given its name or ISO code
+func PrimaryAirport(country string) (Airport, bool) {
+	d := load()
+	key := normalize(country)
+	found, ok := d.countriesByName[key]
+	if !ok {
+		found, ok = d.countriesByCode[strings.ToUpper(key)]
+	}
+	if !ok {
+		return Airport{}, false
+	}
+	airport, ok := d.airportsByCode[found.PrimaryAirport]
+	return airport, ok
+}
+
+// DistanceKm returns the great-circle distance between two places
+func DistanceKm(from, to string) (float64, bool) {
+	a, ok := Resolve(from)
+	if !ok {
+		return 0, false
+	}
+	b, ok := Resolve(to)
+	if !ok {
+		return 0, false
+	}
+	return haversineKm(a.Latitude, a.Longitude, b.Latitude, b.Longitude), true
+}
+
+// airportLocation describes an airport as a location
+func (d *dataset) airportLocation(airport Airport) Location {
+	return Location{
+		Name:        airport.Name,
+		Kind:        KindAirport,
+		City:        airport.City,
+		Country:     d.countriesByCode[airport.Country].Name,
+		CountryCode: airport.Country,
+		Airport:     airport.Code,
+		Airports:    []string{airport.Code},
+		Latitude:    airport.Latitude,
+		Longitude:   airport.Longitude,
+	}
+}
+
+// cityLocation describes a city as a location centred on the city
+func (d *dataset) cityLocation(city City) Location {
+	location := Location{
+		Name:        city.Name,
+		Kind:        KindCity,
+		City:        city.Name,
+		Country:     d.countriesByCode[city.Country].Name,
+		CountryCode: city.Country,
+		Airports:    city.Airports,
+		Latitude:    city.Latitude,
+		Longitude:   city.Longitude,
+	}
+	if len(city.Airports) > 0 {
+		location.Airport = city.Airports[0]
+	}
+	return location
+}
+
+// countryLocation describes a country by its primary airport's city
+func (d *dataset) countryLocation(country Country) (Location, bool) {
+	airport, ok := d.airportsByCode[country.PrimaryAirport]
+	if !ok {
+		return Location{}, false
+	}
+	location := Location{
+		Name:        country.Name,
+		Kind:        KindCountry,
+		City:        airport.City,
+		Country:     country.Name,
+		CountryCode: country.Code,
+		Airport:     airport.Code,
+		Airports:    []string{airport.Code},
+		Latitude:    airport.Latitude,
+		Longitude:   airport.Longitude,
+	}
+	if city, ok := d.citiesByName[normalize(airport.City)]; ok {
+		location.Latitude, location.Longitude = city.Latitude, city.Longitude
+	}
+	return location, true
+}
+
+// normalize lower-cases a name and collapses whitespace
+func normalize(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// haversineKm returns the great-circle distance between two coordinates
+func haversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
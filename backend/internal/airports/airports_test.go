@@ -0,0 +1,106 @@
+package airports
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDataset_IsConsistent(t *testing.T) {
+	d := load()
+	require.NotEmpty(t, d.Airports)
+
+	for _, city := range d.Cities {
+		assert.Contains(t, d.countriesByCode, city.Country, city.Name)
+		require.NotEmpty(t, city.Airports, city.Name)
+		for _, code := range city.Airports {
+			assert.Contains(t, d.airportsByCode, code, city.Name)
+		}
+	}
+	for _, country := range d.Countries {
+		assert.Contains(t, d.airportsByCode, country.PrimaryAirport, country.Name)
+	}
+	for _, airport := range d.Airports {
+		assert.Contains(t, d.countriesByCode, airport.Country, airport.Code)
+		assert.Contains(t, d.citiesByName, normalize(airport.City), airport.Code)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		kind    string
+		airport string
+		country string
+	}{
+		{name: "Airport code", input: "hnd", kind: KindAirport, airport: "HND", country: "JP"},
+		{name: "English city", input: "Tokyo", kind: KindCity, airport: "NRT", country: "JP"},
+		{name: "Thai city", input: "เชียงใหม่", kind: KindCity, airport: "CNX", country: "TH"},
+		{name: "City without an airport", input: "Kyoto", kind: KindCity, airport: "KIX", country: "JP"},
+		{name: "Country", input: "Canada", kind: KindCountry, airport: "YVR", country: "CA"},
+		{name: "Thai country", input: "แคนาดา", kind: KindCountry, airport: "YVR", country: "CA"},
+		{name: "Country alias", input: "UK", kind: KindCountry, airport: "LHR", country: "GB"},
+		{name: "ISO country code", input: "jp", kind: KindCountry, airport: "NRT", country: "JP"},
+		{name: "City state prefers the city", input: "Singapore", kind: KindCity, airport: "SIN", country: "SG"},
+		{name: "City and country", input: "Osaka, Japan", kind: KindCity, airport: "KIX", country: "JP"},
+		{name: "Airport suffix", input: "Bangkok airport", kind: KindCity, airport: "BKK", country: "TH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location, ok := Resolve(tt.input)
+			require.True(t, ok)
+			assert.Equal(t, tt.kind, location.Kind)
+			assert.Equal(t, tt.airport, location.Airport)
+			assert.Equal(t, tt.country, location.CountryCode)
+			assert.NotZero(t, location.Latitude)
+		})
+	}
+
+	_, ok := Resolve("Atlantis")
+	assert.False(t, ok)
+	_, ok = Resolve("  ")
+	assert.False(t, ok)
+}
+
+func TestResolve_CountryUsesPrimaryCityCoordinates(t *testing.T) {
+	location, ok := Resolve("Japan")
+	require.True(t, ok)
+	assert.Equal(t, "Tokyo", location.City)
+	assert.InDelta(t, 35.68, location.Latitude, 0.01)
+	assert.InDelta(t, 139.65, location.Longitude, 0.01)
+}
+
+func TestAirportCodeAndCoordinates(t *testing.T) {
+	assert.Equal(t, "ICN", AirportCode("โซล"))
+	assert.Equal(t, "", AirportCode("Atlantis"))
+
+	lat, lng, ok := Coordinates("Bangkok")
+	require.True(t, ok)
+	assert.InDelta(t, 13.7563, lat, 0.0001)
+	assert.InDelta(t, 100.5018, lng, 0.0001)
+}
+
+func TestPrimaryAirport(t *testing.T) {
+	airport, ok := PrimaryAirport("United States")
+	require.True(t, ok)
+	assert.Equal(t, "JFK", airport.Code)
+
+	airport, ok = PrimaryAirport("TH")
+	require.True(t, ok)
+	assert.Equal(t, "BKK", airport.Code)
+
+	_, ok = PrimaryAirport("Tokyo")
+	assert.False(t, ok, "Cities are not countries")
+}
+
+func TestDistanceKm(t *testing.T) {
+	distance, ok := DistanceKm("BKK", "NRT")
+	require.True(t, ok)
+	assert.InDelta(t, 4600, distance, 100)
+
+	_, ok = DistanceKm("BKK", "Atlantis")
+	assert.False(t, ok)
+}
@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// userKey is the fiber.Ctx local holding the authenticated user
+const userKey = "auth.user"
+
+// Middleware rejects requests without a valid access token and puts the user on the context
+func Middleware(tokens *TokenManager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := authenticate(c, tokens)
+		if err != nil || user == nil {
+			message := "Authorization header with a Bearer token is required"
+			if err != nil {
+				message = err.Error()
+			}
+			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+				Error:   "Unauthorized",
+				Message: message,
+				Code:    fiber.StatusUnauthorized,
+			})
+		}
+		SetUser(c, user)
+		return c.Next()
+	}
+}
+
+// OptionalMiddleware puts the user on the context when the request carries a valid access token
+// and lets anonymous requests through; invalid tokens are still rejected
+func OptionalMiddleware(tokens *TokenManager) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, err := authenticate(c, tokens)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+				Error:   "Unauthorized",
+				Message: err.Error(),
+				Code:    fiber.StatusUnauthorized,
+			})
+		}
+		if user != nil {
+			SetUser(c, user)
+		}
+		return c.Next()
+	}
+}
+
+// SetUser puts a user on the request context
+func SetUser(c *fiber.Ctx, user *User) {
+	c.Locals(userKey, user)
+}
+
+// CurrentUser returns the authenticated user, or nil for anonymous requests
+func CurrentUser(c *fiber.Ctx) *User {
+	user, _ := c.Locals(userKey).(*User)
+	return user
+}
+
+// authenticate reads the Bearer token of a request; requests without one have no user
+func authenticate(c *fiber.Ctx, tokens *TokenManager) (*User, error) {
+	header := c.Get(fiber.HeaderAuthorization)
+	if header == "" {
+		return nil, nil
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, ErrInvalidToken
+	}
+
+	claims, err := tokens.Verify(strings.TrimSpace(token), AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &User{ID: claims.Subject, Email: claims.Email}, nil
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	tokens := NewTokenManager("test-secret", time.Hour, 0)
+	pair, err := tokens.Issue(&User{ID: "user-1", Email: "somchai@example.com"})
+	require.NoError(t, err)
+
+	whoami := func(c *fiber.Ctx) error {
+		if user := CurrentUser(c); user != nil {
+			return c.SendString(user.ID)
+		}
+		return c.SendString("anonymous")
+	}
+
+	app := fiber.New()
+	app.Get("/required", Middleware(tokens), whoami)
+	app.Get("/optional", OptionalMiddleware(tokens), whoami)
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{"Valid token", "/required", "Bearer " + pair.AccessToken, fiber.StatusOK, "user-1"},
+		{"Lower-case scheme", "/required", "bearer " + pair.AccessToken, fiber.StatusOK, "user-1"},
+		{"No token", "/required", "", fiber.StatusUnauthorized, ""},
+		{"Refresh token", "/required", "Bearer " + pair.RefreshToken, fiber.StatusUnauthorized, ""},
+		{"Basic auth", "/required", "Basic dXNlcjpwYXNz", fiber.StatusUnauthorized, ""},
+		{"Anonymous optional", "/optional", "", fiber.StatusOK, "anonymous"},
+		{"Signed-in optional", "/optional", "Bearer " + pair.AccessToken, fiber.StatusOK, "user-1"},
+		{"Garbled optional", "/optional", "Bearer garbage", fiber.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			if tt.wantBody != "" {
+				body, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantBody, string(body))
+			}
+		})
+	}
+}
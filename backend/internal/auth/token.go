@@ -0,0 +1,112 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Token types, kept in the typ claim so a refresh token cannot be used as an access token
+const (
+	AccessToken  = "access"
+	RefreshToken = "refresh"
+)
+
+// Default token lifetimes
+const (
+	DefaultAccessTTL  = 24 * time.Hour
+	DefaultRefreshTTL = 30 * 24 * time.Hour
+)
+
+// ErrInvalidToken is returned for tokens that are malformed, expired, wrongly signed or of the wrong type
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Claims are the JWT claims of access and refresh tokens; the subject is the user ID
+type Claims struct {
+	Email string `json:"email,omitempty"`
+	Type  string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+// TokenPair is what a client receives on login, registration and refresh
+type TokenPair struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// TokenManager issues and verifies HMAC-signed JWTs
+type TokenManager struct {
+	secret     []byte
+	accessTTL  time.Duration
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+// NewTokenManager creates a token manager; zero lifetimes fall back to the defaults
+func NewTokenManager(secret string, accessTTL, refreshTTL time.Duration) *TokenManager {
+	if accessTTL <= 0 {
+		accessTTL = DefaultAccessTTL
+	}
+	if refreshTTL <= 0 {
+		refreshTTL = DefaultRefreshTTL
+	}
+	return &TokenManager{
+		secret:     []byte(secret),
+		accessTTL:  accessTTL,
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+// Issue signs a new access and refresh token for a user
+func (m *TokenManager) Issue(user *User) (TokenPair, error) {
+	now := m.now()
+	access, err := m.sign(user, AccessToken, now, m.accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := m.sign(user, RefreshToken, now, m.refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresAt:    now.Add(m.accessTTL).UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Verify checks a token's signature, expiry and type and returns its claims
+func (m *TokenManager) Verify(token, tokenType string) (*Claims, error) {
+	claims := &Claims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithTimeFunc(m.now))
+	if err != nil || !parsed.Valid || claims.Type != tokenType || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// sign creates one token of the given type
+func (m *TokenManager) sign(user *User, tokenType string, now time.Time, ttl time.Duration) (string, error) {
+	claims := Claims{
+		Email: user.Email,
+		Type:  tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s token: %w", tokenType, err)
+	}
+	return signed, nil
+}
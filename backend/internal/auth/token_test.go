@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestTokenManager(t *testing.T) {
+	tokens := NewTokenManager("test-secret", time.Hour, 48*time.Hour)
+	now := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	tokens.now = func() time.Time { return now }
+
+	user := &User{ID: "user-1", Email: "somchai@example.com"}
+	pair, err := tokens.Issue(user)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), pair.ExpiresAt)
+
+	t.Run("Verifies tokens of the right type", func(t *testing.T) {
+		claims, err := tokens.Verify(pair.AccessToken, AccessToken)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.Subject)
+		assert.Equal(t, "somchai@example.com", claims.Email)
+
+		_, err = tokens.Verify(pair.RefreshToken, RefreshToken)
+		assert.NoError(t, err)
+
+		_, err = tokens.Verify(pair.RefreshToken, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Access tokens expire before refresh tokens", func(t *testing.T) {
+		later := NewTokenManager("test-secret", time.Hour, 48*time.Hour)
+		later.now = func() time.Time { return now.Add(2 * time.Hour) }
+
+		_, err := later.Verify(pair.AccessToken, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+		_, err = later.Verify(pair.RefreshToken, RefreshToken)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rejects tokens signed with another secret", func(t *testing.T) {
+		other := NewTokenManager("other-secret", time.Hour, 0)
+		other.now = tokens.now
+
+		_, err := other.Verify(pair.AccessToken, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+
+	t.Run("Rejects unsigned tokens", func(t *testing.T) {
+		unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, Claims{
+			Type:             AccessToken,
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1", ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour))},
+		}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = tokens.Verify(unsigned, AccessToken)
+		assert.ErrorIs(t, err, ErrInvalidToken)
+	})
+}
+
+func TestPasswords(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, "correct horse", hash)
+	assert.True(t, CheckPassword(hash, "correct horse"))
+	assert.False(t, CheckPassword(hash, "wrong horse"))
+
+	user := &User{ID: "user-1", PasswordHash: hash}
+	assert.True(t, CheckUserPassword(user, "correct horse"))
+	assert.False(t, CheckUserPassword(user, "wrong horse"))
+	assert.False(t, CheckUserPassword(nil, "correct horse"))
+
+	// Unknown users cost one comparison at the cost real hashes use
+	cost, err := bcrypt.Cost([]byte(dummyPasswordHash))
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.DefaultCost, cost)
+}
@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrEmailTaken is returned when registering an email address that already has an account
+var ErrEmailTaken = errors.New("email is already registered")
+
+// MinPasswordLength is the shortest password accepted at registration
+const MinPasswordLength = 8
+
+// User is an account that owns searches, trips and flight watches
+type User struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name,omitempty"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// UserStore persists user accounts
+type UserStore interface {
+	// CreateUser stores a new user, returning ErrEmailTaken if the email is already registered
+	CreateUser(ctx context.Context, user *User) error
+	// GetUser returns the user with the given ID, or nil if there is none
+	GetUser(ctx context.Context, id string) (*User, error)
+	// GetUserByEmail returns the user with the given email, or nil if there is none
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+}
+
+// NormalizeEmail lower-cases and trims an email address so lookups ignore case
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// HashPassword hashes a password with bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// CheckPassword reports whether a password matches a hash from HashPassword
+func CheckPassword(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// dummyPasswordHash is a bcrypt hash at the default cost that no login password matches
+const dummyPasswordHash = "$2a$10$ztBsxgNWrBw3lT0XiWTjo.zlXN7b5ujpoV4eyAo5t9GduppJWPbNu"
+
+// CheckUserPassword reports whether a password is the user's. Without a user it still runs a bcrypt
+// comparison, so logins take as long for unknown emails as for wrong passwords.
+func CheckUserPassword(user *User, password string) bool {
+	if user == nil {
+		CheckPassword(dummyPasswordHash, password)
+		return false
+	}
+	return CheckPassword(user.PasswordHash, password)
+}
+
+// MemoryUserStore keeps users in process memory
+type MemoryUserStore struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+// NewMemoryUserStore creates an empty in-memory user store
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{users: make(map[string]User)}
+}
+
+// CreateUser implements UserStore
+func (s *MemoryUserStore) CreateUser(ctx context.Context, user *User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, existing := range s.users {
+		if existing.Email == user.Email {
+			return ErrEmailTaken
+		}
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	s.users[user.ID] = *user
+	return nil
+}
+
+// GetUser implements UserStore
+func (s *MemoryUserStore) GetUser(ctx context.Context, id string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// GetUserByEmail implements UserStore
+func (s *MemoryUserStore) GetUserByEmail(ctx context.Context, email string) (*User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, user := range s.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,25 @@
+// Package cache stores JSON values with a TTL in Redis or in process memory.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrMiss is returned by GetJSON when the key does not exist or has expired
+var ErrMiss = errors.New("cache miss")
+
+// Cache stores JSON-encoded values under string keys
+type Cache interface {
+	// GetJSON decodes the value stored at key into dest, or returns ErrMiss
+	GetJSON(ctx context.Context, key string, dest interface{}) error
+	// SetJSON encodes value as JSON and stores it for ttl; a zero ttl never expires
+	SetJSON(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// Delete removes the keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+	// GetMany returns the raw JSON of the keys that exist; missing keys are left out
+	GetMany(ctx context.Context, keys []string) (map[string][]byte, error)
+	// SetMany encodes and stores every value with the same ttl
+	SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error
+}
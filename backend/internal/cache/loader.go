@@ -0,0 +1,250 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCachedFailure is wrapped by Load when a recent upstream failure is served from the negative cache
+var ErrCachedFailure = errors.New("recent lookup failed")
+
+// defaultFetchTimeout bounds fetches that run detached from their caller: shared fetches and
+// background refreshes
+const defaultFetchTimeout = 30 * time.Second
+
+// Policy controls how long a Loader serves what it fetched
+type Policy struct {
+	// Fresh is how long a value is served without refetching
+	Fresh time.Duration
+	// Stale is how long after Fresh a value is still served while it is refreshed in the background
+	Stale time.Duration
+	// Negative is how long a failure is remembered before fetching again; zero disables negative caching
+	Negative time.Duration
+}
+
+// FetchFunc loads a value from the upstream API
+type FetchFunc func(ctx context.Context) (interface{}, error)
+
+// Stats counts how a Loader's lookups were served
+type Stats struct {
+	Name         string `json:"name"`
+	Hits         int64  `json:"hits"`
+	Misses       int64  `json:"misses"`
+	Stale        int64  `json:"stale"`
+	NegativeHits int64  `json:"negative_hits"`
+	Coalesced    int64  `json:"coalesced"`
+	Refreshes    int64  `json:"refreshes"`
+	Errors       int64  `json:"errors"`
+}
+
+// loaderEntry is what a Loader stores: a value or a failure, and when it stops being fresh
+type loaderEntry struct {
+	Value      json.RawMessage `json:"value,omitempty"`
+	Error      string          `json:"error,omitempty"`
+	FreshUntil time.Time       `json:"fresh_until"`
+}
+
+// loaderCall is an in-flight fetch that concurrent lookups of the same key wait on
+type loaderCall struct {
+	done  chan struct{}
+	value json.RawMessage
+	err   error
+}
+
+// Loader reads through a Cache to an upstream API. Concurrent lookups of the same key share one
+// fetch, stale values are served while they refresh in the background, and failures are cached
+// briefly so a failing API is not hammered. A nil Loader fetches every time.
+type Loader struct {
+	name         string
+	cache        Cache
+	policy       Policy
+	now          func() time.Time
+	fetchTimeout time.Duration
+
+	mu    sync.Mutex
+	calls map[string]*loaderCall
+
+	hits         int64
+	misses       int64
+	stale        int64
+	negativeHits int64
+	coalesced    int64
+	refreshes    int64
+	errors       int64
+}
+
+// NewLoader creates a loader named for its stats that stores entries in c
+func NewLoader(name string, c Cache, policy Policy) *Loader {
+	return &Loader{
+		name:         name,
+		cache:        c,
+		policy:       policy,
+		now:          time.Now,
+		fetchTimeout: defaultFetchTimeout,
+		calls:        make(map[string]*loaderCall),
+	}
+}
+
+// Load decodes the value for key into dest, calling fetch when the cache has no usable entry
+func (l *Loader) Load(ctx context.Context, key string, dest interface{}, fetch FetchFunc) error {
+	if l == nil {
+		value, err := fetch(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		return decodeInto(data, dest)
+	}
+
+	var entry loaderEntry
+	err := l.cache.GetJSON(ctx, key, &entry)
+	switch {
+	case err == nil && entry.Error != "":
+		atomic.AddInt64(&l.negativeHits, 1)
+		return fmt.Errorf("%w: %s", ErrCachedFailure, entry.Error)
+	case err == nil:
+		if l.now().Before(entry.FreshUntil) {
+			atomic.AddInt64(&l.hits, 1)
+		} else {
+			atomic.AddInt64(&l.stale, 1)
+			l.refresh(key, fetch)
+		}
+		return decodeInto(entry.Value, dest)
+	case !errors.Is(err, ErrMiss):
+		log.Printf("Cache %s: Read failed, fetching %s: %v", l.name, key, err)
+	}
+
+	atomic.AddInt64(&l.misses, 1)
+	value, err := l.do(ctx, key, fetch)
+	if err != nil {
+		return err
+	}
+	return decodeInto(value, dest)
+}
+
+// Stats returns the loader's counters
+func (l *Loader) Stats() Stats {
+	if l == nil {
+		return Stats{}
+	}
+	return Stats{
+		Name:         l.name,
+		Hits:         atomic.LoadInt64(&l.hits),
+		Misses:       atomic.LoadInt64(&l.misses),
+		Stale:        atomic.LoadInt64(&l.stale),
+		NegativeHits: atomic.LoadInt64(&l.negativeHits),
+		Coalesced:    atomic.LoadInt64(&l.coalesced),
+		Refreshes:    atomic.LoadInt64(&l.refreshes),
+		Errors:       atomic.LoadInt64(&l.errors),
+	}
+}
+
+// do fetches key once; lookups that arrive while it is in flight wait for the same result. Each
+// lookup gives up when its own ctx is done, while the shared fetch carries on for the others.
+func (l *Loader) do(ctx context.Context, key string, fetch FetchFunc) (json.RawMessage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	call, ok := l.calls[key]
+	if ok {
+		atomic.AddInt64(&l.coalesced, 1)
+	} else {
+		call = &loaderCall{done: make(chan struct{})}
+		l.calls[key] = call
+		go func() {
+			// Detached so a caller with a short deadline cannot fail the lookups waiting on it
+			fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), l.fetchTimeout)
+			defer cancel()
+
+			call.value, call.err = l.fetch(fetchCtx, key, fetch, true)
+			l.finish(key, call)
+		}()
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// refresh refetches a stale key in the background unless a fetch is already in flight
+func (l *Loader) refresh(key string, fetch FetchFunc) {
+	l.mu.Lock()
+	if _, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		return
+	}
+	call := &loaderCall{done: make(chan struct{})}
+	l.calls[key] = call
+	l.mu.Unlock()
+
+	atomic.AddInt64(&l.refreshes, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), l.fetchTimeout)
+		defer cancel()
+
+		// A failed refresh keeps serving the stale value rather than caching the failure
+		call.value, call.err = l.fetch(ctx, key, fetch, false)
+		if call.err != nil {
+			log.Printf("Cache %s: Refresh of %s failed: %v", l.name, key, call.err)
+		}
+		l.finish(key, call)
+	}()
+}
+
+// finish releases the lookups waiting on an in-flight fetch
+func (l *Loader) finish(key string, call *loaderCall) {
+	l.mu.Lock()
+	delete(l.calls, key)
+	l.mu.Unlock()
+	close(call.done)
+}
+
+// fetch calls the upstream API and stores the value, or the failure when negative is set
+func (l *Loader) fetch(ctx context.Context, key string, fetch FetchFunc, negative bool) (json.RawMessage, error) {
+	value, err := fetch(ctx)
+	if err != nil {
+		atomic.AddInt64(&l.errors, 1)
+		// A fetch that ran out of time says nothing about what the upstream API answers
+		if negative && l.policy.Negative > 0 && ctx.Err() == nil {
+			entry := loaderEntry{Error: err.Error(), FreshUntil: l.now().Add(l.policy.Negative)}
+			if err := l.cache.SetJSON(ctx, key, entry, l.policy.Negative); err != nil {
+				log.Printf("Cache %s: Failed to store failure for %s: %v", l.name, key, err)
+			}
+		}
+		return nil, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	entry := loaderEntry{Value: data, FreshUntil: l.now().Add(l.policy.Fresh)}
+	if err := l.cache.SetJSON(ctx, key, entry, l.policy.Fresh+l.policy.Stale); err != nil {
+		log.Printf("Cache %s: Failed to store %s: %v", l.name, key, err)
+	}
+	return data, nil
+}
+
+// decodeInto copies a JSON value into dest
+func decodeInto(data json.RawMessage, dest interface{}) error {
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode cached value: %w", err)
+	}
+	return nil
+}
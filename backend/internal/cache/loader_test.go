@@ -0,0 +1,239 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPolicy = Policy{Fresh: time.Minute, Stale: time.Hour, Negative: 30 * time.Second}
+
+// newTestLoader returns a loader on a memory cache whose clock is controlled by the returned pointer
+func newTestLoader() (*Loader, *time.Time) {
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	l := NewLoader("test", c, testPolicy)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestLoader_HitsAndMisses(t *testing.T) {
+	l, _ := newTestLoader()
+	calls := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return []string{"Wat Pho", "Wat Arun"}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		var places []string
+		require.NoError(t, l.Load(context.Background(), "places:bangkok", &places, fetch))
+		assert.Equal(t, []string{"Wat Pho", "Wat Arun"}, places)
+	}
+
+	assert.Equal(t, 1, calls)
+	stats := l.Stats()
+	assert.Equal(t, "test", stats.Name)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.Equal(t, int64(2), stats.Hits)
+}
+
+func TestLoader_CoalescesConcurrentLookups(t *testing.T) {
+	l, _ := newTestLoader()
+	var calls int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return 21.5, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]float64, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, l.Load(context.Background(), "weather:tokyo", &results[i], fetch))
+		}(i)
+	}
+
+	// Every lookup has either started the fetch or is waiting on it
+	require.Eventually(t, func() bool {
+		stats := l.Stats()
+		return stats.Misses+stats.Hits == 10
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for _, result := range results {
+		assert.Equal(t, 21.5, result)
+	}
+	assert.Equal(t, int64(9), l.Stats().Coalesced)
+}
+
+func TestLoader_SharedFetchOutlivesFirstCaller(t *testing.T) {
+	l, _ := newTestLoader()
+	var calls int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-release:
+			return "Sunny", nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// The first caller starts the fetch and gives up at its own deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	firstErr := make(chan error, 1)
+	go func() {
+		var value string
+		firstErr <- l.Load(ctx, "weather:tokyo", &value, fetch)
+	}()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, time.Millisecond)
+
+	secondErr := make(chan error, 1)
+	var second string
+	go func() {
+		secondErr <- l.Load(context.Background(), "weather:tokyo", &second, fetch)
+	}()
+	require.Eventually(t, func() bool { return l.Stats().Coalesced == 1 }, time.Second, time.Millisecond)
+
+	assert.ErrorIs(t, <-firstErr, context.DeadlineExceeded)
+	close(release)
+	require.NoError(t, <-secondErr)
+	assert.Equal(t, "Sunny", second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	var cached string
+	require.NoError(t, l.Load(context.Background(), "weather:tokyo", &cached, fetch))
+	assert.Equal(t, "Sunny", cached)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestLoader_ServesStaleWhileRefreshing(t *testing.T) {
+	l, now := newTestLoader()
+	var calls int32
+	fetch := func(ctx context.Context) (interface{}, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+
+	var value int
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, 1, value)
+
+	*now = now.Add(2 * time.Minute)
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, 1, value, "The stale value is served immediately")
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool {
+		var entry loaderEntry
+		return l.cache.GetJSON(context.Background(), "key", &entry) == nil && string(entry.Value) == "2"
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, 2, value, "The refreshed value is fresh again")
+
+	stats := l.Stats()
+	assert.Equal(t, int64(1), stats.Refreshes)
+	assert.Equal(t, int64(1), stats.Stale)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	// Past the stale window the entry has expired
+	*now = now.Add(2 * time.Hour)
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, 3, value)
+}
+
+func TestLoader_KeepsStaleValueWhenRefreshFails(t *testing.T) {
+	l, now := newTestLoader()
+	failing := int32(0)
+	fetch := func(ctx context.Context) (interface{}, error) {
+		if atomic.LoadInt32(&failing) == 1 {
+			return nil, errors.New("rate limited")
+		}
+		return "sunny", nil
+	}
+
+	var value string
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+
+	atomic.StoreInt32(&failing, 1)
+	*now = now.Add(2 * time.Minute)
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	require.Eventually(t, func() bool { return l.Stats().Errors == 1 }, time.Second, time.Millisecond)
+
+	value = ""
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, "sunny", value)
+}
+
+func TestLoader_CachesFailures(t *testing.T) {
+	l, now := newTestLoader()
+	calls := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("upstream returned 503")
+		}
+		return "ok", nil
+	}
+
+	var value string
+	assert.EqualError(t, l.Load(context.Background(), "key", &value, fetch), "upstream returned 503")
+
+	err := l.Load(context.Background(), "key", &value, fetch)
+	assert.ErrorIs(t, err, ErrCachedFailure)
+	assert.ErrorContains(t, err, "upstream returned 503")
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, int64(1), l.Stats().NegativeHits)
+
+	*now = now.Add(31 * time.Second)
+	require.NoError(t, l.Load(context.Background(), "key", &value, fetch))
+	assert.Equal(t, "ok", value)
+}
+
+func TestLoader_DoesNotCacheCancellations(t *testing.T) {
+	l, _ := newTestLoader()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var value string
+	err := l.Load(ctx, "key", &value, func(ctx context.Context) (interface{}, error) {
+		return nil, ctx.Err()
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, l.Load(context.Background(), "key", &value, func(ctx context.Context) (interface{}, error) {
+		return "ok", nil
+	}))
+	assert.Equal(t, "ok", value)
+}
+
+func TestLoader_NilFetchesEveryTime(t *testing.T) {
+	var l *Loader
+	calls := 0
+
+	var value int
+	for i := 0; i < 2; i++ {
+		require.NoError(t, l.Load(context.Background(), "key", &value, func(ctx context.Context) (interface{}, error) {
+			calls++
+			return calls, nil
+		}))
+	}
+	assert.Equal(t, 2, value)
+	assert.Equal(t, Stats{}, l.Stats())
+}
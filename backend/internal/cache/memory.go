@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a stored JSON value and when it expires (zero for never)
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// MemoryCache keeps values in process memory (used in tests and local runs)
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+// GetJSON implements Cache
+func (c *MemoryCache) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	data, ok := c.get(key)
+	if !ok {
+		return ErrMiss
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetJSON implements Cache
+func (c *MemoryCache) SetJSON(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.SetMany(ctx, map[string]interface{}{key: value}, ttl)
+}
+
+// Delete implements Cache
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// GetMany implements Cache
+func (c *MemoryCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if data, ok := c.get(key); ok {
+			values[key] = data
+		}
+	}
+	return values, nil
+}
+
+// SetMany implements Cache
+func (c *MemoryCache) SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	encoded := make(map[string][]byte, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		encoded[key] = data
+	}
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, data := range encoded {
+		c.entries[key] = memoryEntry{data: data, expiresAt: expiresAt}
+	}
+	return nil
+}
+
+// get returns a key's data unless it is missing or expired; expired entries are dropped
+func (c *MemoryCache) get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		c.mu.Lock()
+		if current, ok := c.entries[key]; ok && current.expiresAt.Equal(entry.expiresAt) {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+		return nil, false
+	}
+	return entry.data, true
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cachedPlace struct {
+	Name   string  `json:"name"`
+	Rating float64 `json:"rating"`
+}
+
+func TestMemoryCache_GetSetDelete(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+
+	var place cachedPlace
+	assert.Equal(t, ErrMiss, c.GetJSON(ctx, "place:1", &place))
+
+	require.NoError(t, c.SetJSON(ctx, "place:1", cachedPlace{Name: "Wat Pho", Rating: 4.7}, time.Minute))
+	require.NoError(t, c.GetJSON(ctx, "place:1", &place))
+	assert.Equal(t, cachedPlace{Name: "Wat Pho", Rating: 4.7}, place)
+
+	require.NoError(t, c.Delete(ctx, "place:1", "place:unknown"))
+	assert.Equal(t, ErrMiss, c.GetJSON(ctx, "place:1", &place))
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 5, 1, 8, 0, 0, 0, time.UTC)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+
+	require.NoError(t, c.SetJSON(ctx, "short", 1, time.Minute))
+	require.NoError(t, c.SetJSON(ctx, "forever", 2, 0))
+
+	now = now.Add(time.Minute)
+	var value int
+	assert.Equal(t, ErrMiss, c.GetJSON(ctx, "short", &value))
+	require.NoError(t, c.GetJSON(ctx, "forever", &value))
+	assert.Equal(t, 2, value)
+}
+
+func TestMemoryCache_Batch(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+
+	require.NoError(t, c.SetMany(ctx, map[string]interface{}{
+		"weather:tokyo":   map[string]int{"temp": 18},
+		"weather:bangkok": map[string]int{"temp": 33},
+	}, time.Hour))
+
+	values, err := c.GetMany(ctx, []string{"weather:tokyo", "weather:paris", "weather:bangkok"})
+	require.NoError(t, err)
+	assert.Len(t, values, 2)
+	assert.JSONEq(t, `{"temp":18}`, string(values["weather:tokyo"]))
+	assert.JSONEq(t, `{"temp":33}`, string(values["weather:bangkok"]))
+}
+
+func TestMemoryCache_StoresCopies(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache()
+
+	tags := []string{"temple"}
+	require.NoError(t, c.SetJSON(ctx, "tags", tags, 0))
+	tags[0] = "changed"
+
+	var cached []string
+	require.NoError(t, c.GetJSON(ctx, "tags", &cached))
+	assert.Equal(t, []string{"temple"}, cached)
+}
@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache stores values in Redis using a shared client
+type RedisCache struct {
+	client redis.UniversalClient
+}
+
+// NewRedisCache creates a cache on an existing Redis connection, such as database.RedisCache.Client
+func NewRedisCache(client redis.UniversalClient) *RedisCache {
+	return &RedisCache{client: client}
+}
+
+// GetJSON implements Cache
+func (c *RedisCache) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return ErrMiss
+	} else if err != nil {
+		return fmt.Errorf("failed to get %s: %w", key, err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to decode cached %s: %w", key, err)
+	}
+	return nil
+}
+
+// SetJSON implements Cache
+func (c *RedisCache) SetJSON(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if err := c.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete keys: %w", err)
+	}
+	return nil
+}
+
+// GetMany implements Cache with a single MGET
+func (c *RedisCache) GetMany(ctx context.Context, keys []string) (map[string][]byte, error) {
+	values := make(map[string][]byte, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	results, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get keys: %w", err)
+	}
+	for i, result := range results {
+		if data, ok := result.(string); ok {
+			values[keys[i]] = []byte(data)
+		}
+	}
+	return values, nil
+}
+
+// SetMany implements Cache with a single pipelined round trip
+func (c *RedisCache) SetMany(ctx context.Context, values map[string]interface{}, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := c.client.Pipeline()
+	for key, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", key, err)
+		}
+		pipe.Set(ctx, key, data, ttl)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set keys: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 
@@ -18,6 +19,7 @@ type Config struct {
 	Hotel        HotelConfig
 	GooglePlaces GooglePlacesConfig
 	JWT          JWTConfig
+	Currency     CurrencyConfig
 	Env          EnvironmentConfig
 }
 
@@ -62,6 +64,8 @@ type WeatherConfig struct {
 type FlightConfig struct {
 	APIKey string
 	URL    string
+	// WatchInterval is how often watched flights are re-checked, e.g. "5m"
+	WatchInterval string
 }
 
 // HotelConfig holds Hotel API configuration
@@ -76,10 +80,25 @@ type GooglePlacesConfig struct {
 	URL    string
 }
 
+// DefaultJWTSecret is the development signing secret; the server refuses to use it in production
+const DefaultJWTSecret = "default-secret-change-in-production"
+
+// placeholderJWTSecrets are example secrets from the setup docs, no safer than the default
+var placeholderJWTSecrets = []string{DefaultJWTSecret, "your_jwt_secret_here", "your-secure-jwt-secret"}
+
 // JWTConfig holds JWT authentication configuration
 type JWTConfig struct {
 	Secret    string
 	ExpiresIn string
+	// RefreshExpiresIn is how long refresh tokens stay valid, e.g. "720h"
+	RefreshExpiresIn string
+}
+
+// CurrencyConfig holds exchange rate and display currency settings
+type CurrencyConfig struct {
+	// RatesFile is a JSON rate table; the bundled rates are used when empty
+	RatesFile string
+	Default   string
 }
 
 // EnvironmentConfig holds environment-specific settings
@@ -126,6 +145,8 @@ func LoadConfig() (*Config, error) {
 		Flight: FlightConfig{
 			APIKey: getEnv("FLIGHT_API_KEY", ""),
 			URL:    getEnv("FLIGHT_API_URL", "https://api.aviationstack.com/v1"),
+
+			WatchInterval: getEnv("FLIGHT_WATCH_INTERVAL", "5m"),
 		},
 		Hotel: HotelConfig{
 			APIKey: getEnv("HOTEL_API_KEY", ""),
@@ -136,8 +157,14 @@ func LoadConfig() (*Config, error) {
 			URL:    getEnv("GOOGLE_PLACES_API_URL", "https://maps.googleapis.com/maps/api/place"),
 		},
 		JWT: JWTConfig{
-			Secret:    getEnv("JWT_SECRET", "default-secret-change-in-production"),
+			Secret:    getEnv("JWT_SECRET", DefaultJWTSecret),
 			ExpiresIn: getEnv("JWT_EXPIRES_IN", "24h"),
+
+			RefreshExpiresIn: getEnv("JWT_REFRESH_EXPIRES_IN", "720h"),
+		},
+		Currency: CurrencyConfig{
+			RatesFile: getEnv("CURRENCY_RATES_FILE", ""),
+			Default:   getEnv("DEFAULT_CURRENCY", "THB"),
 		},
 		Env: EnvironmentConfig{
 			Environment: getEnv("ENVIRONMENT", "development"),
@@ -145,9 +172,30 @@ func LoadConfig() (*Config, error) {
 		},
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
+// Validate rejects settings that are unsafe to run with, such as signing tokens in production
+// with a secret anyone can read in this repository
+func (c *Config) Validate() error {
+	if c.Env.Environment != "production" {
+		return nil
+	}
+	for _, placeholder := range placeholderJWTSecrets {
+		if c.JWT.Secret == placeholder {
+			return fmt.Errorf("JWT_SECRET must be set to a private value in production")
+		}
+	}
+	if len(c.JWT.Secret) < 32 {
+		return fmt.Errorf("JWT_SECRET must be at least 32 characters in production")
+	}
+	return nil
+}
+
 // getEnv retrieves an environment variable or returns a default value
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		environment string
+		secret      string
+		wantErr     bool
+	}{
+		{"Default secret in development", "development", DefaultJWTSecret, false},
+		{"Default secret in production", "production", DefaultJWTSecret, true},
+		{"Example secret in production", "production", "your_jwt_secret_here", true},
+		{"Short secret in production", "production", "s3cret", true},
+		{"Private secret in production", "production", "f3b1c9e07a5d4c2b8e6f1a0d9c7b5e3a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				JWT: JWTConfig{Secret: tt.secret},
+				Env: EnvironmentConfig{Environment: tt.environment},
+			}
+			if tt.wantErr {
+				assert.Error(t, cfg.Validate())
+			} else {
+				assert.NoError(t, cfg.Validate())
+			}
+		})
+	}
+}
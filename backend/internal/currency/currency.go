@@ -0,0 +1,104 @@
+// Package currency converts money between currencies using exchange rates from a provider.
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Base is the currency agents price in; amounts are converted from it for display
+const Base = "THB"
+
+// ErrMismatch is returned when amounts in different currencies are added without conversion
+var ErrMismatch = errors.New("currency mismatch")
+
+// Money is an amount in a currency
+type Money struct {
+	Amount   float64 `json:"amount"`
+	Currency string  `json:"currency"`
+}
+
+// New creates a money value, normalizing the currency code
+func New(amount float64, code string) Money {
+	if normalized, ok := Normalize(code); ok {
+		code = normalized
+	}
+	return Money{Amount: amount, Currency: code}
+}
+
+// Add sums two amounts in the same currency; use Converter.Sum for mixed currencies
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrMismatch, m.Currency, other.Currency)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Round rounds the amount to the currency's displayed decimals
+func (m Money) Round() Money {
+	scale := math.Pow(10, float64(Decimals(m.Currency)))
+	return Money{Amount: math.Round(m.Amount*scale) / scale, Currency: m.Currency}
+}
+
+// String formats the amount with its currency code, e.g. "1500 THB" or "42.50 USD"
+func (m Money) String() string {
+	return Format(m.Amount, m.Currency)
+}
+
+// Format formats an amount with the currency's displayed decimals and code
+func Format(amount float64, code string) string {
+	return fmt.Sprintf("%.*f %s", Decimals(code), amount, code)
+}
+
+// zeroDecimal lists currencies shown without minor units; baht is shown in whole baht
+var zeroDecimal = map[string]bool{
+	"THB": true, "JPY": true, "KRW": true, "VND": true, "IDR": true, "LAK": true, "KHR": true, "MMK": true,
+}
+
+// Decimals returns how many decimals amounts in the currency are shown with
+func Decimals(code string) int {
+	if zeroDecimal[code] {
+		return 0
+	}
+	return 2
+}
+
+// aliases maps currency names and symbols in English and Thai to ISO codes
+var aliases = map[string]string{
+	"฿": "THB", "baht": "THB", "บาท": "THB",
+	"$": "USD", "us$": "USD", "dollar": "USD", "dollars": "USD", "us dollar": "USD", "us dollars": "USD", "ดอลลาร์": "USD", "ดอลลาร์สหรัฐ": "USD", "ดอลล่าร์": "USD",
+	"€": "EUR", "euro": "EUR", "euros": "EUR", "ยูโร": "EUR",
+	"£": "GBP", "pound": "GBP", "pounds": "GBP", "ปอนด์": "GBP",
+	"¥": "JPY", "yen": "JPY", "เยน": "JPY",
+	"yuan": "CNY", "rmb": "CNY", "หยวน": "CNY",
+	"₩": "KRW", "won": "KRW", "วอน": "KRW",
+	"s$": "SGD", "singapore dollar": "SGD", "singapore dollars": "SGD",
+	"ringgit": "MYR", "ริงกิต": "MYR",
+	"rupiah": "IDR", "รูเปียห์": "IDR",
+	"dong": "VND", "ดอง": "VND",
+	"peso": "PHP", "pesos": "PHP",
+	"rupee": "INR", "rupees": "INR", "รูปี": "INR",
+	"kip": "LAK", "กีบ": "LAK",
+	"a$": "AUD", "australian dollar": "AUD", "australian dollars": "AUD",
+	"c$": "CAD", "canadian dollar": "CAD", "canadian dollars": "CAD",
+	"franc": "CHF", "francs": "CHF",
+	"dirham": "AED", "dirhams": "AED",
+}
+
+// isoCode matches a three-letter currency code
+var isoCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Normalize turns a currency code, name or symbol ("usd", "yen", "บาท", "€") into an ISO code
+func Normalize(input string) (string, bool) {
+	value := strings.TrimSpace(input)
+	if code, ok := aliases[strings.ToLower(value)]; ok {
+		return code, true
+	}
+	if code := strings.ToUpper(value); isoCode.MatchString(code) {
+		return code, true
+	}
+	return "", false
+}
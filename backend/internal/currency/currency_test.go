@@ -0,0 +1,100 @@
+package currency
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRates() *Rates {
+	return NewRates("THB", map[string]float64{"USD": 0.03, "JPY": 4.5, "CAD": 0.04})
+}
+
+func TestNormalize(t *testing.T) {
+	for input, want := range map[string]string{
+		"usd": "USD", "$": "USD", "ดอลลาร์": "USD", "บาท": "THB", "฿": "THB",
+		"Yen": "JPY", "euros": "EUR", " gbp ": "GBP", "CHF": "CHF",
+	} {
+		code, ok := Normalize(input)
+		assert.True(t, ok, input)
+		assert.Equal(t, want, code, input)
+	}
+
+	_, ok := Normalize("money")
+	assert.False(t, ok)
+}
+
+func TestMoney(t *testing.T) {
+	assert.Equal(t, "1500 THB", New(1500.4, "baht").String())
+	assert.Equal(t, "42.50 USD", New(42.5, "usd").String())
+	assert.Equal(t, Money{Amount: 12.35, Currency: "USD"}, New(12.345, "USD").Round())
+	assert.Equal(t, Money{Amount: 1235, Currency: "JPY"}, New(1234.6, "JPY").Round())
+
+	sum, err := New(10, "USD").Add(New(5, "USD"))
+	require.NoError(t, err)
+	assert.Equal(t, New(15, "USD"), sum)
+
+	_, err = New(10, "USD").Add(New(5, "THB"))
+	assert.ErrorIs(t, err, ErrMismatch)
+}
+
+func TestConverter(t *testing.T) {
+	ctx := context.Background()
+	converter := NewConverter(testRates())
+
+	usd, err := converter.Convert(ctx, New(1000, "THB"), "USD")
+	require.NoError(t, err)
+	assert.InDelta(t, 30, usd.Amount, 0.001)
+	assert.Equal(t, "USD", usd.Currency)
+
+	// Crossed through the base currency
+	jpy, err := converter.Convert(ctx, New(100, "CAD"), "JPY")
+	require.NoError(t, err)
+	assert.InDelta(t, 11250, jpy.Amount, 0.001)
+
+	total, err := converter.Sum(ctx, "THB", New(1000, "THB"), New(30, "USD"), New(100, "CAD"))
+	require.NoError(t, err)
+	assert.InDelta(t, 1000+1000+2500, total.Amount, 0.001)
+
+	_, err = converter.Convert(ctx, New(1, "THB"), "EUR")
+	assert.ErrorContains(t, err, "no exchange rate for EUR")
+	assert.False(t, converter.Supports(ctx, "EUR"))
+	assert.True(t, converter.Supports(ctx, "JPY"))
+}
+
+func TestLoadRates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "usd", "rates": {"THB": 35, "EUR": 0.9}}`), 0o644))
+
+	rates, err := LoadRates(path)
+	require.NoError(t, err)
+	assert.Equal(t, "USD", rates.Base)
+
+	rate, err := rates.Rate(context.Background(), "THB", "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.9/35, rate, 1e-9)
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"base": "USD", "rates": {"THB": -1}}`), 0o644))
+	_, err = LoadRates(path)
+	assert.ErrorContains(t, err, "must be positive")
+}
+
+func TestDefaultRates(t *testing.T) {
+	rates := DefaultRates()
+	assert.Equal(t, Base, rates.Base)
+
+	for code := range rates.Rates {
+		normalized, ok := Normalize(code)
+		assert.True(t, ok, code)
+		assert.Equal(t, code, normalized)
+	}
+
+	// One US dollar buys roughly 30-40 baht
+	rate, err := rates.Rate(context.Background(), "USD", "THB")
+	require.NoError(t, err)
+	assert.InDelta(t, 35, rate, 5)
+}
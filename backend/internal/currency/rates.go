@@ -0,0 +1,137 @@
+package currency
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// RateProvider supplies exchange rates
+type RateProvider interface {
+	// Rate returns how many units of to one unit of from buys
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// Rates is a fixed rate table: units of each currency per one unit of Base
+type Rates struct {
+	Base      string             `json:"base"`
+	UpdatedAt string             `json:"updated_at,omitempty"`
+	Rates     map[string]float64 `json:"rates"`
+}
+
+// NewRates creates a rate table, e.g. a fake provider for tests
+func NewRates(base string, rates map[string]float64) *Rates {
+	table := &Rates{Base: base, Rates: make(map[string]float64, len(rates)+1)}
+	for code, rate := range rates {
+		table.Rates[code] = rate
+	}
+	table.Rates[base] = 1
+	return table
+}
+
+// Rate implements RateProvider by crossing both currencies through the table's base
+func (r *Rates) Rate(ctx context.Context, from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	fromRate, ok := r.Rates[from]
+	if !ok || fromRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := r.Rates[to]
+	if !ok || toRate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+	return toRate / fromRate, nil
+}
+
+// LoadRates reads a rate table from a JSON file shaped like rates.json
+func LoadRates(path string) (*Rates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+	return parseRates(data)
+}
+
+//go:embed rates.json
+var defaultRatesJSON []byte
+
+var (
+	defaultRatesOnce sync.Once
+	defaultRates     *Rates
+)
+
+// DefaultRates returns the bundled rate table, used when no rates file is configured
+func DefaultRates() *Rates {
+	defaultRatesOnce.Do(func() {
+		rates, err := parseRates(defaultRatesJSON)
+		if err != nil {
+			panic(fmt.Sprintf("currency: invalid embedded rates.json: %v", err))
+		}
+		defaultRates = rates
+	})
+	return defaultRates
+}
+
+// parseRates decodes and validates a rate table
+func parseRates(data []byte) (*Rates, error) {
+	var table Rates
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+	base, ok := Normalize(table.Base)
+	if !ok {
+		return nil, fmt.Errorf("exchange rates have an invalid base %q", table.Base)
+	}
+	for code, rate := range table.Rates {
+		if rate <= 0 {
+			return nil, fmt.Errorf("exchange rate for %s must be positive", code)
+		}
+	}
+	return NewRates(base, table.Rates), nil
+}
+
+// Converter converts money using a rate provider
+type Converter struct {
+	provider RateProvider
+}
+
+// NewConverter creates a converter
+func NewConverter(provider RateProvider) *Converter {
+	return &Converter{provider: provider}
+}
+
+// Convert converts an amount into another currency
+func (c *Converter) Convert(ctx context.Context, m Money, to string) (Money, error) {
+	if m.Currency == to {
+		return m, nil
+	}
+	rate, err := c.provider.Rate(ctx, m.Currency, to)
+	if err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount * rate, Currency: to}, nil
+}
+
+// Sum converts every amount into one currency and adds them
+func (c *Converter) Sum(ctx context.Context, to string, amounts ...Money) (Money, error) {
+	total := Money{Currency: to}
+	for _, amount := range amounts {
+		converted, err := c.Convert(ctx, amount, to)
+		if err != nil {
+			return Money{}, err
+		}
+		total.Amount += converted.Amount
+	}
+	return total, nil
+}
+
+// Supports reports whether amounts can be converted between Base and the currency
+func (c *Converter) Supports(ctx context.Context, code string) bool {
+	_, err := c.provider.Rate(ctx, Base, code)
+	return err == nil
+}
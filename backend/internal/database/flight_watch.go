@@ -0,0 +1,247 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+)
+
+// PostgresFlightWatchStore keeps flight watches in the flight_watches and flight_watch_events tables
+type PostgresFlightWatchStore struct {
+	db *PostgresDB
+}
+
+// NewPostgresFlightWatchStore creates a Postgres-backed flight watch store
+func NewPostgresFlightWatchStore(db *PostgresDB) *PostgresFlightWatchStore {
+	return &PostgresFlightWatchStore{db: db}
+}
+
+const flightWatchColumns = `id, COALESCE(user_id, ''), flight_code, flight_date, channel, target, active,
+	last_status, last_checked_at, created_at`
+
+// CreateWatch implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) CreateWatch(ctx context.Context, watch *agents.FlightWatch) error {
+	query := `
+		INSERT INTO flight_watches (user_id, flight_code, flight_date, channel, target, active)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := s.db.DB.QueryRowContext(ctx, query,
+		watch.UserID,
+		watch.FlightCode,
+		watch.FlightDate.Format("2006-01-02"),
+		watch.Channel,
+		watch.Target,
+		watch.Active,
+	).Scan(&watch.ID, &watch.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create flight watch: %w", err)
+	}
+	return nil
+}
+
+// GetWatch implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) GetWatch(ctx context.Context, id int64) (*agents.FlightWatch, error) {
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+flightWatchColumns+" FROM flight_watches WHERE id = $1", id)
+	watch, err := scanFlightWatch(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &watch, nil
+}
+
+// ListWatches implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) ListWatches(ctx context.Context, userID string) ([]agents.FlightWatch, error) {
+	return s.queryWatches(ctx, "SELECT "+flightWatchColumns+" FROM flight_watches WHERE user_id = $1 ORDER BY id", userID)
+}
+
+// ActiveWatches implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) ActiveWatches(ctx context.Context) ([]agents.FlightWatch, error) {
+	return s.queryWatches(ctx, "SELECT "+flightWatchColumns+" FROM flight_watches WHERE active ORDER BY id")
+}
+
+// UpdateWatchStatus implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) UpdateWatchStatus(ctx context.Context, id int64, status agents.FlightStatus, checkedAt time.Time, active bool) error {
+	statusJSON, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flight status: %w", err)
+	}
+
+	_, err = s.db.DB.ExecContext(ctx,
+		"UPDATE flight_watches SET last_status = $2, last_checked_at = $3, active = $4 WHERE id = $1",
+		id, statusJSON, checkedAt, active,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update flight watch: %w", err)
+	}
+	return nil
+}
+
+// DeleteWatch implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) DeleteWatch(ctx context.Context, id int64) error {
+	if _, err := s.db.DB.ExecContext(ctx, "DELETE FROM flight_watches WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete flight watch: %w", err)
+	}
+	return nil
+}
+
+// AddEvent implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) AddEvent(ctx context.Context, event *agents.FlightWatchEvent) error {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal changes: %w", err)
+	}
+	current, err := json.Marshal(event.Current)
+	if err != nil {
+		return fmt.Errorf("failed to marshal flight status: %w", err)
+	}
+	var previous []byte
+	if event.Previous != nil {
+		if previous, err = json.Marshal(event.Previous); err != nil {
+			return fmt.Errorf("failed to marshal flight status: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO flight_watch_events (watch_id, flight_code, changes, previous_status, current_status,
+			message, notified, error, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9)
+		RETURNING id
+	`
+
+	err = s.db.DB.QueryRowContext(ctx, query,
+		event.WatchID,
+		event.FlightCode,
+		changes,
+		previous,
+		current,
+		event.Message,
+		event.Notified,
+		event.Error,
+		event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save flight watch event: %w", err)
+	}
+	return nil
+}
+
+// ListEvents implements agents.FlightWatchStore
+func (s *PostgresFlightWatchStore) ListEvents(ctx context.Context, watchID int64) ([]agents.FlightWatchEvent, error) {
+	query := `
+		SELECT id, watch_id, flight_code, changes, previous_status, current_status, message, notified,
+			COALESCE(error, ''), created_at
+		FROM flight_watch_events
+		WHERE watch_id = $1
+		ORDER BY id
+	`
+
+	rows, err := s.db.DB.QueryContext(ctx, query, watchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flight watch events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []agents.FlightWatchEvent{}
+	for rows.Next() {
+		var event agents.FlightWatchEvent
+		var changes, previous, current []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.WatchID,
+			&event.FlightCode,
+			&changes,
+			&previous,
+			&current,
+			&event.Message,
+			&event.Notified,
+			&event.Error,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan flight watch event: %w", err)
+		}
+		if err := json.Unmarshal(changes, &event.Changes); err != nil {
+			return nil, fmt.Errorf("failed to parse changes: %w", err)
+		}
+		if err := json.Unmarshal(current, &event.Current); err != nil {
+			return nil, fmt.Errorf("failed to parse flight status: %w", err)
+		}
+		if previous != nil {
+			event.Previous = &agents.FlightStatus{}
+			if err := json.Unmarshal(previous, event.Previous); err != nil {
+				return nil, fmt.Errorf("failed to parse flight status: %w", err)
+			}
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+// queryWatches runs a query returning flight watch rows
+func (s *PostgresFlightWatchStore) queryWatches(ctx context.Context, query string, args ...interface{}) ([]agents.FlightWatch, error) {
+	rows, err := s.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query flight watches: %w", err)
+	}
+	defer rows.Close()
+
+	watches := []agents.FlightWatch{}
+	for rows.Next() {
+		watch, err := scanFlightWatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		watches = append(watches, watch)
+	}
+	return watches, rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanFlightWatch reads a row selected with flightWatchColumns
+func scanFlightWatch(row rowScanner) (agents.FlightWatch, error) {
+	var watch agents.FlightWatch
+	var lastStatus []byte
+	var lastChecked sql.NullTime
+	err := row.Scan(
+		&watch.ID,
+		&watch.UserID,
+		&watch.FlightCode,
+		&watch.FlightDate,
+		&watch.Channel,
+		&watch.Target,
+		&watch.Active,
+		&lastStatus,
+		&lastChecked,
+		&watch.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return watch, err
+	}
+	if err != nil {
+		return watch, fmt.Errorf("failed to scan flight watch: %w", err)
+	}
+
+	if lastStatus != nil {
+		watch.LastStatus = &agents.FlightStatus{}
+		if err := json.Unmarshal(lastStatus, watch.LastStatus); err != nil {
+			return watch, fmt.Errorf("failed to parse flight status: %w", err)
+		}
+	}
+	if lastChecked.Valid {
+		watch.LastCheckedAt = &lastChecked.Time
+	}
+	return watch, nil
+}
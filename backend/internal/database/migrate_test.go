@@ -0,0 +1,51 @@
+package database
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrations_Embedded(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, "Versions have no gaps")
+		assert.NotEmpty(t, strings.TrimSpace(migration.Up))
+		assert.NotEmpty(t, strings.TrimSpace(migration.Down))
+	}
+	assert.Equal(t, "travel_searches", migrations[0].Name)
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations(fstest.MapFS{
+		"0002_second.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"0002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"0001_first.up.sql":    {Data: []byte("CREATE TABLE a (id INT);")},
+		"0001_first.down.sql":  {Data: []byte("DROP TABLE a;")},
+		"README.md":            {Data: []byte("not a migration")},
+	})
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, Migration{Version: 1, Name: "first", Up: "CREATE TABLE a (id INT);", Down: "DROP TABLE a;"}, migrations[0])
+	assert.Equal(t, "second", migrations[1].Name)
+
+	invalid := map[string]fstest.MapFS{
+		"missing down": {"0001_first.up.sql": {Data: []byte("SELECT 1;")}},
+		"bad name":     {"first.up.sql": {Data: []byte("SELECT 1;")}},
+		"zero version": {"0000_first.up.sql": {Data: []byte("SELECT 1;")}, "0000_first.down.sql": {Data: []byte("SELECT 1;")}},
+		"duplicate version": {
+			"0001_first.up.sql": {Data: []byte("SELECT 1;")}, "0001_first.down.sql": {Data: []byte("SELECT 1;")},
+			"0001_other.up.sql": {Data: []byte("SELECT 1;")}, "0001_other.down.sql": {Data: []byte("SELECT 1;")},
+		},
+	}
+	for name, fsys := range invalid {
+		_, err := LoadMigrations(fsys)
+		assert.Error(t, err, name)
+	}
+}
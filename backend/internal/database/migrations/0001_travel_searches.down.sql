@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS travel_recommendations;
+DROP TABLE IF EXISTS travel_searches;
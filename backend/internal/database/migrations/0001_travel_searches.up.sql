@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS travel_searches (
+	id SERIAL PRIMARY KEY,
+	user_id VARCHAR(255),
+	destination VARCHAR(255) NOT NULL,
+	start_date DATE,
+	end_date DATE,
+	budget DECIMAL(10, 2),
+	preferences JSONB,
+	results JSONB,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_travel_searches_user_id ON travel_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_travel_searches_destination ON travel_searches(destination);
+CREATE INDEX IF NOT EXISTS idx_travel_searches_created_at ON travel_searches(created_at);
+
+CREATE TABLE IF NOT EXISTS travel_recommendations (
+	id SERIAL PRIMARY KEY,
+	search_id INTEGER REFERENCES travel_searches(id) ON DELETE CASCADE,
+	recommendation_type VARCHAR(50) NOT NULL,
+	title VARCHAR(255) NOT NULL,
+	description TEXT,
+	price DECIMAL(10, 2),
+	rating DECIMAL(3, 2),
+	metadata JSONB,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recommendations_search_id ON travel_recommendations(search_id);
+CREATE INDEX IF NOT EXISTS idx_recommendations_type ON travel_recommendations(recommendation_type);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS visa_rules (
+	id SERIAL PRIMARY KEY,
+	nationality VARCHAR(64) NOT NULL,
+	destination VARCHAR(64) NOT NULL,
+	purpose VARCHAR(32) NOT NULL DEFAULT 'tourism',
+	requirement JSONB NOT NULL,
+	effective_from DATE NOT NULL,
+	effective_to DATE,
+	source_url TEXT,
+	last_verified TIMESTAMP,
+	source VARCHAR(20) NOT NULL,
+	verified BOOLEAN NOT NULL DEFAULT FALSE,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (nationality, destination, purpose, effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_visa_rules_route ON visa_rules(nationality, destination, purpose);
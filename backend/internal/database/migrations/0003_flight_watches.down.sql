@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS flight_watch_events;
+DROP TABLE IF EXISTS flight_watches;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS flight_watches (
+	id SERIAL PRIMARY KEY,
+	user_id VARCHAR(255),
+	flight_code VARCHAR(16) NOT NULL,
+	flight_date DATE NOT NULL,
+	channel VARCHAR(20) NOT NULL,
+	target TEXT NOT NULL,
+	active BOOLEAN NOT NULL DEFAULT TRUE,
+	last_status JSONB,
+	last_checked_at TIMESTAMP,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_flight_watches_user_id ON flight_watches(user_id);
+CREATE INDEX IF NOT EXISTS idx_flight_watches_active ON flight_watches(active);
+
+CREATE TABLE IF NOT EXISTS flight_watch_events (
+	id SERIAL PRIMARY KEY,
+	watch_id INTEGER REFERENCES flight_watches(id) ON DELETE CASCADE,
+	flight_code VARCHAR(16) NOT NULL,
+	changes JSONB NOT NULL,
+	previous_status JSONB,
+	current_status JSONB NOT NULL,
+	message TEXT NOT NULL,
+	notified BOOLEAN NOT NULL DEFAULT FALSE,
+	error TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_flight_watch_events_watch_id ON flight_watch_events(watch_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS trip_expenses;
+DROP TABLE IF EXISTS trip_budgets;
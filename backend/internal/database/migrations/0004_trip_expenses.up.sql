@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS trip_budgets (
+	trip_id BIGINT PRIMARY KEY,
+	total DECIMAL(12, 2) NOT NULL,
+	breakdown JSONB NOT NULL,
+	days INTEGER,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trip_expenses (
+	id SERIAL PRIMARY KEY,
+	trip_id BIGINT NOT NULL,
+	category VARCHAR(20) NOT NULL,
+	amount DECIMAL(12, 2) NOT NULL,
+	currency VARCHAR(3) NOT NULL,
+	amount_thb DECIMAL(12, 2) NOT NULL,
+	expense_date DATE NOT NULL,
+	day INTEGER,
+	description TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_expenses_trip_id ON trip_expenses(trip_id, expense_date);
@@ -0,0 +1,3 @@
+ALTER TABLE trip_expenses DROP COLUMN IF EXISTS split;
+ALTER TABLE trip_expenses DROP COLUMN IF EXISTS paid_by;
+DROP TABLE IF EXISTS trip_members;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS trip_members (
+	id SERIAL PRIMARY KEY,
+	trip_id BIGINT NOT NULL,
+	name VARCHAR(100) NOT NULL,
+	email VARCHAR(255),
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	UNIQUE (trip_id, name)
+);
+
+-- Databases created before group trips have trip_expenses without these columns
+ALTER TABLE trip_expenses ADD COLUMN IF NOT EXISTS paid_by INTEGER;
+ALTER TABLE trip_expenses ADD COLUMN IF NOT EXISTS split JSONB;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS trip_activities;
+DROP TABLE IF EXISTS trip_days;
+DROP TABLE IF EXISTS trips;
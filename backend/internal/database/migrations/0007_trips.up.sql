@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS trips (
+	id SERIAL PRIMARY KEY,
+	user_id VARCHAR(36) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+	title VARCHAR(255) NOT NULL,
+	destination VARCHAR(255) NOT NULL,
+	start_date DATE,
+	duration_days INTEGER NOT NULL DEFAULT 0,
+	budget DECIMAL(12, 2) NOT NULL DEFAULT 0,
+	summary TEXT,
+	created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_trips_user_id ON trips(user_id, updated_at);
+
+CREATE TABLE IF NOT EXISTS trip_days (
+	id SERIAL PRIMARY KEY,
+	trip_id INTEGER NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+	day INTEGER NOT NULL,
+	budget DECIMAL(12, 2),
+	UNIQUE (trip_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS trip_activities (
+	id SERIAL PRIMARY KEY,
+	trip_day_id INTEGER NOT NULL REFERENCES trip_days(id) ON DELETE CASCADE,
+	position INTEGER NOT NULL,
+	description TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_trip_activities_day_id ON trip_activities(trip_day_id, position);
@@ -0,0 +1,3 @@
+ALTER TABLE trip_members DROP CONSTRAINT IF EXISTS trip_members_trip_id_fkey;
+ALTER TABLE trip_expenses DROP CONSTRAINT IF EXISTS trip_expenses_trip_id_fkey;
+ALTER TABLE trip_budgets DROP CONSTRAINT IF EXISTS trip_budgets_trip_id_fkey;
@@ -0,0 +1,11 @@
+-- Ledgers of trips that no longer exist cannot be reached through the API
+DELETE FROM trip_budgets WHERE trip_id NOT IN (SELECT id FROM trips);
+DELETE FROM trip_expenses WHERE trip_id NOT IN (SELECT id FROM trips);
+DELETE FROM trip_members WHERE trip_id NOT IN (SELECT id FROM trips);
+
+ALTER TABLE trip_budgets
+	ADD CONSTRAINT trip_budgets_trip_id_fkey FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE;
+ALTER TABLE trip_expenses
+	ADD CONSTRAINT trip_expenses_trip_id_fkey FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE;
+ALTER TABLE trip_members
+	ADD CONSTRAINT trip_members_trip_id_fkey FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE;
@@ -0,0 +1,2 @@
+UPDATE visa_rules SET verified = TRUE, updated_at = CURRENT_TIMESTAMP
+WHERE source = 'seed' AND last_verified IS NULL AND NOT verified;
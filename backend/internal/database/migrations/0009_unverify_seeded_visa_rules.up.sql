@@ -0,0 +1,3 @@
+-- Seeded rules were stored as verified without a verification date
+UPDATE visa_rules SET verified = FALSE, updated_at = CURRENT_TIMESTAMP
+WHERE source = 'seed' AND last_verified IS NULL AND verified;
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -50,56 +51,21 @@ func NewPostgresDB(cfg *config.Config) (*PostgresDB, error) {
 
 	log.Println("Successfully connected to PostgreSQL database")
 
-	// Initialize schema
-	if err := initSchema(db); err != nil {
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
 	return &PostgresDB{DB: db}, nil
 }
 
-// initSchema creates the necessary database tables
-func initSchema(db *sql.DB) error {
-	schema := `
-	CREATE TABLE IF NOT EXISTS travel_searches (
-		id SERIAL PRIMARY KEY,
-		user_id VARCHAR(255),
-		destination VARCHAR(255) NOT NULL,
-		start_date DATE,
-		end_date DATE,
-		budget DECIMAL(10, 2),
-		preferences JSONB,
-		results JSONB,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-		updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_travel_searches_user_id ON travel_searches(user_id);
-	CREATE INDEX IF NOT EXISTS idx_travel_searches_destination ON travel_searches(destination);
-	CREATE INDEX IF NOT EXISTS idx_travel_searches_created_at ON travel_searches(created_at);
-
-	CREATE TABLE IF NOT EXISTS travel_recommendations (
-		id SERIAL PRIMARY KEY,
-		search_id INTEGER REFERENCES travel_searches(id) ON DELETE CASCADE,
-		recommendation_type VARCHAR(50) NOT NULL,
-		title VARCHAR(255) NOT NULL,
-		description TEXT,
-		price DECIMAL(10, 2),
-		rating DECIMAL(3, 2),
-		metadata JSONB,
-		created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
-	);
-
-	CREATE INDEX IF NOT EXISTS idx_recommendations_search_id ON travel_recommendations(search_id);
-	CREATE INDEX IF NOT EXISTS idx_recommendations_type ON travel_recommendations(recommendation_type);
-	`
-
-	_, err := db.Exec(schema)
+// Migrate applies the pending schema migrations
+func (db *PostgresDB) Migrate(ctx context.Context) error {
+	migrations, err := Migrations()
+	if err != nil {
+		return err
+	}
+	applied, err := NewMigrator(db.DB, migrations).Up(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
+		return err
 	}
 
-	log.Println("Database schema initialized successfully")
+	log.Printf("Database schema is up to date (%d migrations applied)", len(applied))
 	return nil
 }
 
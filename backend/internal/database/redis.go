@@ -2,6 +2,7 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,6 +11,9 @@ import (
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
 )
 
+// ErrCacheMiss is returned by Get when the key does not exist
+var ErrCacheMiss = errors.New("key does not exist")
+
 // RedisCache wraps the Redis client
 type RedisCache struct {
 	Client *redis.Client
@@ -54,7 +58,7 @@ func (r *RedisCache) Set(key string, value interface{}, expiration time.Duration
 func (r *RedisCache) Get(key string) (string, error) {
 	val, err := r.Client.Get(r.ctx, key).Result()
 	if err == redis.Nil {
-		return "", fmt.Errorf("key does not exist")
+		return "", ErrCacheMiss
 	} else if err != nil {
 		return "", err
 	}
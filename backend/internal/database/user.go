@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+)
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// PostgresUserStore keeps user accounts in the users table
+type PostgresUserStore struct {
+	db *PostgresDB
+}
+
+// NewPostgresUserStore creates a Postgres-backed user store
+func NewPostgresUserStore(db *PostgresDB) *PostgresUserStore {
+	return &PostgresUserStore{db: db}
+}
+
+// CreateUser implements auth.UserStore
+func (s *PostgresUserStore) CreateUser(ctx context.Context, user *auth.User) error {
+	query := `
+		INSERT INTO users (id, email, name, password_hash)
+		VALUES ($1, $2, NULLIF($3, ''), $4)
+		RETURNING created_at
+	`
+
+	err := s.db.DB.QueryRowContext(ctx, query, user.ID, user.Email, user.Name, user.PasswordHash).Scan(&user.CreatedAt)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return auth.ErrEmailTaken
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+	return nil
+}
+
+// GetUser implements auth.UserStore
+func (s *PostgresUserStore) GetUser(ctx context.Context, id string) (*auth.User, error) {
+	return s.getUser(ctx, "id", id)
+}
+
+// GetUserByEmail implements auth.UserStore
+func (s *PostgresUserStore) GetUserByEmail(ctx context.Context, email string) (*auth.User, error) {
+	return s.getUser(ctx, "email", email)
+}
+
+// getUser looks a user up by one of its unique columns
+func (s *PostgresUserStore) getUser(ctx context.Context, column, value string) (*auth.User, error) {
+	var user auth.User
+	err := s.db.DB.QueryRowContext(ctx,
+		"SELECT id, email, COALESCE(name, ''), password_hash, created_at FROM users WHERE "+column+" = $1", value,
+	).Scan(&user.ID, &user.Email, &user.Name, &user.PasswordHash, &user.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user: %w", err)
+	}
+	return &user, nil
+}
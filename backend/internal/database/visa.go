@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+)
+
+// PostgresVisaStore keeps visa rules in the visa_rules table
+type PostgresVisaStore struct {
+	db *PostgresDB
+}
+
+// NewPostgresVisaStore creates a Postgres-backed visa rules store
+func NewPostgresVisaStore(db *PostgresDB) *PostgresVisaStore {
+	return &PostgresVisaStore{db: db}
+}
+
+// FindRule returns the verified (or else newest) rule for a route that is active on the given day
+func (s *PostgresVisaStore) FindRule(ctx context.Context, nationality, destination, purpose string, on time.Time) (*agents.VisaRule, error) {
+	query := `
+		SELECT id, nationality, destination, purpose, requirement, effective_from, effective_to,
+			COALESCE(source_url, ''), last_verified, source, verified
+		FROM visa_rules
+		WHERE nationality = $1 AND destination = $2 AND purpose = $3
+			AND effective_from <= $4 AND (effective_to IS NULL OR effective_to >= $4)
+		ORDER BY verified DESC, effective_from DESC
+		LIMIT 1
+	`
+
+	var rule agents.VisaRule
+	var requirement []byte
+	var effectiveTo, lastVerified sql.NullTime
+	err := s.db.DB.QueryRowContext(ctx, query, nationality, destination, purpose, on.Format("2006-01-02")).Scan(
+		&rule.ID,
+		&rule.Nationality,
+		&rule.Destination,
+		&rule.Purpose,
+		&requirement,
+		&rule.EffectiveFrom,
+		&effectiveTo,
+		&rule.SourceURL,
+		&lastVerified,
+		&rule.Source,
+		&rule.Verified,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query visa rule: %w", err)
+	}
+
+	if err := json.Unmarshal(requirement, &rule.Requirement); err != nil {
+		return nil, fmt.Errorf("failed to parse visa requirement: %w", err)
+	}
+	if effectiveTo.Valid {
+		rule.EffectiveTo = &effectiveTo.Time
+	}
+	if lastVerified.Valid {
+		rule.LastVerified = &lastVerified.Time
+	}
+	return &rule, nil
+}
+
+// SaveRule upserts a rule by route and effective-from date; unverified rules never replace verified ones
+func (s *PostgresVisaStore) SaveRule(ctx context.Context, rule *agents.VisaRule) error {
+	requirement, err := json.Marshal(rule.Requirement)
+	if err != nil {
+		return fmt.Errorf("failed to marshal visa requirement: %w", err)
+	}
+
+	query := `
+		INSERT INTO visa_rules (nationality, destination, purpose, requirement, effective_from, effective_to,
+			source_url, last_verified, source, verified)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10)
+		ON CONFLICT (nationality, destination, purpose, effective_from) DO UPDATE SET
+			requirement = EXCLUDED.requirement,
+			effective_to = EXCLUDED.effective_to,
+			source_url = EXCLUDED.source_url,
+			last_verified = EXCLUDED.last_verified,
+			source = EXCLUDED.source,
+			verified = EXCLUDED.verified,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE NOT visa_rules.verified OR EXCLUDED.verified
+		RETURNING id
+	`
+
+	err = s.db.DB.QueryRowContext(ctx, query,
+		rule.Nationality,
+		rule.Destination,
+		rule.Purpose,
+		requirement,
+		rule.EffectiveFrom,
+		rule.EffectiveTo,
+		rule.SourceURL,
+		rule.LastVerified,
+		rule.Source,
+		rule.Verified,
+	).Scan(&rule.ID)
+	if err == sql.ErrNoRows {
+		// A verified rule already covers this route and date
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save visa rule: %w", err)
+	}
+	return nil
+}
+
+// SeedIfEmpty loads the given rules when the table has no rules yet
+func (s *PostgresVisaStore) SeedIfEmpty(ctx context.Context, rules []agents.VisaRule) error {
+	var count int
+	if err := s.db.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM visa_rules").Scan(&count); err != nil {
+		return fmt.Errorf("failed to count visa rules: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	imported, err := agents.ImportVisaRules(ctx, s, rules)
+	if err != nil {
+		return err
+	}
+	log.Printf("Seeded %d visa rules", imported)
+	return nil
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// AuthHandler handles registration, login and token refresh requests
+type AuthHandler struct {
+	users  auth.UserStore
+	tokens *auth.TokenManager
+}
+
+// NewAuthHandler creates a new auth handler instance
+func NewAuthHandler(users auth.UserStore, tokens *auth.TokenManager) *AuthHandler {
+	return &AuthHandler{
+		users:  users,
+		tokens: tokens,
+	}
+}
+
+// Register handles POST /api/v1/auth/register requests, returning the new user and their tokens
+func (h *AuthHandler) Register(c *fiber.Ctx) error {
+	var req models.RegisterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	user := auth.User{
+		ID:    uuid.NewString(),
+		Email: auth.NormalizeEmail(req.Email),
+		Name:  strings.TrimSpace(req.Name),
+	}
+	if err := validateRegistration(user, req.Password); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		log.Printf("Failed to hash password: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Internal error",
+			Message: "Failed to create account",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	user.PasswordHash = hash
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.users.CreateUser(ctx, &user); err != nil {
+		if errors.Is(err, auth.ErrEmailTaken) {
+			return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+				Error:   "Conflict",
+				Message: err.Error(),
+				Code:    fiber.StatusConflict,
+			})
+		}
+		log.Printf("Failed to create user: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to create account",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return h.respondWithTokens(c, fiber.StatusCreated, &user)
+}
+
+// Login handles POST /api/v1/auth/login requests
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	var req models.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := h.users.GetUserByEmail(ctx, auth.NormalizeEmail(req.Email))
+	if err != nil {
+		log.Printf("Failed to look up user: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to log in",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	// Unknown emails and wrong passwords get the same answer after the same bcrypt work. Register
+	// still answers 409 for a taken email, so this only keeps logins from being a faster probe.
+	if !auth.CheckUserPassword(user, req.Password) {
+		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Invalid email or password",
+			Code:    fiber.StatusUnauthorized,
+		})
+	}
+
+	return h.respondWithTokens(c, fiber.StatusOK, user)
+}
+
+// Refresh handles POST /api/v1/auth/refresh requests, trading a refresh token for a new token pair
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req models.RefreshRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	claims, err := h.tokens.Verify(req.RefreshToken, auth.RefreshToken)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: err.Error(),
+			Code:    fiber.StatusUnauthorized,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := h.users.GetUser(ctx, claims.Subject)
+	if err != nil {
+		log.Printf("Failed to look up user %s: %v", claims.Subject, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to refresh token",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	if user == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: auth.ErrInvalidToken.Error(),
+			Code:    fiber.StatusUnauthorized,
+		})
+	}
+
+	return h.respondWithTokens(c, fiber.StatusOK, user)
+}
+
+// Me handles GET /api/v1/auth/me requests, returning the signed-in user
+func (h *AuthHandler) Me(c *fiber.Ctx) error {
+	current := auth.CurrentUser(c)
+	if current == nil {
+		return unauthorized(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	user, err := h.users.GetUser(ctx, current.ID)
+	if err != nil {
+		log.Printf("Failed to look up user %s: %v", current.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve user",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	if user == nil {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: "user not found",
+			Code:    fiber.StatusNotFound,
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// respondWithTokens issues tokens for a user and sends them with the user
+func (h *AuthHandler) respondWithTokens(c *fiber.Ctx, status int, user *auth.User) error {
+	tokens, err := h.tokens.Issue(user)
+	if err != nil {
+		log.Printf("Failed to issue tokens: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Internal error",
+			Message: "Failed to issue tokens",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"user":   user,
+		"tokens": tokens,
+	})
+}
+
+// validateRegistration checks the email and password of a new account
+func validateRegistration(user auth.User, password string) error {
+	at := strings.Index(user.Email, "@")
+	if at < 1 || at == len(user.Email)-1 || strings.ContainsAny(user.Email, " \t") {
+		return fmt.Errorf("a valid email is required")
+	}
+	if len(password) < auth.MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", auth.MinPasswordLength)
+	}
+	// bcrypt only hashes the first 72 bytes
+	if len(password) > 72 {
+		return fmt.Errorf("password must be at most 72 bytes")
+	}
+	return nil
+}
+
+// unauthorized answers requests that reach a user-scoped handler without a signed-in user
+func unauthorized(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusUnauthorized).JSON(models.ErrorResponse{
+		Error:   "Unauthorized",
+		Message: "Sign in to access this resource",
+		Code:    fiber.StatusUnauthorized,
+	})
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// accessToken signs an access token for a user ID
+func accessToken(t *testing.T, tokens *auth.TokenManager, userID string) string {
+	pair, err := tokens.Issue(&auth.User{ID: userID, Email: userID + "@example.com"})
+	require.NoError(t, err)
+	return pair.AccessToken
+}
+
+func TestAuthHandler(t *testing.T) {
+	tokens := auth.NewTokenManager("test-secret", time.Hour, 0)
+	handler := NewAuthHandler(auth.NewMemoryUserStore(), tokens)
+	repositories := storage.NewMemoryRepositories()
+	travelHandler := NewTravelHandler(repositories.Searches, repositories.Recommendations, nil, nil, nil, nil)
+
+	app := fiber.New()
+	app.Post("/api/v1/auth/register", handler.Register)
+	app.Post("/api/v1/auth/login", handler.Login)
+	app.Post("/api/v1/auth/refresh", handler.Refresh)
+	app.Get("/api/v1/auth/me", auth.Middleware(tokens), handler.Me)
+	app.Get("/api/v1/travel/history", auth.Middleware(tokens), travelHandler.GetSearchHistory)
+
+	do := func(method, target, token string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		// Hashing passwords at the default bcrypt cost can take longer than app.Test's one second
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	type authResponse struct {
+		User   auth.User      `json:"user"`
+		Tokens auth.TokenPair `json:"tokens"`
+	}
+
+	status, body := do("POST", "/api/v1/auth/register", "", models.RegisterRequest{
+		Email: " Somchai@Example.com ", Password: "correct horse", Name: "Somchai",
+	})
+	require.Equal(t, fiber.StatusCreated, status, string(body))
+	assert.NotContains(t, string(body), "password", "Password hashes are never returned")
+
+	var registered authResponse
+	require.NoError(t, json.Unmarshal(body, &registered))
+	assert.Equal(t, "somchai@example.com", registered.User.Email)
+	assert.NotEmpty(t, registered.User.ID)
+	assert.Equal(t, "Bearer", registered.Tokens.TokenType)
+
+	t.Run("Rejects duplicate and invalid registrations", func(t *testing.T) {
+		status, _ := do("POST", "/api/v1/auth/register", "", models.RegisterRequest{Email: "SOMCHAI@example.com", Password: "another password"})
+		assert.Equal(t, fiber.StatusConflict, status)
+
+		for name, request := range map[string]models.RegisterRequest{
+			"Missing email":  {Password: "correct horse"},
+			"Bad email":      {Email: "somchai", Password: "correct horse"},
+			"Short password": {Email: "new@example.com", Password: "short"},
+		} {
+			status, _ := do("POST", "/api/v1/auth/register", "", request)
+			assert.Equal(t, fiber.StatusBadRequest, status, name)
+		}
+	})
+
+	t.Run("Logs in", func(t *testing.T) {
+		status, body := do("POST", "/api/v1/auth/login", "", models.LoginRequest{Email: "somchai@example.com", Password: "correct horse"})
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		var response authResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.Equal(t, registered.User.ID, response.User.ID)
+
+		status, body = do("GET", "/api/v1/auth/me", response.Tokens.AccessToken, nil)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Contains(t, string(body), `"name":"Somchai"`)
+
+		status, _ = do("POST", "/api/v1/auth/login", "", models.LoginRequest{Email: "somchai@example.com", Password: "wrong password"})
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+		status, _ = do("POST", "/api/v1/auth/login", "", models.LoginRequest{Email: "nobody@example.com", Password: "correct horse"})
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("Refreshes tokens", func(t *testing.T) {
+		status, body := do("POST", "/api/v1/auth/refresh", "", models.RefreshRequest{RefreshToken: registered.Tokens.RefreshToken})
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		var response authResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.NotEmpty(t, response.Tokens.AccessToken)
+
+		status, _ = do("POST", "/api/v1/auth/refresh", "", models.RefreshRequest{RefreshToken: registered.Tokens.AccessToken})
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Access tokens cannot be used to refresh")
+
+		status, _ = do("POST", "/api/v1/auth/refresh", "", models.RefreshRequest{RefreshToken: accessToken(t, tokens, "deleted-user")})
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("User resources require a signed-in user", func(t *testing.T) {
+		status, _ := do("GET", "/api/v1/auth/me", "", nil)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+
+		status, _ = do("GET", "/api/v1/travel/history?userId="+registered.User.ID, "", nil)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "A userId parameter does not authenticate")
+
+		status, _ = do("GET", "/api/v1/auth/me", registered.Tokens.RefreshToken, nil)
+		assert.Equal(t, fiber.StatusUnauthorized, status, "Refresh tokens cannot be used as access tokens")
+	})
+}
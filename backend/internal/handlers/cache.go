@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+)
+
+// CacheHandler reports cache counters for the upstream API lookups
+type CacheHandler struct {
+	sources []func() []cache.Stats
+}
+
+// NewCacheHandler creates a cache stats handler that collects counters from each source
+func NewCacheHandler(sources ...func() []cache.Stats) *CacheHandler {
+	return &CacheHandler{
+		sources: sources,
+	}
+}
+
+// GetStats handles GET /api/v1/cache/stats requests
+func (h *CacheHandler) GetStats(c *fiber.Ctx) error {
+	stats := []cache.Stats{}
+	for _, source := range h.sources {
+		for _, s := range source() {
+			// Loaders that were never configured have no name
+			if s.Name != "" {
+				stats = append(stats, s)
+			}
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"caches": stats,
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheHandler_GetStats(t *testing.T) {
+	loader := cache.NewLoader("weather", cache.NewMemoryCache(), cache.Policy{Fresh: time.Minute})
+	fetch := func(ctx context.Context) (interface{}, error) { return "sunny", nil }
+
+	var value string
+	for i := 0; i < 3; i++ {
+		require.NoError(t, loader.Load(context.Background(), "weather:tokyo", &value, fetch))
+	}
+
+	var unconfigured *cache.Loader
+	handler := NewCacheHandler(func() []cache.Stats {
+		return []cache.Stats{loader.Stats(), unconfigured.Stats()}
+	})
+
+	app := fiber.New()
+	app.Get("/api/v1/cache/stats", handler.GetStats)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/api/v1/cache/stats", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	var response struct {
+		Caches []cache.Stats `json:"caches"`
+	}
+	require.NoError(t, json.Unmarshal(body, &response))
+	require.Len(t, response.Caches, 1)
+	assert.Equal(t, "weather", response.Caches[0].Name)
+	assert.Equal(t, int64(1), response.Caches[0].Misses)
+	assert.Equal(t, int64(2), response.Caches[0].Hits)
+}
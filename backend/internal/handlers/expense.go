@@ -0,0 +1,715 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+)
+
+// ExpenseHandler handles trip expense and budget HTTP requests
+type ExpenseHandler struct {
+	store     storage.ExpenseRepository
+	converter *currency.Converter
+	trips     storage.TripRepository
+}
+
+// NewExpenseHandler creates a new expense handler instance. Expenses in other currencies are
+// converted to THB with the given rates, or the bundled rates when nil.
+func NewExpenseHandler(store storage.ExpenseRepository, rates currency.RateProvider) *ExpenseHandler {
+	if rates == nil {
+		rates = currency.DefaultRates()
+	}
+	return &ExpenseHandler{
+		store:     store,
+		converter: currency.NewConverter(rates),
+	}
+}
+
+// SetTripRepository lets budgets without a destination or length use the saved trip's
+func (h *ExpenseHandler) SetTripRepository(trips storage.TripRepository) {
+	h.trips = trips
+}
+
+// ListExpenses handles GET /api/v1/trips/:id/expenses requests, returning the expenses and their summary
+func (h *ExpenseHandler) ListExpenses(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expenses, summary, err := h.summarize(ctx, tripID)
+	if err != nil {
+		log.Printf("Failed to list expenses of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve expenses",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"expenses": expenses,
+		"summary":  summary,
+	})
+}
+
+// GetSummary handles GET /api/v1/trips/:id/expenses/summary requests
+func (h *ExpenseHandler) GetSummary(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, summary, err := h.summarize(ctx, tripID)
+	if err != nil {
+		log.Printf("Failed to summarize expenses of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve expenses",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(summary)
+}
+
+// CreateExpense handles POST /api/v1/trips/:id/expenses requests. The response carries the budget
+// alerts after the expense is added.
+func (h *ExpenseHandler) CreateExpense(c *fiber.Ctx) error {
+	return h.saveExpense(c, false)
+}
+
+// UpdateExpense handles PUT /api/v1/trips/:id/expenses/:expenseId requests
+func (h *ExpenseHandler) UpdateExpense(c *fiber.Ctx) error {
+	return h.saveExpense(c, true)
+}
+
+// DeleteExpense handles DELETE /api/v1/trips/:id/expenses/:expenseId requests
+func (h *ExpenseHandler) DeleteExpense(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	var id int64
+	if err == nil {
+		id, err = positiveParam(c, "expenseId")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	existing, err := h.store.GetExpense(ctx, tripID, id)
+	if err == nil && existing == nil {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("expense %d not found", id),
+			Code:    fiber.StatusNotFound,
+		})
+	}
+	if err == nil {
+		err = h.store.DeleteExpense(ctx, tripID, id)
+	}
+	if err != nil {
+		log.Printf("Failed to delete expense %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete expense",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListMembers handles GET /api/v1/trips/:id/members requests
+func (h *ExpenseHandler) ListMembers(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	members, err := h.store.ListMembers(ctx, tripID)
+	if err != nil {
+		log.Printf("Failed to list members of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve trip members",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(members)
+}
+
+// AddMember handles POST /api/v1/trips/:id/members requests
+func (h *ExpenseHandler) AddMember(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	var req models.TripMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	member := agents.TripMember{
+		TripID: tripID,
+		Name:   strings.TrimSpace(req.Name),
+		Email:  strings.TrimSpace(req.Email),
+	}
+	if member.Name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "name is required",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	members, err := h.store.ListMembers(ctx, tripID)
+	if err == nil {
+		for _, existing := range members {
+			if strings.EqualFold(existing.Name, member.Name) {
+				return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+					Error:   "Conflict",
+					Message: fmt.Sprintf("trip %d already has a member named %s", tripID, existing.Name),
+					Code:    fiber.StatusConflict,
+				})
+			}
+		}
+		err = h.store.AddMember(ctx, &member)
+	}
+	if err != nil {
+		log.Printf("Failed to add member to trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to add trip member",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// DeleteMember handles DELETE /api/v1/trips/:id/members/:memberId requests. Members who paid for
+// or share in an expense stay until those expenses are changed, so the settlement still adds up.
+func (h *ExpenseHandler) DeleteMember(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	var id int64
+	if err == nil {
+		id, err = positiveParam(c, "memberId")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expenses, err := h.store.ListExpenses(ctx, tripID)
+	if err != nil {
+		log.Printf("Failed to list expenses of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete trip member",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	for _, expense := range expenses {
+		if expense.SharedWith(id) {
+			return c.Status(fiber.StatusConflict).JSON(models.ErrorResponse{
+				Error:   "Conflict",
+				Message: fmt.Sprintf("member %d is part of expense %d", id, expense.ID),
+				Code:    fiber.StatusConflict,
+			})
+		}
+	}
+
+	if err := h.store.DeleteMember(ctx, tripID, id); err != nil {
+		log.Printf("Failed to delete member %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete trip member",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// GetSettlement handles GET /api/v1/trips/:id/settlement requests, returning each member's balance
+// and the fewest transfers that settle the group
+func (h *ExpenseHandler) GetSettlement(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	members, err := h.store.ListMembers(ctx, tripID)
+	var expenses []agents.Expense
+	if err == nil {
+		expenses, err = h.store.ListExpenses(ctx, tripID)
+	}
+	var budget *agents.TripBudget
+	if err == nil {
+		budget, err = h.store.GetBudget(ctx, tripID)
+	}
+	if err != nil {
+		log.Printf("Failed to load trip %d for settlement: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve trip expenses",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	settlement, err := agents.SettleGroup(tripID, members, budget, expenses)
+	if err != nil {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(models.ErrorResponse{
+			Error:   "Invalid expenses",
+			Message: err.Error(),
+			Code:    fiber.StatusUnprocessableEntity,
+		})
+	}
+
+	return c.JSON(settlement)
+}
+
+// GetBudget handles GET /api/v1/trips/:id/budget requests
+func (h *ExpenseHandler) GetBudget(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	budget, err := h.store.GetBudget(ctx, tripID)
+	if err != nil {
+		log.Printf("Failed to get budget of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve trip budget",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	if budget == nil {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("trip %d has no budget", tripID),
+			Code:    fiber.StatusNotFound,
+		})
+	}
+
+	return c.JSON(budget)
+}
+
+// SetBudget handles PUT /api/v1/trips/:id/budget requests
+func (h *ExpenseHandler) SetBudget(c *fiber.Ctx) error {
+	tripID, err := positiveParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	var req models.TripBudgetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	budget, err := h.parseBudget(ctx, tripID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	if err := h.store.SetBudget(ctx, &budget); err != nil {
+		log.Printf("Failed to set budget of trip %d: %v", tripID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to save trip budget",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(budget)
+}
+
+// saveExpense validates and stores a new or edited expense
+func (h *ExpenseHandler) saveExpense(c *fiber.Ctx, update bool) error {
+	tripID, err := positiveParam(c, "id")
+	var id int64
+	if err == nil && update {
+		id, err = positiveParam(c, "expenseId")
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	var req models.ExpenseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	expense, err := h.parseExpense(ctx, tripID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	status := fiber.StatusCreated
+	if update {
+		status = fiber.StatusOK
+		existing, err := h.store.GetExpense(ctx, tripID, id)
+		if err == nil && existing == nil {
+			return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+				Error:   "Not found",
+				Message: fmt.Sprintf("expense %d not found", id),
+				Code:    fiber.StatusNotFound,
+			})
+		}
+		if err == nil {
+			expense.ID = id
+			err = h.store.UpdateExpense(ctx, &expense)
+		}
+		if err != nil {
+			log.Printf("Failed to update expense %d: %v", id, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+				Error:   "Database error",
+				Message: "Failed to update expense",
+				Code:    fiber.StatusInternalServerError,
+			})
+		}
+	} else if err := h.store.CreateExpense(ctx, &expense); err != nil {
+		log.Printf("Failed to create expense: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to create expense",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	// The expense is saved; a failed summary only leaves out the alerts
+	alerts := []agents.BudgetAlert{}
+	if _, summary, err := h.summarize(ctx, tripID); err != nil {
+		log.Printf("Failed to summarize expenses of trip %d: %v", tripID, err)
+	} else {
+		alerts = summary.Alerts
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"expense": expense,
+		"alerts":  alerts,
+	})
+}
+
+// summarize loads a trip's expenses and budget and checks one against the other
+func (h *ExpenseHandler) summarize(ctx context.Context, tripID int64) ([]agents.Expense, agents.ExpenseSummary, error) {
+	expenses, err := h.store.ListExpenses(ctx, tripID)
+	if err != nil {
+		return nil, agents.ExpenseSummary{}, err
+	}
+	budget, err := h.store.GetBudget(ctx, tripID)
+	if err != nil {
+		return nil, agents.ExpenseSummary{}, err
+	}
+	return expenses, agents.SummarizeExpenses(tripID, budget, expenses), nil
+}
+
+// parseExpense validates an expense request and converts its amount to THB
+func (h *ExpenseHandler) parseExpense(ctx context.Context, tripID int64, req models.ExpenseRequest) (agents.Expense, error) {
+	expense := agents.Expense{
+		TripID:      tripID,
+		Category:    strings.ToLower(strings.TrimSpace(req.Category)),
+		Amount:      req.Amount,
+		Day:         req.Day,
+		Description: strings.TrimSpace(req.Description),
+	}
+
+	if !agents.IsExpenseCategory(expense.Category) {
+		return expense, fmt.Errorf("category must be one of %s", strings.Join(agents.ExpenseCategories, ", "))
+	}
+	if req.Amount <= 0 {
+		return expense, fmt.Errorf("amount must be positive")
+	}
+	if req.Day < 0 {
+		return expense, fmt.Errorf("day must not be negative")
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return expense, fmt.Errorf("date must be YYYY-MM-DD")
+	}
+	expense.Date = date
+
+	expense.Currency, expense.AmountTHB, err = h.toBase(ctx, req.Amount, req.Currency)
+	if err != nil {
+		return expense, err
+	}
+
+	if req.PaidBy == 0 {
+		if req.Split != nil {
+			return expense, fmt.Errorf("split expenses need paid_by")
+		}
+		return expense, nil
+	}
+	return expense, h.parseSplit(ctx, &expense, req)
+}
+
+// parseSplit checks who paid for a shared expense and how it is split against the trip's members
+func (h *ExpenseHandler) parseSplit(ctx context.Context, expense *agents.Expense, req models.ExpenseRequest) error {
+	members, err := h.store.ListMembers(ctx, expense.TripID)
+	if err != nil {
+		return err
+	}
+	if findMember(members, req.PaidBy) == nil {
+		return fmt.Errorf("member %d is not part of this trip", req.PaidBy)
+	}
+	expense.PaidBy = req.PaidBy
+
+	split := agents.ExpenseSplit{Mode: agents.SplitEqual}
+	if req.Split != nil {
+		if mode := strings.ToLower(strings.TrimSpace(req.Split.Mode)); mode != "" {
+			split.Mode = mode
+		}
+		for _, share := range req.Split.Shares {
+			split.Shares = append(split.Shares, agents.SplitShare{
+				MemberID: share.MemberID,
+				Amount:   share.Amount,
+				Percent:  share.Percent,
+			})
+		}
+	}
+	// An even split among everyone means everyone on the trip now; members who join later do not
+	// take on earlier costs
+	if split.Mode == agents.SplitEqual && len(split.Shares) == 0 {
+		for _, member := range members {
+			split.Shares = append(split.Shares, agents.SplitShare{MemberID: member.ID})
+		}
+	}
+	expense.Split = &split
+
+	_, err = expense.MemberShares(members)
+	return err
+}
+
+// parseBudget validates a budget request and converts it to THB
+func (h *ExpenseHandler) parseBudget(ctx context.Context, tripID int64, req models.TripBudgetRequest) (agents.TripBudget, error) {
+	budget := agents.TripBudget{TripID: tripID, Days: req.Days}
+	if req.Days < 0 {
+		return budget, fmt.Errorf("days must not be negative")
+	}
+
+	convert := func(amount float64) (float64, error) {
+		_, converted, err := h.toBase(ctx, amount, req.Currency)
+		return converted, err
+	}
+
+	if req.Breakdown == nil {
+		if req.Total <= 0 {
+			return budget, fmt.Errorf("total must be positive")
+		}
+		total, err := convert(req.Total)
+		if err != nil {
+			return budget, err
+		}
+		budget.Total = total
+		budget.Plan = agents.EstimateTripBudget(h.budgetRequest(ctx, tripID, req, total)).BudgetPlan()
+		return budget, nil
+	}
+
+	amounts := []float64{req.Breakdown.Flight, req.Breakdown.Hotel, req.Breakdown.Food, req.Breakdown.Transport, req.Breakdown.Misc}
+	converted := make([]float64, len(amounts))
+	sum := 0.0
+	for i, amount := range amounts {
+		if amount < 0 {
+			return budget, fmt.Errorf("breakdown amounts must not be negative")
+		}
+		value, err := convert(amount)
+		if err != nil {
+			return budget, err
+		}
+		converted[i] = value
+		sum += value
+	}
+	budget.Plan = agents.BudgetPlan{
+		Flight:    int(converted[0]),
+		Hotel:     int(converted[1]),
+		Food:      int(converted[2]),
+		Transport: int(converted[3]),
+		Misc:      int(converted[4]),
+	}
+
+	budget.Total = sum
+	if req.Total > 0 {
+		total, err := convert(req.Total)
+		if err != nil {
+			return budget, err
+		}
+		budget.Total = total
+	}
+	if budget.Total <= 0 {
+		return budget, fmt.Errorf("total must be positive")
+	}
+	return budget, nil
+}
+
+// budgetRequest describes the trip for the budget estimate. The destination and length default to
+// the saved trip's and the number of travelers to the trip's members.
+func (h *ExpenseHandler) budgetRequest(ctx context.Context, tripID int64, req models.TripBudgetRequest, total float64) agents.BudgetRequest {
+	estimate := agents.BudgetRequest{
+		Destination: req.Destination,
+		Origin:      req.Origin,
+		Days:        req.Days,
+		Travelers:   req.Travelers,
+		Style:       req.Style,
+		Total:       total,
+	}
+
+	if h.trips != nil && (estimate.Destination == "" || estimate.Days == 0) {
+		trip, err := h.trips.GetTrip(ctx, tripID)
+		if err != nil {
+			log.Printf("Failed to get trip %d for its budget: %v", tripID, err)
+		} else if trip != nil {
+			if estimate.Destination == "" {
+				estimate.Destination = trip.Destination
+			}
+			if estimate.Days == 0 {
+				estimate.Days = trip.Duration
+			}
+		}
+	}
+
+	if estimate.Travelers == 0 {
+		members, err := h.store.ListMembers(ctx, tripID)
+		if err != nil {
+			log.Printf("Failed to list members of trip %d for its budget: %v", tripID, err)
+		}
+		estimate.Travelers = len(members)
+	}
+	return estimate
+}
+
+// toBase converts an amount in the given currency (THB when empty) to THB
+func (h *ExpenseHandler) toBase(ctx context.Context, amount float64, code string) (string, float64, error) {
+	if code == "" {
+		code = currency.Base
+	}
+	normalized, ok := currency.Normalize(code)
+	if !ok || !h.converter.Supports(ctx, normalized) {
+		return "", 0, fmt.Errorf("currency %q is not supported", code)
+	}
+
+	converted, err := h.converter.Convert(ctx, currency.Money{Amount: amount, Currency: normalized}, currency.Base)
+	if err != nil {
+		return "", 0, err
+	}
+	return normalized, math.Round(converted.Amount*100) / 100, nil
+}
+
+// findMember returns the member with the given ID, or nil
+func findMember(members []agents.TripMember, id int64) *agents.TripMember {
+	for i := range members {
+		if members[i].ID == id {
+			return &members[i]
+		}
+	}
+	return nil
+}
+
+// positiveParam reads a positive numeric route parameter
+func positiveParam(c *fiber.Ctx, name string) (int64, error) {
+	value, err := c.ParamsInt(name)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("%s must be a positive number", name)
+	}
+	return int64(value), nil
+}
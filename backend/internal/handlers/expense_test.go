@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpenseHandler(t *testing.T) {
+	handler := NewExpenseHandler(storage.NewMemoryExpenseRepository(), currency.NewRates("THB", map[string]float64{"USD": 0.025}))
+
+	app := fiber.New()
+	app.Put("/api/v1/trips/:id/budget", handler.SetBudget)
+	app.Get("/api/v1/trips/:id/budget", handler.GetBudget)
+	app.Get("/api/v1/trips/:id/expenses", handler.ListExpenses)
+	app.Post("/api/v1/trips/:id/expenses", handler.CreateExpense)
+	app.Get("/api/v1/trips/:id/expenses/summary", handler.GetSummary)
+	app.Put("/api/v1/trips/:id/expenses/:expenseId", handler.UpdateExpense)
+	app.Delete("/api/v1/trips/:id/expenses/:expenseId", handler.DeleteExpense)
+
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	status, _ := do("GET", "/api/v1/trips/7/budget", nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+
+	status, body := do("PUT", "/api/v1/trips/7/budget", models.TripBudgetRequest{
+		Days:      5,
+		Breakdown: &models.BudgetBreakdown{Flight: 4500, Hotel: 2500, Food: 1500, Transport: 1000, Misc: 500},
+	})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	var budget agents.TripBudget
+	require.NoError(t, json.Unmarshal(body, &budget))
+	assert.Equal(t, 10000.0, budget.Total, "The total defaults to the breakdown's sum")
+	assert.Equal(t, 1500, budget.Plan.Food)
+
+	t.Run("Splits a total with the budget estimate", func(t *testing.T) {
+		status, body := do("PUT", "/api/v1/trips/9/budget", models.TripBudgetRequest{
+			Total: 80000, Days: 5, Destination: "Tokyo", Travelers: 2, Style: "luxury",
+		})
+		require.Equal(t, fiber.StatusOK, status, string(body))
+		var budget agents.TripBudget
+		require.NoError(t, json.Unmarshal(body, &budget))
+
+		estimate := agents.EstimateTripBudget(agents.BudgetRequest{
+			Destination: "Tokyo", Days: 5, Travelers: 2, Style: "luxury", Total: 80000,
+		})
+		assert.Equal(t, estimate.BudgetPlan(), budget.Plan)
+		assert.NotEqual(t, agents.EstimateBudget(80000), budget.Plan, "The split depends on the trip")
+	})
+
+	t.Run("Adds expenses in other currencies and reports alerts", func(t *testing.T) {
+		status, body := do("POST", "/api/v1/trips/7/expenses", models.ExpenseRequest{
+			Category: "Food", Amount: 35, Currency: "usd", Date: "2025-05-01", Day: 1, Description: "Dinner",
+		})
+		require.Equal(t, fiber.StatusCreated, status, string(body))
+
+		var response struct {
+			Expense agents.Expense       `json:"expense"`
+			Alerts  []agents.BudgetAlert `json:"alerts"`
+		}
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.Equal(t, agents.ExpenseFood, response.Expense.Category)
+		assert.Equal(t, "USD", response.Expense.Currency)
+		assert.Equal(t, 1400.0, response.Expense.AmountTHB)
+		require.Len(t, response.Alerts, 1)
+		assert.Equal(t, agents.AlertWarning, response.Alerts[0].Level)
+		assert.Equal(t, agents.ExpenseFood, response.Alerts[0].Category)
+	})
+
+	t.Run("Lists expenses with the summary", func(t *testing.T) {
+		status, body := do("GET", "/api/v1/trips/7/expenses", nil)
+		require.Equal(t, fiber.StatusOK, status)
+
+		var response struct {
+			Expenses []agents.Expense      `json:"expenses"`
+			Summary  agents.ExpenseSummary `json:"summary"`
+		}
+		require.NoError(t, json.Unmarshal(body, &response))
+		require.Len(t, response.Expenses, 1)
+		assert.Equal(t, 1400.0, response.Summary.Spent)
+		assert.Equal(t, 8600.0, response.Summary.Remaining)
+
+		status, _ = do("GET", "/api/v1/trips/8/expenses", nil)
+		assert.Equal(t, fiber.StatusOK, status, "Trips without expenses have an empty ledger")
+	})
+
+	t.Run("Edits an expense", func(t *testing.T) {
+		status, body := do("PUT", "/api/v1/trips/7/expenses/1", models.ExpenseRequest{
+			Category: "food", Amount: 1600, Date: "2025-05-01",
+		})
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		status, body = do("GET", "/api/v1/trips/7/expenses/summary", nil)
+		require.Equal(t, fiber.StatusOK, status)
+		var summary agents.ExpenseSummary
+		require.NoError(t, json.Unmarshal(body, &summary))
+		assert.True(t, summary.Categories[2].OverBudget)
+		assert.Equal(t, 1600.0, summary.BurnRate)
+
+		status, _ = do("PUT", "/api/v1/trips/8/expenses/1", models.ExpenseRequest{Category: "food", Amount: 1, Date: "2025-05-01"})
+		assert.Equal(t, fiber.StatusNotFound, status, "Expenses belong to one trip")
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		for name, request := range map[string]models.ExpenseRequest{
+			"Unknown category":       {Category: "souvenirs", Amount: 100, Date: "2025-05-01"},
+			"Zero amount":            {Category: "food", Amount: 0, Date: "2025-05-01"},
+			"Bad date":               {Category: "food", Amount: 100, Date: "May 1st"},
+			"Unsupported currency":   {Category: "food", Amount: 100, Currency: "XYZ", Date: "2025-05-01"},
+			"Negative itinerary day": {Category: "food", Amount: 100, Date: "2025-05-01", Day: -1},
+		} {
+			status, _ := do("POST", "/api/v1/trips/7/expenses", request)
+			assert.Equal(t, fiber.StatusBadRequest, status, name)
+		}
+
+		status, _ := do("POST", "/api/v1/trips/abc/expenses", models.ExpenseRequest{})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+		status, _ = do("PUT", "/api/v1/trips/7/budget", models.TripBudgetRequest{})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+
+	t.Run("Deletes an expense", func(t *testing.T) {
+		status, _ := do("DELETE", "/api/v1/trips/7/expenses/1", nil)
+		assert.Equal(t, fiber.StatusNoContent, status)
+		status, _ = do("DELETE", "/api/v1/trips/7/expenses/1", nil)
+		assert.Equal(t, fiber.StatusNotFound, status)
+
+		status, body := do("GET", "/api/v1/trips/7/expenses", nil)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Contains(t, string(body), `"expenses":[]`)
+	})
+}
+
+func TestExpenseHandler_GroupSplitting(t *testing.T) {
+	handler := NewExpenseHandler(storage.NewMemoryExpenseRepository(), nil)
+
+	app := fiber.New()
+	app.Post("/api/v1/trips/:id/expenses", handler.CreateExpense)
+	app.Get("/api/v1/trips/:id/members", handler.ListMembers)
+	app.Post("/api/v1/trips/:id/members", handler.AddMember)
+	app.Delete("/api/v1/trips/:id/members/:memberId", handler.DeleteMember)
+	app.Get("/api/v1/trips/:id/settlement", handler.GetSettlement)
+
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	for _, name := range []string{"Alice", "Bob", "Chai"} {
+		status, body := do("POST", "/api/v1/trips/7/members", models.TripMemberRequest{Name: name})
+		require.Equal(t, fiber.StatusCreated, status, string(body))
+	}
+
+	status, _ := do("POST", "/api/v1/trips/7/members", models.TripMemberRequest{Name: "alice"})
+	assert.Equal(t, fiber.StatusConflict, status, "Member names are unique within a trip")
+	status, _ = do("POST", "/api/v1/trips/7/members", models.TripMemberRequest{Name: " "})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+
+	status, body := do("GET", "/api/v1/trips/7/members", nil)
+	require.Equal(t, fiber.StatusOK, status)
+	var members []agents.TripMember
+	require.NoError(t, json.Unmarshal(body, &members))
+	require.Len(t, members, 3)
+
+	t.Run("Records shared expenses", func(t *testing.T) {
+		status, body := do("POST", "/api/v1/trips/7/expenses", models.ExpenseRequest{
+			Category: "hotel", Amount: 3000, Date: "2025-05-01", PaidBy: 1,
+		})
+		require.Equal(t, fiber.StatusCreated, status, string(body))
+
+		status, body = do("POST", "/api/v1/trips/7/expenses", models.ExpenseRequest{
+			Category: "food", Amount: 600, Date: "2025-05-01", PaidBy: 2, Split: &models.SplitRequest{
+				Mode:   "Percent",
+				Shares: []models.SplitShareRequest{{MemberID: 2, Percent: 50}, {MemberID: 3, Percent: 50}},
+			},
+		})
+		require.Equal(t, fiber.StatusCreated, status, string(body))
+	})
+
+	t.Run("Rejects invalid splits", func(t *testing.T) {
+		for name, request := range map[string]models.ExpenseRequest{
+			"Unknown payer":         {Category: "food", Amount: 100, Date: "2025-05-01", PaidBy: 9},
+			"Split without a payer": {Category: "food", Amount: 100, Date: "2025-05-01", Split: &models.SplitRequest{Mode: "equal"}},
+			"Exact amounts too low": {Category: "food", Amount: 100, Date: "2025-05-01", PaidBy: 1, Split: &models.SplitRequest{
+				Mode: "exact", Shares: []models.SplitShareRequest{{MemberID: 1, Amount: 40}},
+			}},
+		} {
+			status, _ := do("POST", "/api/v1/trips/7/expenses", request)
+			assert.Equal(t, fiber.StatusBadRequest, status, name)
+		}
+	})
+
+	t.Run("Settles up", func(t *testing.T) {
+		status, body := do("GET", "/api/v1/trips/7/settlement", nil)
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		var settlement agents.Settlement
+		require.NoError(t, json.Unmarshal(body, &settlement))
+		assert.Equal(t, "THB", settlement.Currency)
+		assert.Equal(t, []agents.Transfer{
+			{From: 3, FromName: "Chai", To: 1, ToName: "Alice", Amount: 1300},
+			{From: 2, FromName: "Bob", To: 1, ToName: "Alice", Amount: 700},
+		}, settlement.Transfers)
+	})
+
+	t.Run("Keeps members who are part of expenses", func(t *testing.T) {
+		status, _ := do("DELETE", "/api/v1/trips/7/members/3", nil)
+		assert.Equal(t, fiber.StatusConflict, status)
+
+		status, _ = do("POST", "/api/v1/trips/7/members", models.TripMemberRequest{Name: "Dao"})
+		require.Equal(t, fiber.StatusCreated, status)
+		status, body := do("GET", "/api/v1/trips/7/settlement", nil)
+		require.Equal(t, fiber.StatusOK, status)
+		var settlement agents.Settlement
+		require.NoError(t, json.Unmarshal(body, &settlement))
+		assert.Equal(t, 0.0, settlement.Balances[3].Share, "Members who join later do not share earlier costs")
+
+		status, _ = do("DELETE", "/api/v1/trips/7/members/4", nil)
+		assert.Equal(t, fiber.StatusNoContent, status)
+	})
+}
+
+func TestExpenseHandler_BudgetFromSavedTrip(t *testing.T) {
+	store := storage.NewMemoryExpenseRepository()
+	trips := storage.NewMemoryTripRepository()
+	handler := NewExpenseHandler(store, nil)
+	handler.SetTripRepository(trips)
+
+	app := fiber.New()
+	app.Put("/api/v1/trips/:id/budget", handler.SetBudget)
+
+	ctx := context.Background()
+	trip := &agents.Trip{UserID: "user-1", Title: "Bali", Destination: "Bali", Duration: 4}
+	require.NoError(t, trips.CreateTrip(ctx, trip))
+	for _, name := range []string{"Anna", "Ben", "Chai"} {
+		require.NoError(t, store.AddMember(ctx, &agents.TripMember{TripID: trip.ID, Name: name}))
+	}
+
+	payload, err := json.Marshal(models.TripBudgetRequest{Total: 45000})
+	require.NoError(t, err)
+	req := httptest.NewRequest("PUT", fmt.Sprintf("/api/v1/trips/%d/budget", trip.ID), bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	var budget agents.TripBudget
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&budget))
+	estimate := agents.EstimateTripBudget(agents.BudgetRequest{Destination: "Bali", Days: 4, Travelers: 3, Total: 45000})
+	assert.Equal(t, estimate.BudgetPlan(), budget.Plan, "Destination and length come from the trip, travelers from its members")
+}
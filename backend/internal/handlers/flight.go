@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// FlightHandler handles flight search HTTP requests
+type FlightHandler struct {
+	flightAgent *agents.FlightAgent
+}
+
+// NewFlightHandler creates a new flight handler instance
+func NewFlightHandler(flightAgent *agents.FlightAgent) *FlightHandler {
+	return &FlightHandler{
+		flightAgent: flightAgent,
+	}
+}
+
+// SearchFlights handles GET and POST /api/v1/flights/search requests
+func (h *FlightHandler) SearchFlights(c *fiber.Ctx) error {
+	var req models.FlightSearchRequest
+	parse := c.BodyParser
+	if c.Method() == fiber.MethodGet {
+		parse = c.QueryParser
+	}
+	if err := parse(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	search, err := parseFlightSearch(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	options, err := h.flightAgent.SearchFlights(ctx, search)
+	if err != nil {
+		log.Printf("Flight search failed: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Flight search error",
+			Message: fmt.Sprintf("Failed to search flights: %v", err),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"search":  search.WithDefaults(),
+		"options": options,
+	})
+}
+
+// parseFlightSearch validates a flight search request and parses its date
+func parseFlightSearch(req models.FlightSearchRequest) (agents.FlightSearchRequest, error) {
+	search := agents.FlightSearchRequest{
+		Origin:      req.Origin,
+		Destination: req.Destination,
+		Passengers:  req.Passengers,
+		MaxStops:    req.MaxStops,
+		DirectOnly:  req.DirectOnly,
+		SortBy:      agents.NormalizeFlightSort(req.SortBy),
+	}
+
+	if req.Origin == "" || req.Destination == "" {
+		return search, fmt.Errorf("origin and destination are required")
+	}
+	if req.Passengers < 0 || req.Passengers > 9 {
+		return search, fmt.Errorf("passengers must be between 1 and 9")
+	}
+	if req.MaxStops < 0 {
+		return search, fmt.Errorf("max_stops must not be negative")
+	}
+	if req.Date != "" {
+		date, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			return search, fmt.Errorf("date must be YYYY-MM-DD")
+		}
+		search.Date = date
+	}
+
+	return search, nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flightSearchResponse is the body returned by /api/v1/flights/search
+type flightSearchResponse struct {
+	Search  agents.FlightSearchRequest `json:"search"`
+	Options []agents.FlightOption      `json:"options"`
+}
+
+func TestFlightHandler_SearchFlights(t *testing.T) {
+	app := fiber.New()
+	handler := NewFlightHandler(agents.NewFlightAgent("", ""))
+	app.Get("/api/v1/flights/search", handler.SearchFlights)
+	app.Post("/api/v1/flights/search", handler.SearchFlights)
+
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	t.Run("GET returns ranked options", func(t *testing.T) {
+		status, body := do("GET", "/api/v1/flights/search?origin=Bangkok&destination=Tokyo&date=2025-05-01&passengers=2&sort_by=cheapest", nil)
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		var response flightSearchResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.Equal(t, "BKK", response.Search.Origin)
+		assert.Equal(t, "NRT", response.Search.Destination)
+		assert.Equal(t, agents.FlightSortPrice, response.Search.SortBy)
+		require.NotEmpty(t, response.Options)
+		assert.Equal(t, 1, response.Options[0].Rank)
+		for i := 1; i < len(response.Options); i++ {
+			assert.LessOrEqual(t, response.Options[i-1].PricePerPassenger, response.Options[i].PricePerPassenger)
+		}
+		assert.Equal(t, response.Options[0].PricePerPassenger*2, response.Options[0].TotalPrice)
+	})
+
+	t.Run("POST filters direct flights", func(t *testing.T) {
+		status, body := do("POST", "/api/v1/flights/search", models.FlightSearchRequest{
+			Origin: "BKK", Destination: "NRT", Date: "2025-05-01", DirectOnly: true,
+		})
+		require.Equal(t, fiber.StatusOK, status, string(body))
+
+		var response flightSearchResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		require.NotEmpty(t, response.Options)
+		for _, option := range response.Options {
+			assert.Equal(t, 0, option.Stops)
+		}
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		for name, request := range map[string]models.FlightSearchRequest{
+			"Missing destination": {Origin: "BKK"},
+			"Bad date":            {Origin: "BKK", Destination: "NRT", Date: "01/05/2025"},
+			"Too many passengers": {Origin: "BKK", Destination: "NRT", Passengers: 12},
+			"Same airport":        {Origin: "Bangkok", Destination: "BKK"},
+		} {
+			status, _ := do("POST", "/api/v1/flights/search", request)
+			assert.Equal(t, fiber.StatusBadRequest, status, name)
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// flightCodePattern matches IATA flight numbers such as TG642 or JL708
+var flightCodePattern = regexp.MustCompile(`^[A-Z0-9]{2}\d{1,4}[A-Z]?$`)
+
+// FlightWatchHandler handles flight watch subscription HTTP requests
+type FlightWatchHandler struct {
+	store agents.FlightWatchStore
+}
+
+// NewFlightWatchHandler creates a new flight watch handler instance
+func NewFlightWatchHandler(store agents.FlightWatchStore) *FlightWatchHandler {
+	return &FlightWatchHandler{
+		store: store,
+	}
+}
+
+// CreateWatch handles POST /api/v1/flights/watches requests, watching the flight for the signed-in user
+func (h *FlightWatchHandler) CreateWatch(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	var req models.FlightWatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	watch, err := parseFlightWatch(req, time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	watch.UserID = user.ID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := h.store.CreateWatch(ctx, &watch); err != nil {
+		log.Printf("Failed to create flight watch: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to create flight watch",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(watch)
+}
+
+// ListWatches handles GET /api/v1/flights/watches requests, listing the signed-in user's watches
+func (h *FlightWatchHandler) ListWatches(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watches, err := h.store.ListWatches(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to list flight watches: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve flight watches",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(watches)
+}
+
+// GetWatch handles GET /api/v1/flights/watches/:id requests, returning the watch and its status changes
+func (h *FlightWatchHandler) GetWatch(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "id must be a positive number",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := h.store.GetWatch(ctx, int64(id))
+	// Other users' watches are reported as missing rather than forbidden so IDs cannot be probed
+	if err == nil && (watch == nil || watch.UserID != user.ID) {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("flight watch %d not found", id),
+			Code:    fiber.StatusNotFound,
+		})
+	}
+	var events []agents.FlightWatchEvent
+	if err == nil {
+		events, err = h.store.ListEvents(ctx, watch.ID)
+	}
+	if err != nil {
+		log.Printf("Failed to get flight watch %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve flight watch",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"watch":  watch,
+		"events": events,
+	})
+}
+
+// DeleteWatch handles DELETE /api/v1/flights/watches/:id requests
+func (h *FlightWatchHandler) DeleteWatch(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	id, err := c.ParamsInt("id")
+	if err != nil || id <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "id must be a positive number",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	watch, err := h.store.GetWatch(ctx, int64(id))
+	if err == nil && (watch == nil || watch.UserID != user.ID) {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("flight watch %d not found", id),
+			Code:    fiber.StatusNotFound,
+		})
+	}
+	if err == nil {
+		err = h.store.DeleteWatch(ctx, int64(id))
+	}
+	if err != nil {
+		log.Printf("Failed to delete flight watch %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete flight watch",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseFlightWatch validates a watch request; flights that departed more than a day ago cannot be watched
+func parseFlightWatch(req models.FlightWatchRequest, now time.Time) (agents.FlightWatch, error) {
+	watch := agents.FlightWatch{
+		FlightCode: strings.ToUpper(strings.ReplaceAll(req.FlightCode, " ", "")),
+		Channel:    strings.ToLower(strings.TrimSpace(req.Channel)),
+		Target:     strings.TrimSpace(req.Target),
+		Active:     true,
+	}
+
+	if !flightCodePattern.MatchString(watch.FlightCode) {
+		return watch, fmt.Errorf("flight_code must be a flight number such as TG642")
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return watch, fmt.Errorf("date must be YYYY-MM-DD")
+	}
+	if date.Before(now.AddDate(0, 0, -1).Truncate(24 * time.Hour)) {
+		return watch, fmt.Errorf("date must not be in the past")
+	}
+	watch.FlightDate = date
+
+	switch watch.Channel {
+	case agents.ChannelWebhook:
+		target, err := url.Parse(watch.Target)
+		if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+			return watch, fmt.Errorf("target must be an http(s) URL for webhook notifications")
+		}
+	case agents.ChannelEmail:
+		if !strings.Contains(watch.Target, "@") {
+			return watch, fmt.Errorf("target must be an email address for email notifications")
+		}
+	case agents.ChannelLine:
+		if watch.Target == "" {
+			return watch, fmt.Errorf("target must be a LINE user ID for LINE notifications")
+		}
+	default:
+		return watch, fmt.Errorf("channel must be webhook, line or email")
+	}
+
+	return watch, nil
+}
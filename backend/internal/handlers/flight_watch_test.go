@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightWatchHandler(t *testing.T) {
+	store := agents.NewMemoryFlightWatchStore()
+	handler := NewFlightWatchHandler(store)
+	tokens := auth.NewTokenManager("test-secret", time.Hour, 0)
+
+	app := fiber.New()
+	app.Use(auth.Middleware(tokens))
+	app.Post("/api/v1/flights/watches", handler.CreateWatch)
+	app.Get("/api/v1/flights/watches", handler.ListWatches)
+	app.Get("/api/v1/flights/watches/:id", handler.GetWatch)
+	app.Delete("/api/v1/flights/watches/:id", handler.DeleteWatch)
+
+	token := accessToken(t, tokens, "user-1")
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	status, body := do("POST", "/api/v1/flights/watches", models.FlightWatchRequest{
+		FlightCode: "tg 642", Date: tomorrow, Channel: "Webhook", Target: "https://example.com/hook",
+	})
+	require.Equal(t, fiber.StatusCreated, status, string(body))
+
+	var created agents.FlightWatch
+	require.NoError(t, json.Unmarshal(body, &created))
+	assert.Equal(t, "TG642", created.FlightCode)
+	assert.Equal(t, agents.ChannelWebhook, created.Channel)
+	assert.True(t, created.Active)
+	assert.Equal(t, "user-1", created.UserID, "Watches belong to the signed-in user")
+
+	t.Run("Lists the user's watches", func(t *testing.T) {
+		status, body := do("GET", "/api/v1/flights/watches", nil)
+		require.Equal(t, fiber.StatusOK, status)
+
+		var watches []agents.FlightWatch
+		require.NoError(t, json.Unmarshal(body, &watches))
+		require.Len(t, watches, 1)
+		assert.Equal(t, created.ID, watches[0].ID)
+	})
+
+	t.Run("Hides other users' watches", func(t *testing.T) {
+		token = accessToken(t, tokens, "user-2")
+		defer func() { token = accessToken(t, tokens, "user-1") }()
+
+		status, body := do("GET", "/api/v1/flights/watches", nil)
+		require.Equal(t, fiber.StatusOK, status)
+		assert.Equal(t, "[]", string(body))
+
+		status, _ = do("GET", "/api/v1/flights/watches/1", nil)
+		assert.Equal(t, fiber.StatusNotFound, status)
+		status, _ = do("DELETE", "/api/v1/flights/watches/1", nil)
+		assert.Equal(t, fiber.StatusNotFound, status)
+	})
+
+	t.Run("Requires a signed-in user", func(t *testing.T) {
+		token = ""
+		defer func() { token = accessToken(t, tokens, "user-1") }()
+
+		status, _ := do("GET", "/api/v1/flights/watches", nil)
+		assert.Equal(t, fiber.StatusUnauthorized, status)
+	})
+
+	t.Run("Returns the watch with its status changes", func(t *testing.T) {
+		require.NoError(t, store.AddEvent(context.Background(), &agents.FlightWatchEvent{
+			WatchID: created.ID, FlightCode: "TG642", Changes: []string{agents.FlightChangeGate}, Message: "Flight TG642 update: gate A12 → B3.",
+		}))
+
+		status, body := do("GET", "/api/v1/flights/watches/1", nil)
+		require.Equal(t, fiber.StatusOK, status)
+
+		var response struct {
+			Watch  agents.FlightWatch        `json:"watch"`
+			Events []agents.FlightWatchEvent `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal(body, &response))
+		assert.Equal(t, "TG642", response.Watch.FlightCode)
+		require.Len(t, response.Events, 1)
+		assert.Equal(t, []string{"gate"}, response.Events[0].Changes)
+
+		status, _ = do("GET", "/api/v1/flights/watches/99", nil)
+		assert.Equal(t, fiber.StatusNotFound, status)
+	})
+
+	t.Run("Validation errors", func(t *testing.T) {
+		for name, request := range map[string]models.FlightWatchRequest{
+			"Bad flight code":   {FlightCode: "hello", Date: tomorrow, Channel: "line", Target: "U123"},
+			"Bad date":          {FlightCode: "TG642", Date: "tomorrow", Channel: "line", Target: "U123"},
+			"Past date":         {FlightCode: "TG642", Date: "2020-01-01", Channel: "line", Target: "U123"},
+			"Unknown channel":   {FlightCode: "TG642", Date: tomorrow, Channel: "sms", Target: "+66800000000"},
+			"Bad webhook URL":   {FlightCode: "TG642", Date: tomorrow, Channel: "webhook", Target: "example.com"},
+			"Bad email address": {FlightCode: "TG642", Date: tomorrow, Channel: "email", Target: "someone"},
+		} {
+			status, _ := do("POST", "/api/v1/flights/watches", request)
+			assert.Equal(t, fiber.StatusBadRequest, status, name)
+		}
+	})
+
+	t.Run("Deletes the watch", func(t *testing.T) {
+		status, _ := do("DELETE", "/api/v1/flights/watches/1", nil)
+		assert.Equal(t, fiber.StatusNoContent, status)
+
+		status, _ = do("GET", "/api/v1/flights/watches/1", nil)
+		assert.Equal(t, fiber.StatusNotFound, status)
+	})
+}
@@ -1,23 +1,29 @@
 package handlers
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/orchestrator"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/services"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
 )
 
 // PlanHandler handles travel plan-related HTTP requests
 type PlanHandler struct {
 	planService  *services.PlanService
 	orchestrator *orchestrator.Orchestrator
+	trips        storage.TripRepository
 }
 
 // NewPlanHandler creates a new plan handler instance
@@ -28,6 +34,11 @@ func NewPlanHandler(planService *services.PlanService, orch *orchestrator.Orches
 	}
 }
 
+// SetTripRepository lets messages with a trip_id talk about one of the user's saved trips
+func (h *PlanHandler) SetTripRepository(trips storage.TripRepository) {
+	h.trips = trips
+}
+
 // CreateTravelPlan handles POST /api/plan requests
 func (h *PlanHandler) CreateTravelPlan(c *fiber.Ctx) error {
 	var req models.PlanRequest
@@ -54,8 +65,21 @@ func (h *PlanHandler) CreateTravelPlan(c *fiber.Ctx) error {
 
 	// Use orchestrator if available, otherwise use plan service
 	if h.orchestrator != nil {
-		log.Printf("Using orchestrator to process message: %s", req.Message)
-		rawResponse, err := h.orchestrator.ProcessMessage(ctx, req.Message)
+		// Start a new conversation when the client has no session yet
+		if req.SessionID == "" {
+			req.SessionID = uuid.NewString()
+		}
+
+		ctx, errResp := h.withSavedTrip(withUser(ctx, c), c, req.TripID)
+		if errResp == nil {
+			errResp = h.checkSession(ctx, req.SessionID)
+		}
+		if errResp != nil {
+			return c.Status(errResp.Code).JSON(errResp)
+		}
+
+		log.Printf("Using orchestrator to process message: %s (session %s)", req.Message, req.SessionID)
+		result, err := h.orchestrator.Process(withDisplayCurrency(ctx, c, req.Currency), req.SessionID, req.Message)
 		if err != nil {
 			log.Printf("Orchestrator error: %v", err)
 			return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
@@ -66,12 +90,38 @@ func (h *PlanHandler) CreateTravelPlan(c *fiber.Ctx) error {
 		}
 
 		// Format response as Markdown if it's JSON
-		formattedResponse := formatResponseAsMarkdown(rawResponse)
+		formattedResponse := formatResponseAsMarkdown(result.Markdown())
 
-		return c.JSON(fiber.Map{
-			"success":  true,
-			"response": formattedResponse,
-		})
+		switch responseFormat(c, req.Format) {
+		case formatMarkdownText:
+			c.Set(fiber.HeaderContentType, "text/markdown; charset=utf-8")
+			return c.SendString(formattedResponse)
+		case formatMarkdown:
+			return c.JSON(fiber.Map{
+				"success":    true,
+				"response":   formattedResponse,
+				"session_id": req.SessionID,
+			})
+		case formatStructured:
+			return c.JSON(fiber.Map{
+				"success":    true,
+				"session_id": req.SessionID,
+				"intent":     result.Intent,
+				"type":       result.Result.Type(),
+				"currency":   result.Currency,
+				"data":       result.Result,
+			})
+		default:
+			return c.JSON(fiber.Map{
+				"success":    true,
+				"response":   formattedResponse,
+				"session_id": req.SessionID,
+				"intent":     result.Intent,
+				"type":       result.Result.Type(),
+				"currency":   result.Currency,
+				"data":       result.Result,
+			})
+		}
 	}
 
 	// Fallback to plan service
@@ -100,6 +150,238 @@ func (h *PlanHandler) CreateTravelPlan(c *fiber.Ctx) error {
 	return c.JSON(planResponse)
 }
 
+// StreamTravelPlan handles GET and POST /api/plan/stream requests with Server-Sent Events
+func (h *PlanHandler) StreamTravelPlan(c *fiber.Ctx) error {
+	var req models.PlanRequest
+	if c.Method() == fiber.MethodGet {
+		// EventSource clients can only send GET requests
+		req.Message = c.Query("message")
+		req.SessionID = c.Query("session_id")
+		req.Currency = c.Query("currency")
+		if tripID := c.Query("trip_id"); tripID != "" {
+			id, err := strconv.ParseInt(tripID, 10, 64)
+			if err != nil || id <= 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+					Error:   "Validation error",
+					Message: "trip_id must be a positive integer",
+					Code:    fiber.StatusBadRequest,
+				})
+			}
+			req.TripID = id
+		}
+	} else if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	if req.Message == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "message field is required",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	if h.orchestrator == nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(models.ErrorResponse{
+			Error:   "Service unavailable",
+			Message: "Streaming requires the orchestrator",
+			Code:    fiber.StatusServiceUnavailable,
+		})
+	}
+
+	if req.SessionID == "" {
+		req.SessionID = uuid.NewString()
+	}
+
+	// Load the saved trip and session before streaming starts so a missing one is a plain 404
+	tripCtx, errResp := h.withSavedTrip(withUser(context.Background(), c), c, req.TripID)
+	if errResp == nil {
+		errResp = h.checkSession(tripCtx, req.SessionID)
+	}
+	if errResp != nil {
+		return c.Status(errResp.Code).JSON(errResp)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	orch := h.orchestrator
+	currency := req.Currency
+	if currency == "" {
+		currency = c.Query("currency")
+	}
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		ctx, cancel := context.WithTimeout(tripCtx, 30*time.Second)
+		defer cancel()
+		if currency != "" {
+			ctx = orchestrator.WithCurrency(ctx, currency)
+		}
+
+		disconnected := false
+		send := func(event string, data interface{}) {
+			if disconnected {
+				return
+			}
+			if err := writeSSE(w, event, data); err != nil {
+				disconnected = true
+				// The client went away; stop the remaining agents
+				log.Printf("SSE write failed: %v", err)
+				cancel()
+			}
+		}
+
+		send("session", fiber.Map{"session_id": req.SessionID})
+
+		log.Printf("Streaming orchestrator response for: %s (session %s)", req.Message, req.SessionID)
+		_, err := orch.ProcessStream(ctx, req.SessionID, req.Message, func(event orchestrator.StreamEvent) {
+			if event.Type == orchestrator.EventResult {
+				response := event.Data.(*orchestrator.Response)
+				send(event.Type, fiber.Map{
+					"session_id": req.SessionID,
+					"intent":     response.Intent,
+					"type":       response.Result.Type(),
+					"currency":   response.Currency,
+					"data":       response.Result,
+					"response":   formatResponseAsMarkdown(response.Markdown()),
+				})
+				return
+			}
+			send(event.Type, event.Data)
+		})
+		if err != nil {
+			log.Printf("Orchestrator error: %v", err)
+			send("error", models.ErrorResponse{
+				Error:   "AI service error",
+				Message: fmt.Sprintf("Failed to process request: %v", err),
+				Code:    fiber.StatusInternalServerError,
+			})
+			return
+		}
+
+		send("done", fiber.Map{"success": true})
+	})
+
+	return nil
+}
+
+// withSavedTrip attaches the signed-in user's saved trip to ctx so the orchestrator updates and
+// answers questions about it. A zero tripID leaves ctx as it is.
+func (h *PlanHandler) withSavedTrip(ctx context.Context, c *fiber.Ctx, tripID int64) (context.Context, *models.ErrorResponse) {
+	if tripID == 0 {
+		return ctx, nil
+	}
+	if tripID < 0 {
+		return ctx, &models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "trip_id must be a positive integer",
+			Code:    fiber.StatusBadRequest,
+		}
+	}
+	if h.trips == nil {
+		return ctx, &models.ErrorResponse{
+			Error:   "Service unavailable",
+			Message: "Saved trips are not configured",
+			Code:    fiber.StatusServiceUnavailable,
+		}
+	}
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return ctx, &models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Sign in to use a saved trip",
+			Code:    fiber.StatusUnauthorized,
+		}
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	trip, errResp := findOwnedTrip(lookupCtx, h.trips, user, tripID)
+	if errResp != nil {
+		return ctx, errResp
+	}
+	return orchestrator.WithSavedTrip(ctx, trip.Plan()), nil
+}
+
+// checkSession answers 404 for a session another user started, as if it did not exist
+func (h *PlanHandler) checkSession(ctx context.Context, sessionID string) *models.ErrorResponse {
+	if err := h.orchestrator.CheckSession(ctx, sessionID); err != nil {
+		return &models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("session %s not found", sessionID),
+			Code:    fiber.StatusNotFound,
+		}
+	}
+	return nil
+}
+
+// withUser attaches the signed-in user, if any, so conversations stay with whoever started them
+func withUser(ctx context.Context, c *fiber.Ctx) context.Context {
+	if user := auth.CurrentUser(c); user != nil {
+		return orchestrator.WithUser(ctx, user.ID)
+	}
+	return ctx
+}
+
+// withDisplayCurrency attaches the requested display currency, from the body or the currency query
+// parameter, to ctx
+func withDisplayCurrency(ctx context.Context, c *fiber.Ctx, requested string) context.Context {
+	if requested == "" {
+		requested = c.Query("currency")
+	}
+	if requested == "" {
+		return ctx
+	}
+	return orchestrator.WithCurrency(ctx, requested)
+}
+
+// writeSSE writes a single Server-Sent Event with a JSON payload and flushes it
+func writeSSE(w *bufio.Writer, event string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// Response formats supported by POST /api/plan
+const (
+	formatBoth         = "both"
+	formatMarkdown     = "markdown"
+	formatStructured   = "json"
+	formatMarkdownText = "text"
+)
+
+// responseFormat picks the response shape from the request body, the ?format= query or the Accept header
+func responseFormat(c *fiber.Ctx, requested string) string {
+	if requested == "" {
+		requested = c.Query("format")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(requested)) {
+	case "markdown", "md":
+		return formatMarkdown
+	case "json", "structured":
+		return formatStructured
+	case "both":
+		return formatBoth
+	}
+
+	// Only a client that explicitly prefers markdown gets a raw markdown body
+	if c.Get(fiber.HeaderAccept) != "" && c.Accepts(fiber.MIMEApplicationJSON, "text/markdown") == "text/markdown" {
+		return formatMarkdownText
+	}
+	return formatBoth
+}
+
 // formatResponseAsMarkdown converts raw response to beautiful Markdown
 func formatResponseAsMarkdown(raw string) string {
 	// Try to parse as JSON
@@ -1,7 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/orchestrator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFormatNumber(t *testing.T) {
@@ -186,3 +196,151 @@ func contains(s, substr string) bool {
 			return false
 		}())
 }
+
+func TestCreateTravelPlan_ResponseFormats(t *testing.T) {
+	handler := NewPlanHandler(nil, orchestrator.New("", "", "", ""))
+	app := fiber.New()
+	app.Post("/api/plan", handler.CreateTravelPlan)
+
+	send := func(body, accept, query string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/api/plan"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+		return resp
+	}
+
+	decode := func(resp *http.Response) map[string]interface{} {
+		var payload map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+		return payload
+	}
+
+	message := `"message":"What's the weather in Bangkok?"`
+
+	t.Run("Default returns markdown and structured data", func(t *testing.T) {
+		payload := decode(send("{"+message+"}", "", ""))
+		assert.Equal(t, true, payload["success"])
+		assert.Contains(t, payload["response"], "Weather")
+		assert.Equal(t, "weather_check", payload["intent"])
+		assert.Equal(t, "weather_forecast", payload["type"])
+		data, ok := payload["data"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "Bangkok", data["city"])
+	})
+
+	t.Run("Format field selects structured only", func(t *testing.T) {
+		payload := decode(send("{"+message+`,"format":"json"}`, "", ""))
+		assert.NotContains(t, payload, "response")
+		assert.Equal(t, "weather_forecast", payload["type"])
+		assert.NotEmpty(t, payload["session_id"])
+	})
+
+	t.Run("Format query selects markdown only", func(t *testing.T) {
+		payload := decode(send("{"+message+"}", "", "?format=markdown"))
+		assert.Contains(t, payload["response"], "Weather")
+		assert.NotContains(t, payload, "data")
+	})
+
+	t.Run("Accept header negotiates raw markdown", func(t *testing.T) {
+		resp := send("{"+message+"}", "text/markdown", "")
+		assert.Contains(t, resp.Header.Get("Content-Type"), "text/markdown")
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), "Weather")
+	})
+
+	t.Run("Accept JSON keeps the default shape", func(t *testing.T) {
+		payload := decode(send("{"+message+"}", "application/json", ""))
+		assert.Contains(t, payload, "response")
+		assert.Contains(t, payload, "data")
+	})
+
+	t.Run("Currency selects the display currency", func(t *testing.T) {
+		budget := `"message":"What can I do with 50000 baht budget?"`
+
+		payload := decode(send("{"+budget+`,"currency":"usd"}`, "", ""))
+		assert.Equal(t, "USD", payload["currency"])
+		assert.Contains(t, payload["response"], "USD")
+
+		payload = decode(send("{"+budget+"}", "", "?currency=EUR"))
+		assert.Equal(t, "EUR", payload["currency"])
+
+		payload = decode(send("{"+budget+"}", "", ""))
+		assert.Equal(t, "THB", payload["currency"])
+	})
+}
+
+func TestStreamTravelPlan_EmitsServerSentEvents(t *testing.T) {
+	handler := NewPlanHandler(nil, orchestrator.New("", "", "", ""))
+	app := fiber.New()
+	app.Get("/api/plan/stream", handler.StreamTravelPlan)
+	app.Post("/api/plan/stream", handler.StreamTravelPlan)
+
+	t.Run("POST streams every plan stage", func(t *testing.T) {
+		body := `{"message":"Plan a trip to Tokyo for 4 days with 40000 baht","session_id":"stream-1"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/plan/stream", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		stream := string(raw)
+
+		for _, stage := range []string{"itinerary", "weather", "hotels", "popular_spots"} {
+			assert.Contains(t, stream, "event: "+stage+"\n")
+		}
+
+		order := []string{
+			"event: session\ndata: {\"session_id\":\"stream-1\"}",
+			"event: intent\n",
+			"event: summary_token\n",
+			"event: result\n",
+			"event: done\n",
+		}
+		last := -1
+		for _, marker := range order {
+			index := strings.Index(stream, marker)
+			require.NotEqual(t, -1, index, "missing %q", marker)
+			assert.Greater(t, index, last, "%q is out of order", marker)
+			last = index
+		}
+	})
+
+	t.Run("GET reads the message from the query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/plan/stream?message=Hello", nil)
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), "event: result\n")
+		assert.Contains(t, string(raw), `"type":"message"`)
+	})
+
+	t.Run("GET reads the currency from the query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/plan/stream?message=Hello&currency=JPY", nil)
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+
+		raw, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"currency":"JPY"`)
+	})
+
+	t.Run("Missing message is rejected", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/plan/stream", nil)
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -63,8 +63,8 @@ func (h *SocialHandler) GetSocialPlaces(c *fiber.Ctx) error {
 		req.Limit = 10
 	}
 
-	ctx := context.Background()
-	_ = ctx // for future use
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
 
 	// Check cache first (only if redis is available)
 	cacheKey := fmt.Sprintf("social:%s:%s:%d", req.Keyword, req.Location, req.Limit)
@@ -87,7 +87,7 @@ func (h *SocialHandler) GetSocialPlaces(c *fiber.Ctx) error {
 		})
 	}
 
-	places, err := h.social.GetTopRatedPlaces(req.Keyword, req.Location, req.Limit)
+	places, err := h.social.GetTopRatedPlaces(ctx, req.Keyword, req.Location, req.Limit)
 	if err != nil {
 		log.Printf("Error fetching social places: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
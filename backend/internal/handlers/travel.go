@@ -3,41 +3,75 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/services"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
 )
 
+// HealthChecker is a dependency the health check reports on
+type HealthChecker interface {
+	HealthCheck() error
+}
+
 // TravelHandler handles travel-related HTTP requests
 type TravelHandler struct {
-	db      *database.PostgresDB
-	redis   *database.RedisCache
-	openai  *services.OpenAIService
-	weather *services.WeatherService
-	flight  *services.FlightService
+	searches        storage.SearchRepository
+	recommendations storage.RecommendationRepository
+	cache           cache.Cache
+	database        HealthChecker
+	redis           HealthChecker
+	openai          *services.OpenAIService
+	weather         *services.WeatherService
+	flight          *services.FlightService
+	hotels          *agents.HotelAgent
+	local           *agents.LocalAgent
+	social          *services.SocialService
 }
 
-// NewTravelHandler creates a new travel handler instance
+// NewTravelHandler creates a new travel handler instance. Search responses are cached for an hour
+// when responseCache is set.
 func NewTravelHandler(
-	db *database.PostgresDB,
-	redis *database.RedisCache,
+	searches storage.SearchRepository,
+	recommendations storage.RecommendationRepository,
+	responseCache cache.Cache,
 	openai *services.OpenAIService,
 	weather *services.WeatherService,
 	flight *services.FlightService,
 ) *TravelHandler {
 	return &TravelHandler{
-		db:      db,
-		redis:   redis,
-		openai:  openai,
-		weather: weather,
-		flight:  flight,
+		searches:        searches,
+		recommendations: recommendations,
+		cache:           responseCache,
+		openai:          openai,
+		weather:         weather,
+		flight:          flight,
 	}
 }
 
+// SetHealthChecks sets the database and Redis connections the health check reports on
+func (h *TravelHandler) SetHealthChecks(database, redis HealthChecker) {
+	h.database = database
+	h.redis = redis
+}
+
+// SetRecommenders sets the sources SearchTravel builds recommendations from; any of them may be nil
+func (h *TravelHandler) SetRecommenders(hotels *agents.HotelAgent, local *agents.LocalAgent, social *services.SocialService) {
+	h.hotels = hotels
+	h.local = local
+	h.social = social
+}
+
 // SearchTravel handles travel search requests
 func (h *TravelHandler) SearchTravel(c *fiber.Ctx) error {
 	var req models.TravelSearchRequest
@@ -58,106 +92,154 @@ func (h *TravelHandler) SearchTravel(c *fiber.Ctx) error {
 		})
 	}
 
+	// Searches by signed-in users show up in their history
+	if user := auth.CurrentUser(c); user != nil {
+		req.UserID = user.ID
+	}
+
 	ctx := context.Background()
 
 	// Check cache first
-	cacheKey := "travel:" + req.Destination
-	if cachedData, err := h.redis.Get(cacheKey); err == nil {
-		log.Println("Returning cached travel data for", req.Destination)
-		var cachedResponse models.TravelSearchResponse
-		if err := json.Unmarshal([]byte(cachedData), &cachedResponse); err == nil {
-			return c.JSON(cachedResponse)
+	var response models.TravelSearchResponse
+	cached := false
+	cacheKey := travelCacheKey(&req)
+	if h.cache != nil {
+		if err := h.cache.GetJSON(ctx, cacheKey, &response); err == nil {
+			log.Println("Returning cached travel data for", req.Destination)
+			cached = true
 		}
 	}
 
-	// Generate AI recommendations
-	var aiRecommendations string
-	if h.openai != nil {
-		recommendations, err := h.openai.GenerateTravelRecommendations(ctx, &req)
-		if err != nil {
-			log.Printf("Warning: Failed to get AI recommendations: %v", err)
-		} else {
-			aiRecommendations = recommendations
+	if !cached {
+		// Generate AI recommendations
+		var aiRecommendations string
+		if h.openai != nil {
+			recommendations, err := h.openai.GenerateTravelRecommendations(ctx, &req)
+			if err != nil {
+				log.Printf("Warning: Failed to get AI recommendations: %v", err)
+			} else {
+				aiRecommendations = recommendations
+			}
 		}
-	}
 
-	// Fetch weather data
-	var weatherInfo *models.WeatherInfo
-	if h.weather != nil {
-		weather, err := h.weather.GetWeather(req.Destination)
-		if err != nil {
-			log.Printf("Warning: Failed to fetch weather: %v", err)
-		} else {
-			weatherInfo = weather
-			// Fetch forecast
-			forecast, err := h.weather.GetForecast(req.Destination, 5)
-			if err == nil {
-				weatherInfo.Forecast = forecast
+		// Fetch weather data
+		var weatherInfo *models.WeatherInfo
+		if h.weather != nil {
+			weather, err := h.weather.GetWeather(req.Destination)
+			if err != nil {
+				log.Printf("Warning: Failed to fetch weather: %v", err)
+			} else {
+				weatherInfo = weather
+				// Fetch forecast
+				forecast, err := h.weather.GetForecast(req.Destination, 5)
+				if err == nil {
+					weatherInfo.Forecast = forecast
+				}
 			}
 		}
-	}
 
-	// Store in database
-	searchID, err := h.storeSearch(&req, aiRecommendations)
-	if err != nil {
-		log.Printf("Warning: Failed to store search: %v", err)
-	}
+		response = models.TravelSearchResponse{
+			Destination:     req.Destination,
+			Summary:         aiRecommendations,
+			Weather:         weatherInfo,
+			Recommendations: h.buildRecommendations(ctx, &req),
+		}
 
-	// Build response
-	response := models.TravelSearchResponse{
-		SearchID:    searchID,
-		Destination: req.Destination,
-		Summary:     aiRecommendations,
-		Weather:     weatherInfo,
-		Recommendations: []models.TravelRecommendation{
-			{
-				Type:        "hotel",
-				Title:       "Luxury Hotel Recommendation",
-				Description: "Experience comfort in the heart of " + req.Destination,
-				Price:       150.00,
-				Rating:      4.5,
-			},
-			{
-				Type:        "activity",
-				Title:       "City Tour",
-				Description: "Discover the best of " + req.Destination,
-				Price:       50.00,
-				Rating:      4.8,
-			},
-		},
-		EstimatedCost: req.Budget,
-		CreatedAt:     time.Now(),
+		// Cache the response for 1 hour
+		if h.cache != nil {
+			if err := h.cache.SetJSON(ctx, cacheKey, response, time.Hour); err != nil {
+				log.Printf("Warning: Failed to cache travel data: %v", err)
+			}
+		}
 	}
+	response.EstimatedCost = req.Budget
+	response.CreatedAt = time.Now()
 
-	// Cache the response for 1 hour
-	if responseJSON, err := json.Marshal(response); err == nil {
-		h.redis.Set(cacheKey, responseJSON, time.Hour)
-	}
+	// Every search is stored, cached or not, so it shows up in history and can be replayed
+	h.storeSearch(ctx, &req, &response)
 
 	return c.JSON(response)
 }
 
-// GetSearchHistory retrieves user's search history
-func (h *TravelHandler) GetSearchHistory(c *fiber.Ctx) error {
-	userID := c.Query("userId")
-	if userID == "" {
+// travelCacheKey keys a search's cached response on everything its summary and recommendations
+// are built from, so a search with other dates, budget or preferences is not answered from another's
+func travelCacheKey(req *models.TravelSearchRequest) string {
+	// Maps marshal with sorted keys, so equal preferences give equal keys
+	preferences, err := json.Marshal(req.Preferences)
+	if err != nil {
+		preferences = []byte(fmt.Sprint(req.Preferences))
+	}
+	return fmt.Sprintf("travel:%s:%s:%s:%g:%s:%s",
+		strings.ToLower(strings.TrimSpace(req.Destination)), req.StartDate, req.EndDate, req.Budget,
+		strings.ToUpper(req.Currency), preferences)
+}
+
+// GetSearch handles GET /api/v1/travel/search/:id requests, replaying a past search with its
+// recommendations. Only the signed-in user who made a search can replay it.
+func (h *TravelHandler) GetSearch(c *fiber.Ctx) error {
+	id, err := positiveParam(c, "id")
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
 			Error:   "Validation error",
-			Message: "userId is required",
+			Message: err.Error(),
 			Code:    fiber.StatusBadRequest,
 		})
 	}
 
-	query := `
-		SELECT id, user_id, destination, start_date, end_date, budget, 
-		       preferences, results, created_at, updated_at
-		FROM travel_searches
-		WHERE user_id = $1
-		ORDER BY created_at DESC
-		LIMIT 20
-	`
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	rows, err := h.db.DB.Query(query, userID)
+	search, err := h.searches.GetSearch(ctx, int(id))
+	if err != nil {
+		log.Printf("Failed to load search %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve search",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+	// Search IDs are sequential, so anonymous searches would be readable by anyone stepping through them
+	user := auth.CurrentUser(c)
+	if search == nil || search.UserID == "" || user == nil || user.ID != search.UserID {
+		return c.Status(fiber.StatusNotFound).JSON(models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("search %d not found", id),
+			Code:    fiber.StatusNotFound,
+		})
+	}
+
+	recommendations, err := h.recommendations.ListRecommendations(ctx, search.ID)
+	if err != nil {
+		log.Printf("Failed to load recommendations of search %d: %v", id, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve search",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(models.TravelSearchResponse{
+		SearchID:        search.ID,
+		Destination:     search.Destination,
+		Summary:         search.Summary,
+		Recommendations: recommendations,
+		Weather:         search.Weather,
+		EstimatedCost:   search.Budget,
+		CreatedAt:       search.CreatedAt,
+	})
+}
+
+// GetSearchHistory retrieves the signed-in user's search history
+func (h *TravelHandler) GetSearchHistory(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stored, err := h.searches.ListSearches(ctx, user.ID, 20)
 	if err != nil {
 		log.Printf("Database error: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
@@ -166,39 +248,25 @@ func (h *TravelHandler) GetSearchHistory(c *fiber.Ctx) error {
 			Code:    fiber.StatusInternalServerError,
 		})
 	}
-	defer rows.Close()
-
-	searches := []models.TravelSearch{}
-	for rows.Next() {
-		var search models.TravelSearch
-		var preferencesJSON, resultsJSON []byte
-
-		err := rows.Scan(
-			&search.ID,
-			&search.UserID,
-			&search.Destination,
-			&search.StartDate,
-			&search.EndDate,
-			&search.Budget,
-			&preferencesJSON,
-			&resultsJSON,
-			&search.CreatedAt,
-			&search.UpdatedAt,
-		)
-		if err != nil {
-			log.Printf("Row scan error: %v", err)
-			continue
-		}
 
-		// Parse JSON fields
-		if len(preferencesJSON) > 0 {
-			json.Unmarshal(preferencesJSON, &search.Preferences)
+	searches := make([]models.TravelSearch, 0, len(stored))
+	for _, search := range stored {
+		results := map[string]interface{}{"recommendations": search.Summary}
+		if search.Weather != nil {
+			results["weather"] = search.Weather
 		}
-		if len(resultsJSON) > 0 {
-			json.Unmarshal(resultsJSON, &search.Results)
-		}
-
-		searches = append(searches, search)
+		searches = append(searches, models.TravelSearch{
+			ID:          search.ID,
+			UserID:      search.UserID,
+			Destination: search.Destination,
+			StartDate:   search.StartDate,
+			EndDate:     search.EndDate,
+			Budget:      search.Budget,
+			Preferences: search.Preferences,
+			Results:     results,
+			CreatedAt:   search.CreatedAt,
+			UpdatedAt:   search.UpdatedAt,
+		})
 	}
 
 	return c.JSON(searches)
@@ -209,14 +277,18 @@ func (h *TravelHandler) HealthCheck(c *fiber.Ctx) error {
 	services := make(map[string]string)
 
 	// Check database
-	if err := h.db.HealthCheck(); err != nil {
+	if h.database == nil {
+		services["database"] = "not configured"
+	} else if err := h.database.HealthCheck(); err != nil {
 		services["database"] = "unhealthy: " + err.Error()
 	} else {
 		services["database"] = "healthy"
 	}
 
 	// Check Redis
-	if err := h.redis.HealthCheck(); err != nil {
+	if h.redis == nil {
+		services["redis"] = "not configured"
+	} else if err := h.redis.HealthCheck(); err != nil {
 		services["redis"] = "unhealthy: " + err.Error()
 	} else {
 		services["redis"] = "healthy"
@@ -264,34 +336,257 @@ func (h *TravelHandler) HealthCheck(c *fiber.Ctx) error {
 	return c.JSON(response)
 }
 
-// storeSearch stores a travel search in the database
-func (h *TravelHandler) storeSearch(req *models.TravelSearchRequest, results string) (int, error) {
-	var searchID int
+// storeSearch stores a travel search and its recommendations, filling in the search and
+// recommendation IDs. A search that cannot be stored is still answered, just without IDs.
+func (h *TravelHandler) storeSearch(ctx context.Context, req *models.TravelSearchRequest, response *models.TravelSearchResponse) {
+	search := storage.Search{
+		UserID:      req.UserID,
+		Destination: req.Destination,
+		StartDate:   parseSearchDate(req.StartDate),
+		EndDate:     parseSearchDate(req.EndDate),
+		Budget:      req.Budget,
+		Preferences: req.Preferences,
+		Summary:     response.Summary,
+		Weather:     response.Weather,
+	}
+	if err := h.searches.CreateSearch(ctx, &search); err != nil {
+		log.Printf("Warning: Failed to store search: %v", err)
+		return
+	}
+	response.SearchID = search.ID
+	response.CreatedAt = search.CreatedAt
+
+	if err := h.recommendations.SaveRecommendations(ctx, search.ID, response.Recommendations); err != nil {
+		log.Printf("Warning: Failed to store recommendations of search %d: %v", search.ID, err)
+	}
+}
+
+// Recommendations per source in a travel search
+const recommendationsPerSource = 3
+
+// buildRecommendations collects hotels from the hotel agent, top-rated attractions from the social
+// service and places matching the traveller's preferences from the local agent. The sources are
+// asked concurrently; one that fails or is not configured adds nothing.
+func (h *TravelHandler) buildRecommendations(ctx context.Context, req *models.TravelSearchRequest) []models.TravelRecommendation {
+	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	var hotels, attractions, places []models.TravelRecommendation
+	var wg sync.WaitGroup
+	if h.hotels != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			hotels = h.hotelRecommendations(ctx, req)
+		}()
+	}
+	if h.social != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attractions = h.attractionRecommendations(ctx, req)
+		}()
+	}
+	if h.local != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			places = h.localRecommendations(ctx, req)
+		}()
+	}
+	wg.Wait()
+
+	recommendations := make([]models.TravelRecommendation, 0, len(hotels)+len(attractions)+len(places))
+	recommendations = append(recommendations, hotels...)
+	recommendations = append(recommendations, attractions...)
+	return append(recommendations, places...)
+}
+
+// hotelRecommendations finds hotels for the search dates, preferring ones within the hotel share
+// of the budget
+func (h *TravelHandler) hotelRecommendations(ctx context.Context, req *models.TravelSearchRequest) []models.TravelRecommendation {
+	search := agents.HotelSearchRequest{
+		City:     req.Destination,
+		Currency: strings.ToUpper(req.Currency),
+		Limit:    recommendationsPerSource,
+	}
+	if checkIn := parseSearchDate(req.StartDate); checkIn != nil {
+		search.CheckIn = *checkIn
+	}
+	if checkOut := parseSearchDate(req.EndDate); checkOut != nil {
+		search.CheckOut = *checkOut
+	}
+	search.BudgetPerNight = hotelBudgetPerNight(req.Budget, search)
+
+	hotels, err := h.hotels.Search(ctx, search)
+	if err != nil {
+		log.Printf("Warning: Failed to find hotels: %v", err)
+		return nil
+	}
+
+	recommendations := make([]models.TravelRecommendation, 0, len(hotels))
+	for _, hotel := range hotels {
+		description := "Hotel"
+		if hotel.Stars > 0 {
+			description = fmt.Sprintf("%d-star hotel", hotel.Stars)
+		}
+		if hotel.Neighborhood != "" {
+			description += " in " + hotel.Neighborhood
+		}
+		if hotel.Distance > 0 {
+			description += fmt.Sprintf(", %.1f km from the center", hotel.Distance)
+		}
+
+		recommendations = append(recommendations, models.TravelRecommendation{
+			Type:        "hotel",
+			Title:       hotel.Name,
+			Description: description,
+			Price:       hotel.PricePerNight,
+			Rating:      hotel.Rating,
+			Location:    hotel.Address,
+			Metadata: map[string]interface{}{
+				"currency":    hotel.Currency,
+				"nights":      hotel.Nights,
+				"total_price": hotel.TotalPrice,
+				"room_type":   hotel.RoomType,
+				"amenities":   hotel.Amenities,
+				"refundable":  hotel.Refundable,
+				"source":      hotel.Source,
+			},
+		})
+	}
+	return recommendations
+}
 
-	preferencesJSON, _ := json.Marshal(req.Preferences)
-	resultsMap := map[string]interface{}{
-		"recommendations": results,
+// hotelBudgetPerNight is the hotel share of a search budget per night, split the way trip budgets
+// are from the destination and the nights searched (0 without a budget)
+func hotelBudgetPerNight(budget float64, search agents.HotelSearchRequest) float64 {
+	if budget <= 0 {
+		return 0
 	}
-	resultsJSON, _ := json.Marshal(resultsMap)
+	stay := search.WithDefaults()
+	nights := stay.Nights()
+	estimate := agents.EstimateTripBudget(agents.BudgetRequest{
+		Destination: search.City,
+		Days:        nights + 1,
+		Travelers:   stay.Guests,
+		Total:       budget,
+	})
+	return float64(estimate.BudgetPlan().Hotel) / float64(nights)
+}
 
-	query := `
-		INSERT INTO travel_searches (user_id, destination, budget, preferences, results)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id
-	`
+// attractionRecommendations lists the destination's top-rated attractions
+func (h *TravelHandler) attractionRecommendations(ctx context.Context, req *models.TravelSearchRequest) []models.TravelRecommendation {
+	places, err := h.social.GetTopRatedPlaces(ctx, "tourist attractions", req.Destination, recommendationsPerSource)
+	if err != nil {
+		log.Printf("Warning: Failed to find attractions: %v", err)
+		return nil
+	}
 
-	err := h.db.DB.QueryRow(
-		query,
-		req.UserID,
-		req.Destination,
-		req.Budget,
-		preferencesJSON,
-		resultsJSON,
-	).Scan(&searchID)
+	recommendations := make([]models.TravelRecommendation, 0, len(places))
+	for _, place := range places {
+		recommendation := models.TravelRecommendation{
+			Type:        "attraction",
+			Title:       place.Name,
+			Description: fmt.Sprintf("Rated %.1f from %d reviews", place.Rating, place.ReviewCount),
+			Rating:      place.Rating,
+			Location:    place.Address,
+			ImageURL:    place.PhotoURL,
+			Metadata: map[string]interface{}{
+				"place_id":     place.PlaceID,
+				"review_count": place.ReviewCount,
+				"types":        place.Types,
+				"latitude":     place.Latitude,
+				"longitude":    place.Longitude,
+			},
+		}
+		if place.PlaceID != "" {
+			recommendation.URL = "https://www.google.com/maps/place/?q=place_id:" + place.PlaceID
+		}
+		recommendations = append(recommendations, recommendation)
+	}
+	return recommendations
+}
 
+// localRecommendations finds places near the destination's center for the traveller's interest
+func (h *TravelHandler) localRecommendations(ctx context.Context, req *models.TravelSearchRequest) []models.TravelRecommendation {
+	lat, lng, ok := airports.Coordinates(req.Destination)
+	if !ok {
+		log.Printf("Warning: No coordinates for %s, skipping local recommendations", req.Destination)
+		return nil
+	}
+
+	interest := searchInterest(req.Preferences)
+	places, err := h.local.GetRecommendations(ctx, lat, lng, interest)
 	if err != nil {
-		return 0, err
+		log.Printf("Warning: Failed to find local places: %v", err)
+		return nil
+	}
+	if len(places) > recommendationsPerSource {
+		places = places[:recommendationsPerSource]
 	}
 
-	return searchID, nil
+	recommendations := make([]models.TravelRecommendation, 0, len(places))
+	for _, place := range places {
+		placeType := place.Type
+		if placeType == "" {
+			placeType = interest
+		}
+		recommendations = append(recommendations, models.TravelRecommendation{
+			Type:        placeType,
+			Title:       place.Name,
+			Description: fmt.Sprintf("%.1f km from the center of %s", place.DistanceKm, req.Destination),
+			Rating:      place.Rating,
+			Location:    place.Address,
+			Metadata: map[string]interface{}{
+				"interest":    interest,
+				"distance_km": place.DistanceKm,
+			},
+		})
+	}
+	return recommendations
+}
+
+// searchInterests maps the search form's preference switches to what the local agent looks for,
+// in the order they are tried
+var searchInterests = []struct {
+	preference string
+	interest   string
+}{
+	{"food", "restaurant"},
+	{"culture", "museum"},
+	{"adventure", "outdoor activity"},
+	{"relaxation", "spa"},
+}
+
+// searchInterest picks what local places to look for: an explicit interests preference, else the
+// first preference switched on, else restaurants
+func searchInterest(preferences map[string]interface{}) string {
+	switch interests := preferences["interests"].(type) {
+	case string:
+		if strings.TrimSpace(interests) != "" {
+			return strings.TrimSpace(interests)
+		}
+	case []interface{}:
+		for _, interest := range interests {
+			if s, ok := interest.(string); ok && strings.TrimSpace(s) != "" {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	for _, option := range searchInterests {
+		if enabled, _ := preferences[option.preference].(bool); enabled {
+			return option.interest
+		}
+	}
+	return "restaurant"
+}
+
+// parseSearchDate parses a YYYY-MM-DD search date; anything else is no date
+func parseSearchDate(value string) *time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil
+	}
+	return &date
 }
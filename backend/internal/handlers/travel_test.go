@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTravelHandler_SearchHistoryAndReplay(t *testing.T) {
+	repositories := storage.NewMemoryRepositories()
+	handler := NewTravelHandler(repositories.Searches, repositories.Recommendations, cache.NewMemoryCache(), nil, nil, nil)
+	handler.SetRecommenders(agents.NewHotelAgent("", ""), agents.NewLocalAgent(""), nil)
+	tokens := auth.NewTokenManager("test-secret", time.Hour, 0)
+
+	app := fiber.New()
+	app.Post("/api/v1/travel/search", auth.OptionalMiddleware(tokens), handler.SearchTravel)
+	app.Get("/api/v1/travel/search/:id", auth.OptionalMiddleware(tokens), handler.GetSearch)
+	app.Get("/api/v1/travel/history", auth.Middleware(tokens), handler.GetSearchHistory)
+	app.Get("/health", handler.HealthCheck)
+
+	token := accessToken(t, tokens, "user-1")
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+	search := func(req models.TravelSearchRequest) models.TravelSearchResponse {
+		status, body := do("POST", "/api/v1/travel/search", req)
+		require.Equal(t, fiber.StatusOK, status, string(body))
+		var response models.TravelSearchResponse
+		require.NoError(t, json.Unmarshal(body, &response))
+		return response
+	}
+
+	first := search(models.TravelSearchRequest{Destination: "Tokyo", StartDate: "2026-12-01", EndDate: "2026-12-04", Budget: 60000})
+	assert.NotZero(t, first.SearchID)
+	require.NotEmpty(t, first.Recommendations)
+	for _, rec := range first.Recommendations {
+		assert.NotZero(t, rec.ID, "Recommendations are stored with the search")
+	}
+
+	// A replay returns the stored search as it was answered
+	status, body := do("GET", fmt.Sprintf("/api/v1/travel/search/%d", first.SearchID), nil)
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	var replay models.TravelSearchResponse
+	require.NoError(t, json.Unmarshal(body, &replay))
+	assert.Equal(t, first.SearchID, replay.SearchID)
+	assert.Equal(t, 60000.0, replay.EstimatedCost)
+	assert.Equal(t, first.Recommendations, replay.Recommendations)
+
+	// Searching again comes from the cache but is still stored as its own search
+	second := search(models.TravelSearchRequest{Destination: "Tokyo", StartDate: "2026-12-01", EndDate: "2026-12-04", Budget: 60000})
+	assert.NotEqual(t, first.SearchID, second.SearchID)
+	assert.Equal(t, 60000.0, second.EstimatedCost)
+	assert.Len(t, second.Recommendations, len(first.Recommendations))
+
+	status, body = do("GET", "/api/v1/travel/history", nil)
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	var history []models.TravelSearch
+	require.NoError(t, json.Unmarshal(body, &history))
+	require.Len(t, history, 2)
+	assert.Equal(t, second.SearchID, history[0].ID, "Newest first")
+	require.NotNil(t, history[1].StartDate)
+	assert.Equal(t, "2026-12-01", history[1].StartDate.Format("2006-01-02"))
+
+	// Searches can only be replayed by the user who made them; anonymous searches not at all
+	token = ""
+	anonymous := search(models.TravelSearchRequest{Destination: "Osaka"})
+	status, _ = do("GET", fmt.Sprintf("/api/v1/travel/search/%d", anonymous.SearchID), nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, _ = do("GET", fmt.Sprintf("/api/v1/travel/search/%d", first.SearchID), nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+
+	token = accessToken(t, tokens, "user-2")
+	status, _ = do("GET", fmt.Sprintf("/api/v1/travel/search/%d", first.SearchID), nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, _ = do("GET", "/api/v1/travel/search/999", nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, body = do("GET", "/api/v1/travel/history", nil)
+	require.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, "[]", string(body))
+
+	status, body = do("GET", "/health", nil)
+	require.Equal(t, fiber.StatusOK, status)
+	assert.Contains(t, string(body), `"database":"not configured"`)
+}
+
+func TestTravelCacheKey(t *testing.T) {
+	base := models.TravelSearchRequest{
+		Destination: "Tokyo",
+		StartDate:   "2026-12-01",
+		EndDate:     "2026-12-04",
+		Budget:      60000,
+		Currency:    "THB",
+		Preferences: map[string]interface{}{"food": true, "pace": "slow"},
+	}
+	key := travelCacheKey(&base)
+
+	same := base
+	same.Destination = " tokyo"
+	same.Currency = "thb"
+	same.Preferences = map[string]interface{}{"pace": "slow", "food": true}
+	assert.Equal(t, key, travelCacheKey(&same))
+
+	for name, change := range map[string]func(*models.TravelSearchRequest){
+		"destination": func(r *models.TravelSearchRequest) { r.Destination = "Osaka" },
+		"start date":  func(r *models.TravelSearchRequest) { r.StartDate = "2026-12-02" },
+		"end date":    func(r *models.TravelSearchRequest) { r.EndDate = "2026-12-05" },
+		"budget":      func(r *models.TravelSearchRequest) { r.Budget = 30000 },
+		"currency":    func(r *models.TravelSearchRequest) { r.Currency = "USD" },
+		"preferences": func(r *models.TravelSearchRequest) { r.Preferences = map[string]interface{}{"food": false} },
+	} {
+		other := base
+		change(&other)
+		assert.NotEqual(t, key, travelCacheKey(&other), name)
+	}
+}
+
+func TestTravelHandler_BuildRecommendations(t *testing.T) {
+	handler := NewTravelHandler(nil, nil, nil, nil, nil, nil)
+	assert.Empty(t, handler.buildRecommendations(context.Background(), &models.TravelSearchRequest{Destination: "Tokyo"}),
+		"Without sources there is nothing to recommend")
+
+	handler.SetRecommenders(agents.NewHotelAgent("", ""), agents.NewLocalAgent(""), nil)
+	recommendations := handler.buildRecommendations(context.Background(), &models.TravelSearchRequest{
+		Destination: "Tokyo",
+		StartDate:   "2026-12-01",
+		EndDate:     "2026-12-04",
+		Budget:      60000,
+		Preferences: map[string]interface{}{"culture": true},
+	})
+
+	var hotels, places []models.TravelRecommendation
+	for _, rec := range recommendations {
+		assert.NotEmpty(t, rec.Title)
+		assert.NotContains(t, rec.Title, "Luxury Hotel Recommendation")
+		if rec.Type == "hotel" {
+			hotels = append(hotels, rec)
+		} else {
+			places = append(places, rec)
+		}
+	}
+	require.NotEmpty(t, hotels)
+	assert.LessOrEqual(t, len(hotels), recommendationsPerSource)
+	assert.Greater(t, hotels[0].Price, 0.0)
+	assert.Equal(t, "THB", hotels[0].Metadata["currency"])
+	assert.Equal(t, 3, hotels[0].Metadata["nights"])
+	assert.Equal(t, "hotel", recommendations[0].Type, "Hotels come first")
+
+	require.NotEmpty(t, places)
+	assert.Equal(t, "museum", places[0].Metadata["interest"])
+	assert.Contains(t, places[0].Description, "from the center of Tokyo")
+
+	// Places are only looked up around destinations with known coordinates
+	handler.SetRecommenders(nil, agents.NewLocalAgent(""), nil)
+	assert.Empty(t, handler.buildRecommendations(context.Background(), &models.TravelSearchRequest{Destination: "Nowhere Town"}))
+}
+
+func TestHotelBudgetPerNight(t *testing.T) {
+	search := agents.HotelSearchRequest{
+		City:     "Tokyo",
+		CheckIn:  time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+		CheckOut: time.Date(2026, 12, 4, 0, 0, 0, 0, time.UTC),
+	}
+	estimate := agents.EstimateTripBudget(agents.BudgetRequest{Destination: "Tokyo", Days: 4, Travelers: 2, Total: 60000})
+	assert.Equal(t, float64(estimate.BudgetPlan().Hotel)/3, hotelBudgetPerNight(60000, search),
+		"Searches split the budget the same way as trip budgets")
+	assert.Zero(t, hotelBudgetPerNight(0, search))
+}
+
+func TestSearchInterest(t *testing.T) {
+	assert.Equal(t, "restaurant", searchInterest(nil))
+	assert.Equal(t, "spa", searchInterest(map[string]interface{}{"food": false, "relaxation": true}))
+	assert.Equal(t, "restaurant", searchInterest(map[string]interface{}{"food": true, "culture": true}))
+	assert.Equal(t, "jazz bar", searchInterest(map[string]interface{}{"interests": " jazz bar ", "food": true}))
+	assert.Equal(t, "ramen", searchInterest(map[string]interface{}{"interests": []interface{}{"", "ramen"}}))
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/orchestrator"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+)
+
+// TripHandler handles saved trip HTTP requests
+type TripHandler struct {
+	store        storage.TripRepository
+	orchestrator *orchestrator.Orchestrator
+}
+
+// NewTripHandler creates a new trip handler instance. Plans are saved from the orchestrator's
+// conversations when it is given.
+func NewTripHandler(store storage.TripRepository, orch *orchestrator.Orchestrator) *TripHandler {
+	return &TripHandler{
+		store:        store,
+		orchestrator: orch,
+	}
+}
+
+// ListTrips handles GET /api/v1/trips requests, listing the signed-in user's trips
+func (h *TripHandler) ListTrips(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	trips, err := h.store.ListTrips(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to list trips: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve trips",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(trips)
+}
+
+// CreateTrip handles POST /api/v1/trips requests
+func (h *TripHandler) CreateTrip(c *fiber.Ctx) error {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return unauthorized(c)
+	}
+
+	var req models.TripRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	trip, err := h.parseTrip(ctx, user, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	trip.UserID = user.ID
+
+	if err := h.store.CreateTrip(ctx, &trip); err != nil {
+		log.Printf("Failed to create trip: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to save trip",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(trip)
+}
+
+// GetTrip handles GET /api/v1/trips/:id requests
+func (h *TripHandler) GetTrip(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	trip, errResp := h.ownedTrip(ctx, c)
+	if errResp != nil {
+		return c.Status(errResp.Code).JSON(errResp)
+	}
+
+	return c.JSON(trip)
+}
+
+// UpdateTrip handles PUT /api/v1/trips/:id requests, replacing the trip's details and itinerary.
+// With session_id the conversation's latest plan, e.g. after asking to change the saved trip, is
+// saved over it.
+func (h *TripHandler) UpdateTrip(c *fiber.Ctx) error {
+	var req models.TripRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	existing, errResp := h.ownedTrip(ctx, c)
+	if errResp != nil {
+		return c.Status(errResp.Code).JSON(errResp)
+	}
+
+	trip, err := h.parseTrip(ctx, auth.CurrentUser(c), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	trip.ID = existing.ID
+	if req.Title == "" && existing.Title != "" {
+		trip.Title = existing.Title
+	}
+	if req.StartDate == "" {
+		trip.StartDate = existing.StartDate
+	}
+
+	if err := h.store.UpdateTrip(ctx, &trip); err != nil {
+		log.Printf("Failed to update trip %d: %v", trip.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to update trip",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(trip)
+}
+
+// DeleteTrip handles DELETE /api/v1/trips/:id requests
+func (h *TripHandler) DeleteTrip(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	trip, errResp := h.ownedTrip(ctx, c)
+	if errResp != nil {
+		return c.Status(errResp.Code).JSON(errResp)
+	}
+
+	if err := h.store.DeleteTrip(ctx, trip.ID); err != nil {
+		log.Printf("Failed to delete trip %d: %v", trip.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to delete trip",
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RequireOwner only lets the signed-in owner of the trip in the :id parameter through, for routes
+// such as the expense ledger that hang off a trip
+func (h *TripHandler) RequireOwner(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, errResp := h.ownedTrip(ctx, c); errResp != nil {
+		return c.Status(errResp.Code).JSON(errResp)
+	}
+	return c.Next()
+}
+
+// ownedTrip loads the trip in the :id parameter if it belongs to the signed-in user. Other users'
+// trips are reported as missing so IDs cannot be probed.
+func (h *TripHandler) ownedTrip(ctx context.Context, c *fiber.Ctx) (*agents.Trip, *models.ErrorResponse) {
+	user := auth.CurrentUser(c)
+	if user == nil {
+		return nil, &models.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "Sign in to access this resource",
+			Code:    fiber.StatusUnauthorized,
+		}
+	}
+
+	id, err := positiveParam(c, "id")
+	if err != nil {
+		return nil, &models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		}
+	}
+
+	return findOwnedTrip(ctx, h.store, user, id)
+}
+
+// findOwnedTrip loads a trip if it belongs to the user
+func findOwnedTrip(ctx context.Context, store storage.TripRepository, user *auth.User, id int64) (*agents.Trip, *models.ErrorResponse) {
+	trip, err := store.GetTrip(ctx, id)
+	if err != nil {
+		log.Printf("Failed to get trip %d: %v", id, err)
+		return nil, &models.ErrorResponse{
+			Error:   "Database error",
+			Message: "Failed to retrieve trip",
+			Code:    fiber.StatusInternalServerError,
+		}
+	}
+	if trip == nil || trip.UserID != user.ID {
+		return nil, &models.ErrorResponse{
+			Error:   "Not found",
+			Message: fmt.Sprintf("trip %d not found", id),
+			Code:    fiber.StatusNotFound,
+		}
+	}
+	return trip, nil
+}
+
+// parseTrip validates a trip request, taking the itinerary from the conversation when it names one
+// of the user's
+func (h *TripHandler) parseTrip(ctx context.Context, user *auth.User, req models.TripRequest) (agents.Trip, error) {
+	var trip agents.Trip
+	if req.SessionID != "" {
+		var plan *agents.TripPlan
+		if h.orchestrator != nil {
+			plan = h.orchestrator.SessionPlan(orchestrator.WithUser(ctx, user.ID), req.SessionID)
+		}
+		if plan == nil {
+			return trip, fmt.Errorf("session %s has no plan to save", req.SessionID)
+		}
+		trip = agents.NewTripFromPlan(plan)
+	} else {
+		trip = agents.Trip{
+			Destination: strings.TrimSpace(req.Destination),
+			Duration:    req.DurationDays,
+			Budget:      req.Budget,
+			Summary:     strings.TrimSpace(req.Summary),
+		}
+		seen := make(map[int]bool, len(req.Itinerary))
+		for _, day := range req.Itinerary {
+			if day.Day <= 0 {
+				return trip, fmt.Errorf("itinerary days must be numbered from 1")
+			}
+			if seen[day.Day] {
+				return trip, fmt.Errorf("day %d is listed twice in the itinerary", day.Day)
+			}
+			if day.Budget < 0 {
+				return trip, fmt.Errorf("day budgets must not be negative")
+			}
+			seen[day.Day] = true
+
+			tripDay := agents.TripDay{Day: day.Day, Budget: day.Budget}
+			for _, activity := range append(day.Activities, day.Activity) {
+				tripDay.Activities = append(tripDay.Activities, agents.TripActivity{Description: activity})
+			}
+			trip.Days = append(trip.Days, tripDay)
+		}
+	}
+
+	if trip.Destination == "" {
+		return trip, fmt.Errorf("destination is required")
+	}
+	if trip.Duration < 0 {
+		return trip, fmt.Errorf("duration_days must not be negative")
+	}
+	if trip.Budget < 0 {
+		return trip, fmt.Errorf("budget must not be negative")
+	}
+
+	trip.Title = req.Title
+	if req.StartDate != "" {
+		date, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return trip, fmt.Errorf("start_date must be YYYY-MM-DD")
+		}
+		trip.StartDate = &date
+	}
+	trip.Normalize()
+	return trip, nil
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/auth"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/orchestrator"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTripHandler(t *testing.T) {
+	store := storage.NewMemoryTripRepository()
+	orch := orchestrator.New("", "", "", "")
+	orch.SetSessionStore(orchestrator.NewMemorySessionStore())
+	handler := NewTripHandler(store, orch)
+	planHandler := NewPlanHandler(nil, orch)
+	planHandler.SetTripRepository(store)
+	tokens := auth.NewTokenManager("test-secret", time.Hour, 0)
+
+	app := fiber.New()
+	app.Post("/api/plan", auth.OptionalMiddleware(tokens), planHandler.CreateTravelPlan)
+	app.Use("/api/v1/trips", auth.Middleware(tokens))
+	app.Get("/api/v1/trips", handler.ListTrips)
+	app.Post("/api/v1/trips", handler.CreateTrip)
+	app.Get("/api/v1/trips/:id", handler.GetTrip)
+	app.Put("/api/v1/trips/:id", handler.UpdateTrip)
+	app.Delete("/api/v1/trips/:id", handler.DeleteTrip)
+	app.Get("/api/v1/trips/:id/budget", handler.RequireOwner, func(c *fiber.Ctx) error {
+		return c.SendString("ledger")
+	})
+
+	token := accessToken(t, tokens, "user-1")
+	do := func(method, target string, body interface{}) (int, []byte) {
+		var reader io.Reader
+		if body != nil {
+			payload, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(payload)
+		}
+		req := httptest.NewRequest(method, target, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := app.Test(req, 10000)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	// A trip given directly
+	status, body := do("POST", "/api/v1/trips", models.TripRequest{
+		Destination: "Kyoto",
+		StartDate:   "2026-11-20",
+		Budget:      45000,
+		Itinerary: []models.TripDayRequest{
+			{Day: 2, Activities: []string{"Arashiyama", "Tenryu-ji"}},
+			{Day: 1, Activity: "Fushimi Inari"},
+		},
+	})
+	require.Equal(t, fiber.StatusCreated, status, string(body))
+	var trip agents.Trip
+	require.NoError(t, json.Unmarshal(body, &trip))
+	assert.Equal(t, "Trip to Kyoto", trip.Title)
+	assert.Equal(t, "user-1", trip.UserID)
+	assert.Equal(t, 2, trip.Duration)
+	require.NotNil(t, trip.StartDate)
+	assert.Equal(t, "2026-11-20", trip.StartDate.Format("2006-01-02"))
+	require.Len(t, trip.Days, 2)
+	assert.Equal(t, "Fushimi Inari", trip.Days[0].Activities[0].Description)
+	assert.Equal(t, 2, trip.Days[1].Activities[1].Position)
+	tripPath := fmt.Sprintf("/api/v1/trips/%d", trip.ID)
+
+	for _, invalid := range []models.TripRequest{
+		{},
+		{Destination: "Kyoto", StartDate: "20/11/2026"},
+		{Destination: "Kyoto", Budget: -1},
+		{Destination: "Kyoto", Itinerary: []models.TripDayRequest{{Day: 0}}},
+		{Destination: "Kyoto", Itinerary: []models.TripDayRequest{{Day: 1}, {Day: 1}}},
+		{SessionID: "no-such-session"},
+	} {
+		status, body = do("POST", "/api/v1/trips", invalid)
+		assert.Equal(t, fiber.StatusBadRequest, status, string(body))
+	}
+
+	// A trip saved from a conversation
+	status, body = do("POST", "/api/plan", fiber.Map{"message": "Plan a trip to Tokyo for 4 days with 40000 baht", "session_id": "tokyo"})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	status, body = do("POST", "/api/v1/trips", models.TripRequest{SessionID: "tokyo", Title: "Tokyo food trip"})
+	require.Equal(t, fiber.StatusCreated, status, string(body))
+	var saved agents.Trip
+	require.NoError(t, json.Unmarshal(body, &saved))
+	assert.Equal(t, "Tokyo food trip", saved.Title)
+	assert.Equal(t, "Tokyo", saved.Destination)
+	assert.Equal(t, 4, saved.Duration)
+	assert.Equal(t, 40000.0, saved.Budget)
+	assert.Len(t, saved.Days, 4)
+
+	status, body = do("GET", "/api/v1/trips", nil)
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	var trips []agents.Trip
+	require.NoError(t, json.Unmarshal(body, &trips))
+	assert.Len(t, trips, 2)
+
+	// Talking about the saved trip in a new conversation, then saving the update back
+	status, body = do("POST", "/api/plan", fiber.Map{"message": "What's the weather like?", "trip_id": trip.ID, "format": "json"})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	assert.Contains(t, string(body), `"intent":"weather_check"`)
+	assert.Contains(t, string(body), `"city":"Kyoto"`)
+
+	status, body = do("POST", "/api/plan", fiber.Map{"message": "Change day 2 to a cooking class", "trip_id": trip.ID, "session_id": "kyoto"})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	status, body = do("PUT", tripPath, models.TripRequest{SessionID: "kyoto"})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	var updated agents.Trip
+	require.NoError(t, json.Unmarshal(body, &updated))
+	assert.Equal(t, trip.ID, updated.ID)
+	assert.Equal(t, "Trip to Kyoto", updated.Title)
+	assert.Equal(t, trip.StartDate.Unix(), updated.StartDate.Unix(), "The start date is kept")
+	assert.Equal(t, "Kyoto", updated.Destination)
+
+	status, body = do("PUT", tripPath, models.TripRequest{Title: "Autumn in Kyoto", Destination: "Kyoto"})
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	status, body = do("GET", tripPath, nil)
+	require.Equal(t, fiber.StatusOK, status, string(body))
+	require.NoError(t, json.Unmarshal(body, &updated))
+	assert.Equal(t, "Autumn in Kyoto", updated.Title)
+	assert.Empty(t, updated.Days, "Updates replace the itinerary")
+
+	status, body = do("GET", tripPath+"/budget", nil)
+	assert.Equal(t, fiber.StatusOK, status, string(body))
+
+	// Other users cannot see, use or change the trip
+	token = accessToken(t, tokens, "user-2")
+	for _, target := range []string{tripPath, tripPath + "/budget"} {
+		status, body = do("GET", target, nil)
+		assert.Equal(t, fiber.StatusNotFound, status, string(body))
+	}
+	status, _ = do("PUT", tripPath, models.TripRequest{Destination: "Paris"})
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, _ = do("DELETE", tripPath, nil)
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, _ = do("POST", "/api/plan", fiber.Map{"message": "What's the weather like?", "trip_id": trip.ID})
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, body = do("GET", "/api/v1/trips", nil)
+	require.Equal(t, fiber.StatusOK, status)
+	assert.JSONEq(t, "[]", string(body))
+
+	// Nor continue or save user-1's conversations
+	status, _ = do("POST", "/api/plan", fiber.Map{"message": "make it 5 days instead", "session_id": "tokyo"})
+	assert.Equal(t, fiber.StatusNotFound, status)
+	status, body = do("POST", "/api/v1/trips", models.TripRequest{SessionID: "tokyo"})
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Contains(t, string(body), "session tokyo has no plan to save")
+
+	token = ""
+	status, _ = do("POST", "/api/plan", fiber.Map{"message": "What's the weather like?", "trip_id": trip.ID})
+	assert.Equal(t, fiber.StatusUnauthorized, status)
+	status, _ = do("POST", "/api/plan", fiber.Map{"message": "make it 5 days instead", "session_id": "tokyo"})
+	assert.Equal(t, fiber.StatusNotFound, status)
+
+	token = accessToken(t, tokens, "user-1")
+	status, _ = do("DELETE", tripPath, nil)
+	assert.Equal(t, fiber.StatusNoContent, status)
+	got, err := store.GetTrip(context.Background(), trip.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
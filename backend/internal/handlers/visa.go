@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// VisaHandler handles visa requirement HTTP requests
+type VisaHandler struct {
+	visaAgent *agents.VisaDocAgent
+}
+
+// NewVisaHandler creates a new visa handler instance
+func NewVisaHandler(visaAgent *agents.VisaDocAgent) *VisaHandler {
+	return &VisaHandler{
+		visaAgent: visaAgent,
+	}
+}
+
+// CheckVisa handles POST /api/v1/visa requests
+func (h *VisaHandler) CheckVisa(c *fiber.Ctx) error {
+	var req models.VisaRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	// Validate required fields
+	if req.Nationality == "" || req.Destination == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "nationality and destination are required",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+	if req.StayDays < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: "stay_days must not be negative",
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	requirement, err := h.visaAgent.CheckVisa(ctx, req.Nationality, req.Destination, req.StayDays, req.Purpose)
+	if err != nil {
+		log.Printf("Visa check failed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Visa service error",
+			Message: fmt.Sprintf("Failed to check visa requirements: %v", err),
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(requirement)
+}
+
+// CheckItinerary handles POST /api/v1/visa/itinerary requests
+func (h *VisaHandler) CheckItinerary(c *fiber.Ctx) error {
+	var req models.VisaItineraryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Invalid request",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	itinerary, err := parseVisaItinerary(req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(models.ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+			Code:    fiber.StatusBadRequest,
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	check, err := h.visaAgent.CheckItinerary(ctx, itinerary)
+	if err != nil {
+		log.Printf("Visa itinerary check failed: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(models.ErrorResponse{
+			Error:   "Visa service error",
+			Message: fmt.Sprintf("Failed to check visa requirements: %v", err),
+			Code:    fiber.StatusInternalServerError,
+		})
+	}
+
+	return c.JSON(check)
+}
+
+// parseVisaItinerary validates an itinerary request and parses its dates
+func parseVisaItinerary(req models.VisaItineraryRequest) (agents.VisaItinerary, error) {
+	itinerary := agents.VisaItinerary{Nationality: req.Nationality}
+
+	if req.Nationality == "" {
+		return itinerary, fmt.Errorf("nationality is required")
+	}
+	if len(req.Stops) == 0 {
+		return itinerary, fmt.Errorf("at least one stop is required")
+	}
+
+	var err error
+	if itinerary.PassportExpiry, err = parseOptionalDate("passport_expiry", req.PassportExpiry); err != nil {
+		return itinerary, err
+	}
+	if itinerary.ReturnDate, err = parseOptionalDate("return_date", req.ReturnDate); err != nil {
+		return itinerary, err
+	}
+
+	for i, input := range req.Stops {
+		if input.Country == "" || input.EntryDate == "" {
+			return itinerary, fmt.Errorf("stop %d: country and entry_date are required", i+1)
+		}
+		if input.StayDays < 0 {
+			return itinerary, fmt.Errorf("stop %d: stay_days must not be negative", i+1)
+		}
+
+		stop := agents.VisaStop{
+			Country:  input.Country,
+			StayDays: input.StayDays,
+			Purpose:  input.Purpose,
+			Transit:  input.Transit,
+		}
+		if stop.EntryDate, err = time.Parse("2006-01-02", input.EntryDate); err != nil {
+			return itinerary, fmt.Errorf("stop %d: entry_date must be YYYY-MM-DD", i+1)
+		}
+		if input.ExitDate != "" {
+			if stop.ExitDate, err = time.Parse("2006-01-02", input.ExitDate); err != nil {
+				return itinerary, fmt.Errorf("stop %d: exit_date must be YYYY-MM-DD", i+1)
+			}
+		}
+		itinerary.Stops = append(itinerary.Stops, stop)
+	}
+
+	return itinerary, nil
+}
+
+// parseOptionalDate parses a YYYY-MM-DD field that may be empty
+func parseOptionalDate(field, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be YYYY-MM-DD", field)
+	}
+	return &date, nil
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVisaHandler_CheckVisa(t *testing.T) {
+	app := fiber.New()
+	handler := NewVisaHandler(agents.NewVisaDocAgent(""))
+	app.Post("/api/v1/visa", handler.CheckVisa)
+
+	post := func(request models.VisaRequest) (int, []byte) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/v1/visa", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	t.Run("Returns the visa requirement", func(t *testing.T) {
+		status, body := post(models.VisaRequest{Nationality: "TH", Destination: "JP", StayDays: 10})
+		assert.Equal(t, fiber.StatusOK, status)
+
+		var requirement agents.VisaRequirement
+		require.NoError(t, json.Unmarshal(body, &requirement))
+		assert.False(t, requirement.VisaRequired)
+		assert.Equal(t, 15, requirement.MaxStayDays)
+		assert.NotEmpty(t, requirement.Checklist)
+		assert.NotEmpty(t, requirement.Disclaimer)
+	})
+
+	t.Run("Requires nationality and destination", func(t *testing.T) {
+		status, body := post(models.VisaRequest{Destination: "JP"})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+
+		var errorResp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(body, &errorResp))
+		assert.Equal(t, "nationality and destination are required", errorResp.Message)
+	})
+
+	t.Run("Rejects negative stay", func(t *testing.T) {
+		status, _ := post(models.VisaRequest{Nationality: "TH", Destination: "JP", StayDays: -1})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+}
+
+func TestVisaHandler_CheckItinerary(t *testing.T) {
+	app := fiber.New()
+	handler := NewVisaHandler(agents.NewVisaDocAgent(""))
+	app.Post("/api/v1/visa/itinerary", handler.CheckItinerary)
+
+	post := func(request models.VisaItineraryRequest) (int, []byte) {
+		body, err := json.Marshal(request)
+		require.NoError(t, err)
+		req := httptest.NewRequest("POST", "/api/v1/visa/itinerary", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		respBody, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, respBody
+	}
+
+	t.Run("Returns per-country verdicts", func(t *testing.T) {
+		status, body := post(models.VisaItineraryRequest{
+			Nationality: "TH",
+			ReturnDate:  "2025-03-31",
+			Stops: []models.VisaStopInput{
+				{Country: "Japan", EntryDate: "2025-03-01"},
+				{Country: "Canada", EntryDate: "2025-03-20"},
+			},
+		})
+		assert.Equal(t, fiber.StatusOK, status)
+
+		var check agents.ItineraryVisaCheck
+		require.NoError(t, json.Unmarshal(body, &check))
+		require.Len(t, check.Countries, 2)
+		assert.Equal(t, agents.VisaVerdictStayTooLong, check.Countries[0].Verdict)
+		assert.Equal(t, agents.VisaVerdictVisaRequired, check.Countries[1].Verdict)
+		assert.Equal(t, 12, check.Countries[1].StayDays)
+		assert.True(t, check.VisaRequired)
+		assert.NotEmpty(t, check.Warnings)
+	})
+
+	t.Run("Rejects bad dates", func(t *testing.T) {
+		status, body := post(models.VisaItineraryRequest{
+			Nationality: "TH",
+			Stops:       []models.VisaStopInput{{Country: "JP", EntryDate: "01/03/2025"}},
+		})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+
+		var errorResp models.ErrorResponse
+		require.NoError(t, json.Unmarshal(body, &errorResp))
+		assert.Equal(t, "stop 1: entry_date must be YYYY-MM-DD", errorResp.Message)
+	})
+
+	t.Run("Requires stops", func(t *testing.T) {
+		status, _ := post(models.VisaItineraryRequest{Nationality: "TH"})
+		assert.Equal(t, fiber.StatusBadRequest, status)
+	})
+}
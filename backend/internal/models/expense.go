@@ -0,0 +1,58 @@
+package models
+
+// ExpenseRequest records or edits a trip expense. The date uses YYYY-MM-DD and the currency
+// defaults to THB; day is the optional day of the itinerary. On group trips, paid_by names the
+// member who paid and split how the cost is shared, evenly among all members by default.
+type ExpenseRequest struct {
+	Category    string        `json:"category"`
+	Amount      float64       `json:"amount"`
+	Currency    string        `json:"currency,omitempty"`
+	Date        string        `json:"date"`
+	Day         int           `json:"day,omitempty"`
+	Description string        `json:"description,omitempty"`
+	PaidBy      int64         `json:"paid_by,omitempty"`
+	Split       *SplitRequest `json:"split,omitempty"`
+}
+
+// SplitRequest divides a shared expense: "equal" among the listed members (all when none are
+// listed), "exact" amounts in the expense's currency, or "percent" shares adding up to 100
+type SplitRequest struct {
+	Mode   string              `json:"mode"`
+	Shares []SplitShareRequest `json:"shares,omitempty"`
+}
+
+// SplitShareRequest is one member's part of a split
+type SplitShareRequest struct {
+	MemberID int64   `json:"member_id"`
+	Amount   float64 `json:"amount,omitempty"`
+	Percent  float64 `json:"percent,omitempty"`
+}
+
+// TripMemberRequest adds a person to a group trip
+type TripMemberRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// TripBudgetRequest sets the budget a trip's expenses are tracked against. Without a breakdown the
+// total is split by the budget estimate for the destination, travelers and style; with one, the
+// total defaults to its sum.
+type TripBudgetRequest struct {
+	Total       float64          `json:"total,omitempty"`
+	Currency    string           `json:"currency,omitempty"`
+	Breakdown   *BudgetBreakdown `json:"breakdown,omitempty"`
+	Days        int              `json:"days,omitempty"`
+	Destination string           `json:"destination,omitempty"`
+	Origin      string           `json:"origin,omitempty"`
+	Travelers   int              `json:"travelers,omitempty"`
+	Style       string           `json:"style,omitempty"`
+}
+
+// BudgetBreakdown is a budget per expense category
+type BudgetBreakdown struct {
+	Flight    float64 `json:"flight"`
+	Hotel     float64 `json:"hotel"`
+	Food      float64 `json:"food"`
+	Transport float64 `json:"transport"`
+	Misc      float64 `json:"misc"`
+}
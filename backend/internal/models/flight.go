@@ -0,0 +1,21 @@
+package models
+
+// FlightSearchRequest represents a one-way flight search. The date uses YYYY-MM-DD.
+type FlightSearchRequest struct {
+	Origin      string `json:"origin" query:"origin"`
+	Destination string `json:"destination" query:"destination"`
+	Date        string `json:"date" query:"date"`
+	Passengers  int    `json:"passengers,omitempty" query:"passengers"`
+	MaxStops    int    `json:"max_stops,omitempty" query:"max_stops"`
+	DirectOnly  bool   `json:"direct_only,omitempty" query:"direct_only"`
+	SortBy      string `json:"sort_by,omitempty" query:"sort_by"`
+}
+
+// FlightWatchRequest subscribes to status changes of a flight. The date uses YYYY-MM-DD.
+// Channel is webhook, line or email; target is the webhook URL, LINE user ID or email address.
+type FlightWatchRequest struct {
+	FlightCode string `json:"flight_code"`
+	Date       string `json:"date"`
+	Channel    string `json:"channel"`
+	Target     string `json:"target"`
+}
@@ -2,7 +2,14 @@ package models
 
 // PlanRequest represents a request to create a travel plan
 type PlanRequest struct {
-	Message string `json:"message" validate:"required"`
+	Message   string `json:"message" validate:"required"`
+	SessionID string `json:"session_id,omitempty"`
+	// Format selects the response shape: "markdown", "json" or "both" (default)
+	Format string `json:"format,omitempty"`
+	// Currency is the ISO code amounts are shown in (default THB)
+	Currency string `json:"currency,omitempty"`
+	// TripID is a saved trip of the signed-in user that the message is about
+	TripID int64 `json:"trip_id,omitempty"`
 }
 
 // PlanResponse represents a comprehensive travel plan response
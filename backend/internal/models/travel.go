@@ -10,8 +10,10 @@ type TravelSearchRequest struct {
 	StartDate   string                 `json:"startDate,omitempty"`
 	EndDate     string                 `json:"endDate,omitempty"`
 	Budget      float64                `json:"budget,omitempty"`
+	Currency    string                 `json:"currency,omitempty"`
 	Preferences map[string]interface{} `json:"preferences,omitempty"`
-	UserID      string                 `json:"userId,omitempty"`
+	// UserID is the signed-in user making the search, taken from the access token
+	UserID string `json:"-"`
 }
 
 // TravelSearchResponse represents the response to a travel search
@@ -0,0 +1,24 @@
+package models
+
+// TripRequest saves a trip. With session_id the plan most recently created or updated in that
+// conversation is saved, and the other fields only override its title and start date. Otherwise
+// the itinerary is given directly; the shape of a PlanResponse is accepted as is.
+type TripRequest struct {
+	SessionID    string           `json:"session_id,omitempty"`
+	Title        string           `json:"title,omitempty"`
+	Destination  string           `json:"destination,omitempty"`
+	StartDate    string           `json:"start_date,omitempty"`
+	DurationDays int              `json:"duration_days,omitempty"`
+	Budget       float64          `json:"budget,omitempty"`
+	Summary      string           `json:"summary,omitempty"`
+	Itinerary    []TripDayRequest `json:"itinerary,omitempty"`
+}
+
+// TripDayRequest is one day of a saved itinerary, with its activities in order. A single
+// activity may be given as activity.
+type TripDayRequest struct {
+	Day        int      `json:"day"`
+	Budget     float64  `json:"budget,omitempty"`
+	Activities []string `json:"activities,omitempty"`
+	Activity   string   `json:"activity,omitempty"`
+}
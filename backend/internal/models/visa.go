@@ -0,0 +1,27 @@
+package models
+
+// VisaRequest represents a request to check visa requirements
+type VisaRequest struct {
+	Nationality string `json:"nationality"`
+	Destination string `json:"destination"`
+	Purpose     string `json:"purpose,omitempty"`
+	StayDays    int    `json:"stay_days,omitempty"`
+}
+
+// VisaItineraryRequest represents a multi-country visa check. Dates use YYYY-MM-DD.
+type VisaItineraryRequest struct {
+	Nationality    string          `json:"nationality"`
+	PassportExpiry string          `json:"passport_expiry,omitempty"`
+	ReturnDate     string          `json:"return_date,omitempty"`
+	Stops          []VisaStopInput `json:"stops"`
+}
+
+// VisaStopInput is one country of a visa itinerary
+type VisaStopInput struct {
+	Country   string `json:"country"`
+	EntryDate string `json:"entry_date"`
+	ExitDate  string `json:"exit_date,omitempty"`
+	StayDays  int    `json:"stay_days,omitempty"`
+	Purpose   string `json:"purpose,omitempty"`
+	Transit   bool   `json:"transit,omitempty"`
+}
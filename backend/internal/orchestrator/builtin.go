@@ -0,0 +1,99 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+)
+
+// handlerAgent adapts an orchestrator handler to the agents.Agent interface
+type handlerAgent struct {
+	name    string
+	intents []string
+	handle  func(ctx context.Context, intent *agents.IntentResult) (Result, error)
+}
+
+// Name implements agents.Agent
+func (a *handlerAgent) Name() string { return a.name }
+
+// SupportedIntents implements agents.Agent
+func (a *handlerAgent) SupportedIntents() []string { return a.intents }
+
+// Handle implements agents.Agent
+func (a *handlerAgent) Handle(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+	return a.handle(ctx, intent)
+}
+
+// turnKey is the context key for per-message orchestrator state
+type turnKey struct{}
+
+// turn carries the session and stream of the message being handled
+type turn struct {
+	state *ConversationState
+	emit  EventFunc
+}
+
+// withTurn attaches the session and stream of a message to ctx
+func withTurn(ctx context.Context, state *ConversationState, emit EventFunc) context.Context {
+	return context.WithValue(ctx, turnKey{}, turn{state: state, emit: emit})
+}
+
+// turnFrom returns the session and stream attached to ctx, if any
+func turnFrom(ctx context.Context) turn {
+	t, _ := ctx.Value(turnKey{}).(turn)
+	return t
+}
+
+// registerBuiltinAgents registers the handlers the orchestrator ships with
+func (o *Orchestrator) registerBuiltinAgents() {
+	builtins := []*handlerAgent{
+		{name: "planner", intents: []string{"plan_trip"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			result, err := o.handlePlanTrip(ctx, intent, turnFrom(ctx).emit)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		{name: "plan_updater", intents: []string{"plan_update"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			return o.handlePlanUpdate(ctx, turnFrom(ctx).state, intent.Message)
+		}},
+		{name: "weather", intents: []string{"weather_check"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			result, err := o.handleWeatherCheck(ctx, intent)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		{name: "flight", intents: []string{"flight_check"}, handle: o.handleFlightCheck},
+		{name: "flight_search", intents: []string{"flight_search"}, handle: o.handleFlightSearch},
+		{name: "hotel", intents: []string{"hotel_search"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			result, err := o.handleHotelSearch(ctx, intent)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		{name: "local", intents: []string{"local_recommendation"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			result, err := o.handleLocalRecommendation(ctx, intent)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		{name: "budget", intents: []string{"budget_inquiry"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			result, err := o.handleBudgetInquiry(ctx, intent)
+			if err != nil {
+				return nil, err
+			}
+			return result, nil
+		}},
+		{name: "chat", intents: []string{"general_chat"}, handle: func(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+			return &MessageResult{Text: "Hello! I'm your AI travel assistant. I can help you plan trips, check weather, find flights, search hotels, and get local recommendations. What would you like to do?"}, nil
+		}},
+	}
+
+	for _, agent := range builtins {
+		o.RegisterAgent(agent)
+	}
+	o.RegisterAgent(o.visaAgent)
+}
@@ -0,0 +1,64 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingAgent answers with a fixed message and remembers the intent it received
+type recordingAgent struct {
+	intents  []string
+	received *agents.IntentResult
+}
+
+func (a *recordingAgent) Name() string               { return "recording" }
+func (a *recordingAgent) SupportedIntents() []string { return a.intents }
+func (a *recordingAgent) Handle(ctx context.Context, intent *agents.IntentResult) (agents.Result, error) {
+	a.received = intent
+	return &MessageResult{Text: "handled by fake"}, nil
+}
+
+func TestOrchestrator_BuiltinAgentsCoverAllIntents(t *testing.T) {
+	orch := New("", "", "", "")
+	assert.ElementsMatch(t, []string{
+		"plan_trip", "plan_update", "weather_check", "flight_check", "flight_search",
+		"hotel_search", "local_recommendation", "budget_inquiry", "visa_check", "general_chat",
+	}, orch.Intents())
+}
+
+func TestOrchestrator_RegisteredAgentReplacesBuiltin(t *testing.T) {
+	orch := New("", "", "", "")
+	fake := &recordingAgent{intents: []string{"weather_check"}}
+	orch.RegisterAgent(fake)
+
+	response, err := orch.Process(context.Background(), "", "What's the weather in Bangkok?")
+	require.NoError(t, err)
+	assert.Equal(t, "handled by fake", response.Markdown())
+
+	require.NotNil(t, fake.received)
+	assert.Equal(t, "weather_check", fake.received.Intent)
+	assert.Equal(t, "What's the weather in Bangkok?", fake.received.Message)
+}
+
+func TestOrchestrator_UnregisteredIntent(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.registry = agents.NewRegistry()
+
+	response, err := orch.Process(context.Background(), "", "Hello!")
+	require.NoError(t, err)
+	assert.Contains(t, response.Markdown(), "I'm not sure how to help with that")
+}
+
+func TestOrchestrator_VisaCheck(t *testing.T) {
+	orch := New("", "", "", "")
+
+	response, err := orch.Process(context.Background(), "", "Do Thai citizens need a visa for Japan?")
+	require.NoError(t, err)
+	assert.Equal(t, "visa_check", response.Intent)
+	assert.Equal(t, "visa_requirement", response.Result.Type())
+	assert.Contains(t, response.Markdown(), "Visa Requirements: TH → JP")
+}
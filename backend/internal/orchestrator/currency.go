@@ -0,0 +1,251 @@
+package orchestrator
+
+import (
+	"context"
+	"log"
+	"math"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+)
+
+// currencyKey is the context key for the caller's display currency
+type currencyKey struct{}
+
+// WithCurrency asks the orchestrator to show amounts in the given currency.
+// A "currency" entity in the message takes precedence.
+func WithCurrency(ctx context.Context, code string) context.Context {
+	return context.WithValue(ctx, currencyKey{}, code)
+}
+
+// SetRateProvider changes the exchange rates used for budgets, hotel prices and display amounts
+func (o *Orchestrator) SetRateProvider(provider currency.RateProvider) {
+	o.converter = currency.NewConverter(provider)
+	if hotelAgent, ok := o.hotelAgent.(*agents.HotelAgent); ok {
+		hotelAgent.SetRateProvider(provider)
+	}
+}
+
+// SetDefaultCurrency sets the display currency used when a request does not choose one
+func (o *Orchestrator) SetDefaultCurrency(code string) {
+	normalized, ok := currency.Normalize(code)
+	if !ok {
+		log.Printf("Orchestrator: Unknown default currency %q, keeping %s", code, o.defaultCurrency)
+		return
+	}
+	o.defaultCurrency = normalized
+}
+
+// displayCurrency picks the currency for a response: the message's "currency" entity, then the
+// caller's choice, then the default. Currencies without an exchange rate fall back to THB.
+func (o *Orchestrator) displayCurrency(ctx context.Context, entities map[string]interface{}) string {
+	code := o.defaultCurrency
+	if requested, ok := ctx.Value(currencyKey{}).(string); ok && requested != "" {
+		code = requested
+	}
+	if requested := o.getStringEntity(entities, "currency", ""); requested != "" {
+		code = requested
+	}
+
+	normalized, ok := currency.Normalize(code)
+	if !ok || !o.converter.Supports(ctx, normalized) {
+		log.Printf("Orchestrator: No exchange rate for display currency %q, using %s", code, currency.Base)
+		return currency.Base
+	}
+	return normalized
+}
+
+// budgetEntity reads the budget in THB. Budgets are in "budget_currency" when it is set, otherwise
+// in the display currency.
+func (o *Orchestrator) budgetEntity(ctx context.Context, entities map[string]interface{}, defaultValue float64) float64 {
+	amount := o.getFloatEntity(entities, "budget", -1)
+	if amount < 0 {
+		return defaultValue
+	}
+
+	code := o.getStringEntity(entities, "budget_currency", "")
+	if code == "" {
+		code = o.displayCurrency(ctx, entities)
+	}
+	code, _ = currency.Normalize(code)
+	if code == currency.Base {
+		return amount
+	}
+
+	converted, err := o.converter.Convert(ctx, currency.Money{Amount: amount, Currency: code}, currency.Base)
+	if err != nil {
+		log.Printf("Orchestrator: Cannot convert budget from %s, assuming THB: %v", code, err)
+		return amount
+	}
+	return math.Round(converted.Amount)
+}
+
+// moneyConverter converts THB amounts in results into the display currency
+type moneyConverter struct {
+	ctx       context.Context
+	converter *currency.Converter
+	to        string
+}
+
+// amount converts an amount from a currency (THB when empty); unconvertible amounts are returned unchanged
+func (m moneyConverter) amount(value float64, from string) (float64, string) {
+	if from == "" {
+		from = currency.Base
+	}
+	converted, err := m.converter.Convert(m.ctx, currency.Money{Amount: value, Currency: from}, m.to)
+	if err != nil {
+		log.Printf("Orchestrator: Cannot convert %s to %s: %v", from, m.to, err)
+		return value, from
+	}
+	return converted.Round().Amount, m.to
+}
+
+// base converts a THB amount
+func (m moneyConverter) base(value float64) float64 {
+	converted, _ := m.amount(value, currency.Base)
+	return converted
+}
+
+// plan returns a copy of the plan with its budgets converted, leaving the session's plan untouched
+func (m moneyConverter) plan(plan *agents.TripPlan) *agents.TripPlan {
+	if plan == nil {
+		return nil
+	}
+	converted := *plan
+	converted.TotalBudget = m.base(plan.TotalBudget)
+	converted.Itinerary = make([]agents.ItineraryDay, len(plan.Itinerary))
+	for i, day := range plan.Itinerary {
+		day.Budget = m.base(day.Budget)
+		converted.Itinerary[i] = day
+	}
+	return &converted
+}
+
+// hotels returns a copy of the hotels with their prices converted
+func (m moneyConverter) hotels(hotels []agents.HotelRecommendation) []agents.HotelRecommendation {
+	converted := make([]agents.HotelRecommendation, len(hotels))
+	for i, hotel := range hotels {
+		from := hotel.Currency
+		hotel.PricePerNight, hotel.Currency = m.amount(hotel.PricePerNight, from)
+		hotel.TotalPrice, _ = m.amount(hotel.TotalPrice, from)
+		converted[i] = hotel
+	}
+	return converted
+}
+
+// flights returns a copy of the flight options with their prices converted
+func (m moneyConverter) flights(flights []agents.FlightOption) []agents.FlightOption {
+	converted := make([]agents.FlightOption, len(flights))
+	for i, flight := range flights {
+		from := flight.Currency
+		flight.PricePerPassenger, flight.Currency = m.amount(flight.PricePerPassenger, from)
+		flight.TotalPrice, _ = m.amount(flight.TotalPrice, from)
+		converted[i] = flight
+	}
+	return converted
+}
+
+// estimate returns a copy of the budget estimate with its amounts converted
+func (m moneyConverter) estimate(estimate *agents.BudgetEstimate) *agents.BudgetEstimate {
+	if estimate == nil {
+		return nil
+	}
+	converted := *estimate
+	converted.Total = m.base(estimate.Total)
+	converted.Required = m.base(estimate.Required)
+	converted.PerDay = m.base(estimate.PerDay)
+	converted.PerPersonPerDay = m.base(estimate.PerPersonPerDay)
+	converted.Shortfall = m.base(estimate.Shortfall)
+	converted.Allocations = make([]agents.BudgetAllocation, len(estimate.Allocations))
+	for i, allocation := range estimate.Allocations {
+		allocation.Amount = m.base(allocation.Amount)
+		allocation.PerDay = m.base(allocation.PerDay)
+		allocation.Estimate = m.base(allocation.Estimate)
+		converted.Allocations[i] = allocation
+	}
+	return &converted
+}
+
+// event converts the amounts in a progress event
+func (m moneyConverter) event(event StreamEvent) StreamEvent {
+	switch data := event.Data.(type) {
+	case *agents.TripPlan:
+		event.Data = m.plan(data)
+	case []agents.HotelRecommendation:
+		event.Data = m.hotels(data)
+	case []agents.FlightOption:
+		event.Data = m.flights(data)
+	}
+	return event
+}
+
+// result converts the amounts in a handler result; results without amounts are returned as is
+func (m moneyConverter) result(result Result) Result {
+	switch r := result.(type) {
+	case *TripPlanResult:
+		converted := *r
+		converted.Currency = m.to
+		converted.Budget = m.base(r.Budget)
+		converted.Plan = m.plan(r.Plan)
+		converted.Hotels = m.hotels(r.Hotels)
+		converted.Flights = m.flights(r.Flights)
+		return &converted
+	case *PlanUpdateResult:
+		converted := *r
+		converted.Currency = m.to
+		converted.PreviousBudget = m.base(r.PreviousBudget)
+		converted.Plan = m.plan(r.Plan)
+		converted.Changes = make([]agents.PlanDayChange, len(r.Changes))
+		for i, change := range r.Changes {
+			change.BudgetBefore = m.base(change.BudgetBefore)
+			change.BudgetAfter = m.base(change.BudgetAfter)
+			converted.Changes[i] = change
+		}
+		return &converted
+	case *HotelListResult:
+		converted := *r
+		converted.Currency = m.to
+		converted.Budget = m.base(r.Budget)
+		converted.Search.BudgetPerNight = converted.Budget
+		converted.Search.Currency = m.to
+		converted.Hotels = m.hotels(r.Hotels)
+		return &converted
+	case *FlightSearchResult:
+		converted := *r
+		converted.Options = m.flights(r.Options)
+		return &converted
+	case *BudgetResult:
+		converted := *r
+		converted.Currency = m.to
+		converted.Total = m.base(r.Total)
+		converted.Plan = agents.BudgetPlan{
+			Flight:    int(m.base(float64(r.Plan.Flight))),
+			Hotel:     int(m.base(float64(r.Plan.Hotel))),
+			Food:      int(m.base(float64(r.Plan.Food))),
+			Transport: int(m.base(float64(r.Plan.Transport))),
+			Misc:      int(m.base(float64(r.Plan.Misc))),
+		}
+		converted.Estimate = m.estimate(r.Estimate)
+		return &converted
+	case *agents.VisaCheckResult:
+		if r.Requirement == nil || r.Requirement.Fees == nil || r.Requirement.Fees.Currency == m.to {
+			return r
+		}
+		amount, code := m.amount(r.Requirement.Fees.Amount, r.Requirement.Fees.Currency)
+		if code != m.to {
+			return r
+		}
+		converted := *r
+		converted.LocalFee = &currency.Money{Amount: amount, Currency: code}
+		return &converted
+	}
+	return result
+}
+
+// displayCode is the currency amounts are shown in, THB unless a result was converted
+func displayCode(code string) string {
+	if code == "" {
+		return currency.Base
+	}
+	return code
+}
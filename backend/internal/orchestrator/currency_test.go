@@ -0,0 +1,151 @@
+package orchestrator
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCurrencyOrchestrator uses fake agents and a fixed rate of 0.03 USD per baht
+func newCurrencyOrchestrator() *Orchestrator {
+	orch := newFanOutOrchestrator(0, 0, 0, 0)
+	orch.SetRateProvider(currency.NewRates("THB", map[string]float64{"USD": 0.03, "EUR": 0.025}))
+	orch.SetSessionStore(NewMemorySessionStore())
+	return orch
+}
+
+func TestOrchestrator_DisplayCurrency(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "usd")
+
+	response, err := orch.Process(ctx, "session-1", "Plan a trip to Tokyo for 3 days with 30000 baht")
+	require.NoError(t, err)
+	assert.Equal(t, "USD", response.Currency)
+
+	result, ok := response.Result.(*TripPlanResult)
+	require.True(t, ok)
+	assert.Equal(t, "USD", result.Currency)
+	assert.Equal(t, 900.0, result.Budget)
+	assert.Equal(t, 900.0, result.Plan.TotalBudget)
+	assert.Equal(t, 900.0, result.Plan.Itinerary[0].Budget)
+	assert.Equal(t, "USD", result.Hotels[0].Currency)
+	assert.Equal(t, 300.0, result.Hotels[0].PricePerNight)
+	assert.Equal(t, 270.0, result.Flights[0].PricePerPassenger)
+	assert.Contains(t, response.Markdown(), "**Budget:** 900.00 USD")
+
+	// The session keeps the plan in baht so follow-ups can be shown in any currency
+	state, err := orch.sessions.Load(ctx, "session-1")
+	require.NoError(t, err)
+	assert.Equal(t, 30000.0, state.LastPlan.TotalBudget)
+}
+
+func TestOrchestrator_CurrencyEntityOverridesContext(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "USD")
+
+	response, err := orch.Process(ctx, "", "What can I do with 10000 baht budget? Show it in euros")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", response.Currency)
+
+	result, ok := response.Result.(*BudgetResult)
+	require.True(t, ok)
+	assert.Equal(t, 250.0, result.Total)
+	assert.Contains(t, response.Markdown(), "# Budget Breakdown for 250 EUR")
+}
+
+func TestOrchestrator_BudgetInOtherCurrency(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+
+	response, err := orch.Process(context.Background(), "", "Plan a trip to Tokyo for 3 days with 600 usd")
+	require.NoError(t, err)
+	assert.Equal(t, "THB", response.Currency)
+
+	result, ok := response.Result.(*TripPlanResult)
+	require.True(t, ok)
+	assert.Equal(t, 20000.0, result.Budget, "Agents plan with the budget converted to baht")
+	assert.Contains(t, response.Markdown(), "**Budget:** 20000 THB")
+}
+
+func TestOrchestrator_BudgetWithoutCurrencyUsesDisplayCurrency(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "USD")
+
+	response, err := orch.Process(ctx, "", "Plan a trip to Tokyo for 3 days with a budget of 600")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*TripPlanResult)
+	require.True(t, ok)
+	assert.Equal(t, 600.0, result.Budget)
+	assert.Equal(t, "USD", result.Currency)
+}
+
+func TestOrchestrator_UnknownDisplayCurrencyFallsBackToBaht(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "GBP")
+
+	response, err := orch.Process(ctx, "", "Plan a trip to Tokyo for 3 days with 30000 baht")
+	require.NoError(t, err)
+	assert.Equal(t, "THB", response.Currency)
+	assert.Equal(t, 30000.0, response.Result.(*TripPlanResult).Budget)
+}
+
+func TestOrchestrator_StreamEventsUseDisplayCurrency(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "USD")
+
+	var mu sync.Mutex
+	events := map[string]interface{}{}
+	_, err := orch.ProcessStream(ctx, "", "Plan a trip to Tokyo for 3 days with 30000 baht", func(event StreamEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events[event.Type] = event.Data
+	})
+	require.NoError(t, err)
+
+	plan, ok := events[EventItinerary].(*agents.TripPlan)
+	require.True(t, ok)
+	assert.Equal(t, 900.0, plan.TotalBudget)
+
+	hotels, ok := events[EventHotels].([]agents.HotelRecommendation)
+	require.True(t, ok)
+	assert.Equal(t, "USD", hotels[0].Currency)
+
+	flights, ok := events[EventFlights].([]agents.FlightOption)
+	require.True(t, ok)
+	assert.Equal(t, "USD", flights[0].Currency)
+}
+
+func TestOrchestrator_VisaFeeInDisplayCurrency(t *testing.T) {
+	orch := New("", "", "", "")
+
+	response, err := orch.Process(context.Background(), "", "Do Thai citizens need a visa for Canada?")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*agents.VisaCheckResult)
+	require.True(t, ok)
+	require.NotNil(t, result.Requirement.Fees)
+	require.NotNil(t, result.LocalFee)
+	assert.Equal(t, "THB", result.LocalFee.Currency)
+	assert.Greater(t, result.LocalFee.Amount, result.Requirement.Fees.Amount)
+	assert.Contains(t, response.Markdown(), "CAD (≈ ")
+}
+
+func TestOrchestrator_BudgetEstimateInDisplayCurrency(t *testing.T) {
+	orch := newCurrencyOrchestrator()
+	ctx := WithCurrency(context.Background(), "USD")
+
+	response, err := orch.Process(ctx, "", "Is 30000 baht enough for 5 days in Tokyo?")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*BudgetResult)
+	require.True(t, ok)
+	require.NotNil(t, result.Estimate)
+	assert.Equal(t, 900.0, result.Estimate.Total)
+	assert.Equal(t, 578.4, result.Estimate.Shortfall)
+	assert.Contains(t, response.Markdown(), "**Budget too low by 578.40 USD:**")
+}
@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+)
+
+// Agent run statuses reported in AgentRun
+const (
+	AgentStatusOK      = "ok"
+	AgentStatusError   = "error"
+	AgentStatusTimeout = "timeout"
+	AgentStatusSkipped = "skipped"
+)
+
+// Names of the sub-agents used to build a trip plan
+const (
+	agentPlanner = "planner"
+	agentWeather = "weather"
+	agentHotels  = "hotels"
+	agentSocial  = "social"
+	agentFlights = "flights"
+)
+
+// AgentTimeouts holds the deadline given to each sub-agent of a trip plan
+type AgentTimeouts struct {
+	Planner time.Duration
+	Weather time.Duration
+	Hotels  time.Duration
+	Social  time.Duration
+	Flights time.Duration
+}
+
+// DefaultAgentTimeouts leaves the LLM planner the most time and keeps lookups short
+var DefaultAgentTimeouts = AgentTimeouts{
+	Planner: 20 * time.Second,
+	Weather: 5 * time.Second,
+	Hotels:  8 * time.Second,
+	Social:  5 * time.Second,
+	Flights: 8 * time.Second,
+}
+
+// AgentRun reports how a sub-agent performed while handling a request
+type AgentRun struct {
+	Agent      string `json:"agent"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// tripPlanner creates, updates and summarizes itineraries
+type tripPlanner interface {
+	CreatePlan(ctx context.Context, destination string, duration int, budget float64) (*agents.TripPlan, error)
+	UpdatePlan(ctx context.Context, currentPlan *agents.TripPlan, condition string) (*agents.TripPlan, error)
+	StreamSummary(ctx context.Context, plan *agents.TripPlan, onToken func(string)) (string, error)
+}
+
+// weatherForecaster returns the weather forecast for a city
+type weatherForecaster interface {
+	GetForecast(ctx context.Context, city string) (*agents.WeatherForecast, error)
+}
+
+// hotelSearcher finds hotels for a stay
+type hotelSearcher interface {
+	Search(ctx context.Context, req agents.HotelSearchRequest) ([]agents.HotelRecommendation, error)
+}
+
+// flightFinder checks flight status and searches flights on a route
+type flightFinder interface {
+	CheckFlight(ctx context.Context, flightCode string) (*agents.FlightStatus, error)
+	SearchFlights(ctx context.Context, req agents.FlightSearchRequest) ([]agents.FlightOption, error)
+}
+
+// agentOutcome is what a sub-agent sends back to the fan-in loop
+type agentOutcome struct {
+	value interface{}
+	run   AgentRun
+}
+
+// runAgent calls fn with its own deadline and reports the outcome on out.
+// A slow agent that ignores its context is abandoned once the deadline passes.
+func runAgent(ctx context.Context, name string, timeout time.Duration, fn func(ctx context.Context) (interface{}, error), out chan<- agentOutcome) {
+	agentCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type callResult struct {
+		value interface{}
+		err   error
+	}
+
+	start := time.Now()
+	done := make(chan callResult, 1)
+	go func() {
+		value, err := fn(agentCtx)
+		done <- callResult{value: value, err: err}
+	}()
+
+	run := AgentRun{Agent: name}
+	var value interface{}
+	select {
+	case result := <-done:
+		value = result.value
+		if result.err != nil {
+			run.Status = AgentStatusError
+			run.Error = result.err.Error()
+		} else {
+			run.Status = AgentStatusOK
+		}
+	case <-agentCtx.Done():
+		run.Status = AgentStatusError
+		run.Error = agentCtx.Err().Error()
+	}
+
+	if run.Status == AgentStatusError && errors.Is(agentCtx.Err(), context.DeadlineExceeded) {
+		run.Status = AgentStatusTimeout
+		run.Error = fmt.Sprintf("no response within %s", timeout)
+		value = nil
+	}
+	run.DurationMs = time.Since(start).Milliseconds()
+
+	out <- agentOutcome{value: value, run: run}
+}
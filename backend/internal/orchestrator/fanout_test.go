@@ -0,0 +1,266 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlanner returns a fixed plan after a delay
+type fakePlanner struct {
+	delay time.Duration
+	err   error
+}
+
+func (f *fakePlanner) CreatePlan(ctx context.Context, destination string, duration int, budget float64) (*agents.TripPlan, error) {
+	if err := sleep(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &agents.TripPlan{
+		Destination: destination,
+		Duration:    duration,
+		TotalBudget: budget,
+		Itinerary:   []agents.ItineraryDay{{Day: 1, Activities: []string{"Walk around"}, Budget: budget}},
+		Summary:     "A fake plan",
+	}, nil
+}
+
+func (f *fakePlanner) UpdatePlan(ctx context.Context, currentPlan *agents.TripPlan, condition string) (*agents.TripPlan, error) {
+	return currentPlan, nil
+}
+
+func (f *fakePlanner) StreamSummary(ctx context.Context, plan *agents.TripPlan, onToken func(string)) (string, error) {
+	return plan.Summary, nil
+}
+
+// fakeWeather returns a fixed forecast after a delay, ignoring its context
+type fakeWeather struct {
+	delay time.Duration
+}
+
+func (f *fakeWeather) GetForecast(ctx context.Context, city string) (*agents.WeatherForecast, error) {
+	time.Sleep(f.delay)
+	return &agents.WeatherForecast{City: city, Temperature: 25, Condition: "Sunny"}, nil
+}
+
+// fakeHotels returns a fixed hotel list after a delay
+type fakeHotels struct {
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeHotels) Search(ctx context.Context, req agents.HotelSearchRequest) ([]agents.HotelRecommendation, error) {
+	if err := sleep(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	return []agents.HotelRecommendation{{Name: "Fake Hotel", PricePerNight: req.BudgetPerNight, Rating: 4.5}}, nil
+}
+
+// fakeFlights returns a fixed flight option after a delay
+type fakeFlights struct {
+	delay time.Duration
+}
+
+func (f *fakeFlights) CheckFlight(ctx context.Context, flightCode string) (*agents.FlightStatus, error) {
+	return &agents.FlightStatus{FlightCode: flightCode, Status: "On Time"}, nil
+}
+
+func (f *fakeFlights) SearchFlights(ctx context.Context, req agents.FlightSearchRequest) ([]agents.FlightOption, error) {
+	if err := sleep(ctx, f.delay); err != nil {
+		return nil, err
+	}
+	return []agents.FlightOption{{Rank: 1, Airline: "Fake Air", FlightNumber: "FA100", Origin: "BKK", Destination: req.Destination, PricePerPassenger: 9000}}, nil
+}
+
+// fakeSocial returns a fixed place after a delay, reporting how each lookup ended on done when set
+type fakeSocial struct {
+	delay time.Duration
+	done  chan error
+}
+
+func (f *fakeSocial) GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]SocialPlace, error) {
+	err := sleep(ctx, f.delay)
+	if f.done != nil {
+		f.done <- err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []SocialPlace{{Name: "Fake Spot", Rating: 4.8, ReviewCount: 100}}, nil
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-time.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newFanOutOrchestrator(planner, weather, hotels, social time.Duration) *Orchestrator {
+	orch := New("", "", "", "")
+	orch.plannerAgent = &fakePlanner{delay: planner}
+	orch.weatherAgent = &fakeWeather{delay: weather}
+	orch.hotelAgent = &fakeHotels{delay: hotels}
+	orch.flightAgent = &fakeFlights{delay: hotels}
+	orch.SetSocialService(&fakeSocial{delay: social})
+	return orch
+}
+
+func runsByAgent(runs []AgentRun) map[string]AgentRun {
+	byAgent := make(map[string]AgentRun)
+	for _, run := range runs {
+		byAgent[run.Agent] = run
+	}
+	return byAgent
+}
+
+func planTripIntent() *agents.IntentResult {
+	return &agents.IntentResult{
+		Intent:   "plan_trip",
+		Entities: map[string]interface{}{"destination": "Tokyo", "duration": 3.0, "budget": 30000.0},
+	}
+}
+
+func TestHandlePlanTrip_RunsAgentsConcurrently(t *testing.T) {
+	orch := newFanOutOrchestrator(100*time.Millisecond, 100*time.Millisecond, 100*time.Millisecond, 100*time.Millisecond)
+
+	start := time.Now()
+	result, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	elapsed := time.Since(start)
+	require.NoError(t, err)
+
+	assert.Less(t, elapsed, 300*time.Millisecond, "Agents should not run one after another")
+	require.NotNil(t, result.Plan)
+	require.NotNil(t, result.Weather)
+	assert.Len(t, result.Hotels, 1)
+	assert.Len(t, result.PopularSpots, 1)
+	assert.Len(t, result.Flights, 1)
+	assert.Contains(t, result.Markdown(), "## Flight Options")
+
+	runs := runsByAgent(result.Agents)
+	require.Len(t, runs, 5)
+	for _, name := range []string{agentPlanner, agentWeather, agentHotels, agentSocial, agentFlights} {
+		assert.Equal(t, AgentStatusOK, runs[name].Status, name)
+		assert.GreaterOrEqual(t, runs[name].DurationMs, int64(100), name)
+	}
+}
+
+func TestHandlePlanTrip_PartialResultsOnTimeout(t *testing.T) {
+	orch := newFanOutOrchestrator(10*time.Millisecond, time.Second, 10*time.Millisecond, time.Second)
+	orch.SetAgentTimeouts(AgentTimeouts{Weather: 50 * time.Millisecond, Social: 50 * time.Millisecond})
+
+	start := time.Now()
+	result, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 500*time.Millisecond, "Slow agents should be abandoned at their deadline")
+
+	require.NotNil(t, result.Plan)
+	assert.Nil(t, result.Weather)
+	assert.Len(t, result.Hotels, 1)
+	assert.Empty(t, result.PopularSpots)
+
+	runs := runsByAgent(result.Agents)
+	assert.Equal(t, AgentStatusOK, runs[agentPlanner].Status)
+	assert.Equal(t, AgentStatusOK, runs[agentHotels].Status)
+	assert.Equal(t, AgentStatusTimeout, runs[agentWeather].Status)
+	assert.Equal(t, AgentStatusTimeout, runs[agentSocial].Status)
+	assert.NotEmpty(t, runs[agentWeather].Error)
+
+	markdown := result.Markdown()
+	assert.Contains(t, markdown, "weather and social lookup did not respond in time")
+	assert.NotContains(t, markdown, "## Weather Forecast")
+}
+
+func TestHandlePlanTrip_SocialLookupStopsAtItsDeadline(t *testing.T) {
+	orch := newFanOutOrchestrator(0, 0, 0, 0)
+	social := &fakeSocial{delay: time.Minute, done: make(chan error, 1)}
+	orch.SetSocialService(social)
+	orch.SetAgentTimeouts(AgentTimeouts{Social: 50 * time.Millisecond})
+
+	_, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	require.NoError(t, err)
+
+	select {
+	case err := <-social.done:
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	case <-time.After(time.Second):
+		t.Fatal("The places lookup kept running after the social deadline")
+	}
+}
+
+func TestTripPlanResult_TimedOutAgentsInFanOutOrder(t *testing.T) {
+	// Runs are recorded as agents finish, so a slow social lookup can come before weather
+	result := &TripPlanResult{
+		Destination: "Tokyo",
+		Duration:    3,
+		Plan:        &agents.TripPlan{Summary: "A fake plan"},
+		Agents: []AgentRun{
+			{Agent: agentSocial, Status: AgentStatusTimeout},
+			{Agent: agentPlanner, Status: AgentStatusOK},
+			{Agent: agentHotels, Status: AgentStatusOK},
+			{Agent: agentWeather, Status: AgentStatusTimeout},
+		},
+	}
+
+	assert.Equal(t, []string{agentWeather, agentSocial}, result.timedOutAgents())
+	assert.Contains(t, result.Markdown(), "weather and social lookup did not respond in time")
+}
+
+func TestHandlePlanTrip_AgentErrorsAreReported(t *testing.T) {
+	orch := newFanOutOrchestrator(0, 0, 0, 0)
+	orch.hotelAgent = &fakeHotels{err: errors.New("hotel API down")}
+
+	result, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	require.NoError(t, err)
+
+	runs := runsByAgent(result.Agents)
+	assert.Equal(t, AgentStatusError, runs[agentHotels].Status)
+	assert.Equal(t, "hotel API down", runs[agentHotels].Error)
+	assert.Empty(t, result.Hotels)
+	assert.NotNil(t, result.Weather)
+}
+
+func TestHandlePlanTrip_AtLeastOneDay(t *testing.T) {
+	orch := newFanOutOrchestrator(0, 0, 0, 0)
+	intent := planTripIntent()
+	intent.Entities["duration"] = 0.0
+
+	result, err := orch.handlePlanTrip(context.Background(), intent, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Duration)
+	assert.Equal(t, 1, result.Plan.Duration)
+	require.NotEmpty(t, result.Hotels)
+	assert.Equal(t, 30000.0, result.Hotels[0].PricePerNight, "The whole budget goes to the one night")
+}
+
+func TestHandlePlanTrip_PlannerIsRequired(t *testing.T) {
+	orch := newFanOutOrchestrator(time.Second, 0, 0, 0)
+	orch.SetAgentTimeouts(AgentTimeouts{Planner: 50 * time.Millisecond})
+
+	_, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "planner agent timeout")
+}
+
+func TestHandlePlanTrip_SocialSkippedWithoutService(t *testing.T) {
+	orch := newFanOutOrchestrator(0, 0, 0, 0)
+	orch.socialService = nil
+
+	result, err := orch.handlePlanTrip(context.Background(), planTripIntent(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, AgentStatusSkipped, runsByAgent(result.Agents)[agentSocial].Status)
+}
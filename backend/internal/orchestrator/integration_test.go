@@ -17,7 +17,7 @@ func TestJourney1_TripPlanning_Thai(t *testing.T) {
 
 	// Journey: "อยากไปเที่ยวแคนาดา 7 วัน งบ 100,000 บาท"
 	message := "อยากไปเที่ยวแคนาดา 7 วัน งบ 100,000 บาท"
-	response, err := orch.ProcessMessage(ctx, message)
+	response, err := orch.ProcessMessage(ctx, "", message)
 
 	assert.NoError(t, err, "Should process Thai trip planning message")
 	assert.NotEmpty(t, response, "Response should not be empty")
@@ -36,7 +36,7 @@ func TestJourney2_WeatherCheck_Thai(t *testing.T) {
 
 	// Journey: "วันนี้ฝนตกที่เกียวโตไหม ถ้าตกช่วยเปลี่ยนกิจกรรมให้หน่อย"
 	message := "วันนี้ฝนตกที่เกียวโตไหม"
-	response, err := orch.ProcessMessage(ctx, message)
+	response, err := orch.ProcessMessage(ctx, "", message)
 
 	assert.NoError(t, err, "Should process Thai weather check")
 	assert.NotEmpty(t, response, "Response should not be empty")
@@ -51,7 +51,7 @@ func TestJourney3_FlightCheck_English(t *testing.T) {
 
 	// Journey: "Is flight JL708 on time?"
 	message := "Is flight JL708 on time?"
-	response, err := orch.ProcessMessage(ctx, message)
+	response, err := orch.ProcessMessage(ctx, "", message)
 
 	assert.NoError(t, err, "Should process flight check")
 	assert.NotEmpty(t, response, "Response should not be empty")
@@ -70,7 +70,7 @@ func TestJourney4_LocalRecommendation_Thai(t *testing.T) {
 
 	// Journey: "อยากกินราเมนอร่อยใกล้ Shinjuku"
 	message := "อยากกินราเมนอร่อยใกล้ Shinjuku"
-	response, err := orch.ProcessMessage(ctx, message)
+	response, err := orch.ProcessMessage(ctx, "", message)
 
 	assert.NoError(t, err, "Should process Thai local recommendation")
 	assert.NotEmpty(t, response, "Response should not be empty")
@@ -101,7 +101,7 @@ func TestMultiLanguageSupport(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			response, err := orch.ProcessMessage(ctx, tc.message)
+			response, err := orch.ProcessMessage(ctx, "", tc.message)
 			assert.NoError(t, err, "Should process %s message", tc.language)
 			assert.NotEmpty(t, response, "%s response should not be empty", tc.language)
 		})
@@ -129,7 +129,7 @@ func TestAllIntentCategories(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			response, err := orch.ProcessMessage(ctx, tc.message)
+			response, err := orch.ProcessMessage(ctx, "", tc.message)
 			assert.NoError(t, err, "Should process %s intent", tc.expectedIntent)
 			assert.NotEmpty(t, response, "Response should not be empty for %s", tc.expectedIntent)
 		})
@@ -144,7 +144,7 @@ func TestOrchestratorCoordination(t *testing.T) {
 
 	// Test that plan_trip coordinates multiple agents
 	message := "I want to visit Tokyo for 7 days with 100000 baht"
-	response, err := orch.ProcessMessage(ctx, message)
+	response, err := orch.ProcessMessage(ctx, "", message)
 
 	assert.NoError(t, err, "Should process complex trip planning")
 	assert.NotEmpty(t, response, "Response should not be empty")
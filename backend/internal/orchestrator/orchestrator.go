@@ -5,272 +5,606 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/airports"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 )
 
 // Orchestrator coordinates multiple agents based on user intent
 type Orchestrator struct {
-	intentAgent  *agents.IntentAgent
-	plannerAgent *agents.PlannerAgent
-	weatherAgent *agents.WeatherAgent
-	flightAgent  *agents.FlightAgent
-	localAgent   *agents.LocalAgent
-	hotelAgent   *agents.HotelAgent
+	intentAgent   *agents.IntentAgent
+	plannerAgent  tripPlanner
+	weatherAgent  weatherForecaster
+	flightAgent   flightFinder
+	localAgent    *agents.LocalAgent
+	hotelAgent    hotelSearcher
+	visaAgent     *agents.VisaDocAgent
 	socialService interface {
-		GetTopRatedPlaces(keyword, location string, limit int) ([]SocialPlace, error)
+		GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]SocialPlace, error)
 	}
+	sessions      SessionStore
+	agentTimeouts AgentTimeouts
+	registry      *agents.Registry
+	// converter turns THB amounts into the display currency, THB unless set otherwise
+	converter       *currency.Converter
+	defaultCurrency string
 }
 
 // SocialPlace represents a socially popular place (imported from models)
 type SocialPlace struct {
-	PlaceID     string
-	Name        string
-	Address     string
-	Rating      float64
-	ReviewCount int
-	Types       []string
+	PlaceID     string   `json:"place_id"`
+	Name        string   `json:"name"`
+	Address     string   `json:"address"`
+	Rating      float64  `json:"rating"`
+	ReviewCount int      `json:"review_count"`
+	Types       []string `json:"types,omitempty"`
 }
 
 // New creates a new orchestrator with all agents
 func New(openaiKey, weatherKey, flightKey, hotelKey string) *Orchestrator {
-	return &Orchestrator{
-		intentAgent:  agents.NewIntentAgent(openaiKey),
-		plannerAgent: agents.NewPlannerAgent(openaiKey),
-		weatherAgent: agents.NewWeatherAgent(openaiKey, weatherKey),
-		flightAgent:  agents.NewFlightAgent(openaiKey, flightKey),
-		localAgent:   agents.NewLocalAgent(openaiKey),
-		hotelAgent:   agents.NewHotelAgent(openaiKey, hotelKey),
-		socialService: nil, // Will be set via SetSocialService
+	o := &Orchestrator{
+		intentAgent:     agents.NewIntentAgent(openaiKey),
+		plannerAgent:    agents.NewPlannerAgent(openaiKey),
+		weatherAgent:    agents.NewWeatherAgent(openaiKey, weatherKey),
+		flightAgent:     agents.NewFlightAgent(openaiKey, flightKey),
+		localAgent:      agents.NewLocalAgent(openaiKey),
+		hotelAgent:      agents.NewHotelAgent(openaiKey, hotelKey),
+		visaAgent:       agents.NewVisaDocAgent(openaiKey),
+		socialService:   nil, // Will be set via SetSocialService
+		agentTimeouts:   DefaultAgentTimeouts,
+		registry:        agents.NewRegistry(),
+		converter:       currency.NewConverter(currency.DefaultRates()),
+		defaultCurrency: currency.Base,
 	}
+	o.registerBuiltinAgents()
+	return o
+}
+
+// RegisterAgent routes the agent's supported intents to it, replacing any agent
+// previously registered for the same intents
+func (o *Orchestrator) RegisterAgent(agent agents.Agent) {
+	if err := o.registry.Register(agent); err != nil {
+		log.Printf("Orchestrator: Cannot register agent: %v", err)
+		return
+	}
+	log.Printf("Orchestrator: Registered agent %s for %v", agent.Name(), agent.SupportedIntents())
+}
+
+// VisaAgent returns the visa agent so HTTP handlers can share its data
+func (o *Orchestrator) VisaAgent() *agents.VisaDocAgent {
+	return o.visaAgent
+}
+
+// SetHotelProvider makes the hotel agent search a real hotel inventory
+func (o *Orchestrator) SetHotelProvider(provider agents.HotelProvider) {
+	hotelAgent, ok := o.hotelAgent.(*agents.HotelAgent)
+	if !ok {
+		log.Printf("Orchestrator: Hotel agent does not support providers")
+		return
+	}
+	hotelAgent.SetProvider(provider)
+}
+
+// SetCache shares a cache with the weather and hotel agents so repeated lookups skip the upstream APIs
+func (o *Orchestrator) SetCache(c cache.Cache) {
+	if weatherAgent, ok := o.weatherAgent.(*agents.WeatherAgent); ok {
+		weatherAgent.SetCache(c)
+	}
+	if hotelAgent, ok := o.hotelAgent.(*agents.HotelAgent); ok {
+		hotelAgent.SetCache(c)
+	}
+}
+
+// CacheStats reports how the weather and hotel agents' upstream lookups were served
+func (o *Orchestrator) CacheStats() []cache.Stats {
+	var stats []cache.Stats
+	for _, agent := range []interface{}{o.weatherAgent, o.hotelAgent} {
+		if source, ok := agent.(interface{ CacheStats() cache.Stats }); ok {
+			stats = append(stats, source.CacheStats())
+		}
+	}
+	return stats
+}
+
+// FlightAgent returns the flight agent so HTTP handlers can share its data source
+func (o *Orchestrator) FlightAgent() *agents.FlightAgent {
+	flightAgent, _ := o.flightAgent.(*agents.FlightAgent)
+	return flightAgent
+}
+
+// HotelAgent returns the hotel agent so HTTP handlers can share its provider and cache
+func (o *Orchestrator) HotelAgent() *agents.HotelAgent {
+	hotelAgent, _ := o.hotelAgent.(*agents.HotelAgent)
+	return hotelAgent
+}
+
+// LocalAgent returns the local recommendations agent
+func (o *Orchestrator) LocalAgent() *agents.LocalAgent {
+	return o.localAgent
+}
+
+// Intents lists the intents the orchestrator can currently handle
+func (o *Orchestrator) Intents() []string {
+	return o.registry.Intents()
 }
 
 // SetSocialService sets the social service for the orchestrator
 func (o *Orchestrator) SetSocialService(service interface {
-	GetTopRatedPlaces(keyword, location string, limit int) ([]SocialPlace, error)
+	GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]SocialPlace, error)
 }) {
 	o.socialService = service
 }
 
-// ProcessMessage is the main entry point for handling user messages
-func (o *Orchestrator) ProcessMessage(ctx context.Context, userInput string) (string, error) {
+// SetAgentTimeouts changes the per-agent deadlines used when building a trip plan.
+// Zero values keep the defaults.
+func (o *Orchestrator) SetAgentTimeouts(timeouts AgentTimeouts) {
+	if timeouts.Planner <= 0 {
+		timeouts.Planner = DefaultAgentTimeouts.Planner
+	}
+	if timeouts.Weather <= 0 {
+		timeouts.Weather = DefaultAgentTimeouts.Weather
+	}
+	if timeouts.Hotels <= 0 {
+		timeouts.Hotels = DefaultAgentTimeouts.Hotels
+	}
+	if timeouts.Social <= 0 {
+		timeouts.Social = DefaultAgentTimeouts.Social
+	}
+	if timeouts.Flights <= 0 {
+		timeouts.Flights = DefaultAgentTimeouts.Flights
+	}
+	o.agentTimeouts = timeouts
+}
+
+// SetSessionStore enables multi-turn conversations backed by the given store
+func (o *Orchestrator) SetSessionStore(store SessionStore) {
+	o.sessions = store
+}
+
+// ProcessMessage handles a user message and returns the response rendered as markdown.
+// When sessionID is empty or no session store is configured, each message is handled on its own.
+func (o *Orchestrator) ProcessMessage(ctx context.Context, sessionID, userInput string) (string, error) {
+	response, err := o.Process(ctx, sessionID, userInput)
+	if err != nil {
+		return "", err
+	}
+	return response.Markdown(), nil
+}
+
+// Process is the main entry point for handling user messages and returns the typed result
+func (o *Orchestrator) Process(ctx context.Context, sessionID, userInput string) (*Response, error) {
+	return o.process(ctx, sessionID, userInput, nil)
+}
+
+// process runs a message through intent detection and the matching handler, reporting progress to emit
+func (o *Orchestrator) process(ctx context.Context, sessionID, userInput string, emit EventFunc) (*Response, error) {
 	log.Printf("🚀 Orchestrator: Processing message: %s", userInput)
 
-	// Step 1: Detect intent
-	intentResult, err := o.intentAgent.Detect(ctx, userInput)
+	// Step 1: Load conversation state for follow-ups, and the saved trip the message is about
+	state, err := o.loadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	state = applySavedTrip(ctx, state, sessionID)
+
+	// Step 2: Detect intent
+	intentResult, err := o.intentAgent.DetectWithContext(ctx, userInput, state.PreviousIntent())
 	if err != nil {
 		log.Printf("Orchestrator: Intent detection failed: %v", err)
-		return "", err
+		return nil, err
 	}
 
+	intentResult = resolveFollowUp(intentResult, state)
 	log.Printf("Orchestrator: Detected intent=%s", intentResult.Intent)
+	emit.send(EventIntent, intentResult)
+
+	// Agents work in THB; amounts are converted to the display currency on the way out
+	display := o.displayCurrency(ctx, intentResult.Entities)
+	money := moneyConverter{ctx: ctx, converter: o.converter, to: display}
+	handlerEmit := emit
+	if emit != nil && display != currency.Base {
+		handlerEmit = func(event StreamEvent) { emit(money.event(event)) }
+	}
 
-	// Step 2: Route to appropriate handler
-	var response string
-	switch intentResult.Intent {
-	case "plan_trip":
-		response, err = o.handlePlanTrip(ctx, intentResult)
-	case "weather_check":
-		response, err = o.handleWeatherCheck(ctx, intentResult)
-	case "flight_check":
-		response, err = o.handleFlightCheck(ctx, intentResult)
-	case "hotel_search":
-		response, err = o.handleHotelSearch(ctx, intentResult)
-	case "local_recommendation":
-		response, err = o.handleLocalRecommendation(ctx, intentResult)
-	case "budget_inquiry":
-		response, err = o.handleBudgetInquiry(ctx, intentResult)
-	case "plan_update":
-		response = "Plan update functionality coming soon!"
-	case "general_chat":
-		response = "Hello! I'm your AI travel assistant. I can help you plan trips, check weather, find flights, search hotels, and get local recommendations. What would you like to do?"
-	default:
-		response = "I'm not sure how to help with that. Try asking about planning a trip, checking weather, or finding hotels!"
+	// Step 3: Route to the agent registered for the intent
+	intentResult.Message = userInput
+	var result Result
+	if agent, ok := o.registry.Lookup(intentResult.Intent); ok {
+		result, err = agent.Handle(withTurn(ctx, state, handlerEmit), intentResult)
+	} else {
+		result = &MessageResult{Text: "I'm not sure how to help with that. Try asking about planning a trip, checking weather, or finding hotels!"}
 	}
 
 	if err != nil {
 		log.Printf("Orchestrator: Handler error: %v", err)
-		return "", err
+		return nil, err
+	}
+
+	// Step 4: Remember this turn for the next message
+	if state != nil {
+		switch r := result.(type) {
+		case *TripPlanResult:
+			state.LastPlan = r.Plan
+		case *PlanUpdateResult:
+			state.LastPlan = r.Plan
+		}
+		state.Record(intentResult)
+		o.saveSession(ctx, state)
 	}
 
 	log.Printf("✅ Orchestrator: Response generated successfully")
-	return response, nil
+	return &Response{
+		SessionID: sessionID,
+		Intent:    intentResult.Intent,
+		Entities:  intentResult.Entities,
+		Currency:  display,
+		Result:    money.result(result),
+	}, nil
 }
 
-// handlePlanTrip creates a complete travel plan
-func (o *Orchestrator) handlePlanTrip(ctx context.Context, intent *agents.IntentResult) (string, error) {
+// CheckSession returns ErrSessionNotOwned when the session was started by another user than the one
+// set with WithUser. Unknown sessions are fine; they are started on the first message.
+func (o *Orchestrator) CheckSession(ctx context.Context, sessionID string) error {
+	_, err := o.loadSession(ctx, sessionID)
+	return err
+}
+
+// loadSession fetches or creates the conversation state for a session of the user in ctx.
+// Anonymous sessions are taken over by the first signed-in user to continue them.
+func (o *Orchestrator) loadSession(ctx context.Context, sessionID string) (*ConversationState, error) {
+	if o.sessions == nil || sessionID == "" {
+		return nil, nil
+	}
+
+	userID := userFromContext(ctx)
+	state, err := o.sessions.Load(ctx, sessionID)
+	if err != nil {
+		log.Printf("Orchestrator: Failed to load session %s: %v", sessionID, err)
+	}
+	if state == nil {
+		state = NewConversationState(sessionID)
+	}
+	if state.UserID != "" && state.UserID != userID {
+		return nil, ErrSessionNotOwned
+	}
+	state.UserID = userID
+	return state, nil
+}
+
+// saveSession persists the conversation state, logging failures instead of failing the request
+func (o *Orchestrator) saveSession(ctx context.Context, state *ConversationState) {
+	// States started for a saved trip outside a session are not kept
+	if o.sessions == nil || state.SessionID == "" {
+		return
+	}
+	if err := o.sessions.Save(ctx, state); err != nil {
+		log.Printf("Orchestrator: Failed to save session %s: %v", state.SessionID, err)
+	}
+}
+
+// resolveFollowUp merges entities from earlier turns into the new intent.
+// A message without a clear intent that only changes entities (e.g. "make it 5 days instead")
+// is treated as a follow-up of the previous intent.
+func resolveFollowUp(intent *agents.IntentResult, state *ConversationState) *agents.IntentResult {
+	if state == nil || state.LastIntent == "" {
+		return intent
+	}
+
+	newEntities := 0
+	merged := make(map[string]interface{}, len(state.Entities)+len(intent.Entities))
+	for key, value := range state.Entities {
+		merged[key] = value
+	}
+	for key, value := range intent.Entities {
+		if !isEmptyEntity(value) {
+			merged[key] = value
+			newEntities++
+		}
+	}
+	// A new budget without a currency is not in the currency of the old one
+	if !isEmptyEntity(intent.Entities["budget"]) && isEmptyEntity(intent.Entities["budget_currency"]) {
+		delete(merged, "budget_currency")
+	}
+
+	resolved := &agents.IntentResult{
+		Intent:   intent.Intent,
+		Entities: merged,
+	}
+	if intent.Intent == "general_chat" && newEntities > 0 {
+		log.Printf("Orchestrator: Treating message as follow-up to %s", state.LastIntent)
+		resolved.Intent = state.LastIntent
+	}
+	return resolved
+}
+
+// handlePlanTrip creates a complete travel plan.
+// The planner, weather, hotel and social agents run concurrently, each with its own deadline;
+// only the planner is required, the other sections are left empty when their agent fails.
+func (o *Orchestrator) handlePlanTrip(ctx context.Context, intent *agents.IntentResult, emit EventFunc) (*TripPlanResult, error) {
 	// Extract entities
 	destination := o.getStringEntity(intent.Entities, "destination", "Unknown")
 	duration := o.getIntEntity(intent.Entities, "duration", 7)
-	budget := o.getFloatEntity(intent.Entities, "budget", 50000)
+	if duration < 1 {
+		// "0 days" still needs a day to plan and to split the budget over
+		duration = 1
+	}
+	budget := o.budgetEntity(ctx, intent.Entities, 50000)
 
-	log.Printf("Creating plan: destination=%s, duration=%d days, budget=%.0f THB", 
+	log.Printf("Creating plan: destination=%s, duration=%d days, budget=%.0f THB",
 		destination, duration, budget)
 
-	// Create itinerary
-	plan, err := o.plannerAgent.CreatePlan(ctx, destination, duration, budget)
-	if err != nil {
-		return "", err
+	result := &TripPlanResult{
+		Destination:  destination,
+		Duration:     duration,
+		Budget:       budget,
+		Hotels:       []agents.HotelRecommendation{},
+		PopularSpots: []SocialPlace{},
+		Flights:      []agents.FlightOption{},
+		Agents:       []AgentRun{},
 	}
 
-	// Get weather forecast
-	weather, err := o.weatherAgent.GetForecast(ctx, destination)
-	if err != nil {
-		log.Printf("Weather check failed: %v", err)
-	}
+	// Fan out: every agent reports back on the same channel
+	outcomes := make(chan agentOutcome, 5)
+	pending := 4
 
-	// Search for hotels
-	hotels, err := o.hotelAgent.SearchHotels(ctx, destination, budget/float64(duration))
-	if err != nil {
-		log.Printf("Hotel search failed: %v", err)
-	}
-
-	// Build response
-	response := fmt.Sprintf("# %d-Day Trip to %s\n\n", duration, destination)
-	response += fmt.Sprintf("**Budget:** %.0f THB\n\n", budget)
-	
-	response += "## Itinerary\n"
-	for _, day := range plan.Itinerary {
-		response += fmt.Sprintf("\n**Day %d:**\n", day.Day)
-		for _, activity := range day.Activities {
-			response += fmt.Sprintf("- %s\n", activity)
-		}
-		response += fmt.Sprintf("*Daily Budget: %.0f THB*\n", day.Budget)
+	go runAgent(ctx, agentPlanner, o.agentTimeouts.Planner, func(ctx context.Context) (interface{}, error) {
+		return o.plannerAgent.CreatePlan(ctx, destination, duration, budget)
+	}, outcomes)
+
+	go runAgent(ctx, agentWeather, o.agentTimeouts.Weather, func(ctx context.Context) (interface{}, error) {
+		return o.weatherAgent.GetForecast(ctx, destination)
+	}, outcomes)
+
+	go runAgent(ctx, agentHotels, o.agentTimeouts.Hotels, func(ctx context.Context) (interface{}, error) {
+		return o.hotelAgent.Search(ctx, o.hotelSearchRequest(intent.Entities, destination, budget/float64(duration), duration))
+	}, outcomes)
+
+	go runAgent(ctx, agentFlights, o.agentTimeouts.Flights, func(ctx context.Context) (interface{}, error) {
+		return o.flightAgent.SearchFlights(ctx, o.flightSearchRequest(intent.Entities, destination))
+	}, outcomes)
+
+	if o.socialService != nil {
+		pending++
+		go runAgent(ctx, agentSocial, o.agentTimeouts.Social, func(ctx context.Context) (interface{}, error) {
+			return o.socialService.GetTopRatedPlaces(ctx, "tourist attractions", destination, 5)
+		}, outcomes)
+	} else {
+		result.Agents = append(result.Agents, AgentRun{Agent: agentSocial, Status: AgentStatusSkipped})
+		emit.send(EventPopularSpots, result.PopularSpots)
 	}
 
-	if weather != nil {
-		response += fmt.Sprintf("\n## Weather Forecast\n")
-		response += fmt.Sprintf("Current: %.0f°C, %s\n", weather.Temperature, weather.Condition)
-		if weather.RainProb > 60 {
-			response += fmt.Sprintf("\n⚠️ %s\n", weather.Suggestion)
+	// Fan in: assemble whatever comes back before each deadline
+	for i := 0; i < pending; i++ {
+		outcome := <-outcomes
+		result.Agents = append(result.Agents, outcome.run)
+		if outcome.run.Status != AgentStatusOK {
+			log.Printf("Agent %s %s after %dms: %s", outcome.run.Agent, outcome.run.Status, outcome.run.DurationMs, outcome.run.Error)
 		}
-	}
 
-	if len(hotels) > 0 {
-		response += fmt.Sprintf("\n## Recommended Hotels\n")
-		for i, hotel := range hotels {
-			if i < 3 {
-				response += fmt.Sprintf("- **%s** - %.0f THB/night (Rating: %.1f★)\n", 
-					hotel.Name, hotel.PricePerNight, hotel.Rating)
+		switch outcome.run.Agent {
+		case agentPlanner:
+			plan, _ := outcome.value.(*agents.TripPlan)
+			if outcome.run.Status != AgentStatusOK || plan == nil {
+				return nil, fmt.Errorf("planner agent %s: %s", outcome.run.Status, outcome.run.Error)
+			}
+			result.Plan = plan
+			emit.send(EventItinerary, plan)
+		case agentWeather:
+			if weather, ok := outcome.value.(*agents.WeatherForecast); ok && weather != nil {
+				result.Weather = weather
+			}
+			emit.send(EventWeather, result.Weather)
+		case agentHotels:
+			if hotels, ok := outcome.value.([]agents.HotelRecommendation); ok && len(hotels) > 0 {
+				result.Hotels = hotels
 			}
+			emit.send(EventHotels, result.Hotels)
+		case agentSocial:
+			if places, ok := outcome.value.([]SocialPlace); ok && len(places) > 0 {
+				result.PopularSpots = places
+			}
+			emit.send(EventPopularSpots, result.PopularSpots)
+		case agentFlights:
+			if flights, ok := outcome.value.([]agents.FlightOption); ok && len(flights) > 0 {
+				result.Flights = flights
+			}
+			emit.send(EventFlights, result.Flights)
 		}
 	}
 
-	// Get socially popular spots
-	if o.socialService != nil {
-		socialPlaces, err := o.socialService.GetTopRatedPlaces("tourist attractions", destination, 5)
-		if err == nil && len(socialPlaces) > 0 {
-			response += fmt.Sprintf("\n## Socially Popular Spots\n")
-			response += "*Top-rated places based on reviews*\n\n"
-			for i, place := range socialPlaces {
-				if i < 5 {
-					response += fmt.Sprintf("- **%s** (%.1f★, %d reviews)\n", 
-						place.Name, place.Rating, place.ReviewCount)
-				}
-			}
+	// Stream the summary so clients can render it while it is written
+	if emit != nil {
+		summary, err := o.plannerAgent.StreamSummary(ctx, result.Plan, func(token string) {
+			emit.send(EventSummaryToken, token)
+		})
+		if err != nil {
+			log.Printf("Summary streaming failed: %v", err)
+		} else if summary != "" {
+			result.Plan.Summary = summary
 		}
 	}
 
-	response += fmt.Sprintf("\n%s", plan.Summary)
+	return result, nil
+}
+
+// handlePlanUpdate applies the requested change to the session's current plan
+func (o *Orchestrator) handlePlanUpdate(ctx context.Context, state *ConversationState, request string) (Result, error) {
+	if state == nil || state.LastPlan == nil {
+		return &MessageResult{Text: "I don't have a plan to update yet. Tell me where you'd like to go first, for example: 'Plan a 5-day trip to Tokyo with 50,000 THB'."}, nil
+	}
+
+	currentPlan := state.LastPlan
+	log.Printf("Updating plan for %s: %s", currentPlan.Destination, request)
 
-	return response, nil
+	updatedPlan, err := o.plannerAgent.UpdatePlan(ctx, currentPlan, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PlanUpdateResult{
+		Request:        request,
+		PreviousBudget: currentPlan.TotalBudget,
+		Plan:           updatedPlan,
+		Changes:        agents.DiffPlans(currentPlan, updatedPlan),
+	}, nil
 }
 
 // handleWeatherCheck gets weather forecast for a city
-func (o *Orchestrator) handleWeatherCheck(ctx context.Context, intent *agents.IntentResult) (string, error) {
+func (o *Orchestrator) handleWeatherCheck(ctx context.Context, intent *agents.IntentResult) (*WeatherResult, error) {
 	city := o.getStringEntity(intent.Entities, "destination", "Bangkok")
 
 	log.Printf("Checking weather for: %s", city)
 
 	forecast, err := o.weatherAgent.GetForecast(ctx, city)
 	if err != nil {
-		return "", err
-	}
-
-	response := fmt.Sprintf("# Weather Forecast for %s\n\n", city)
-	response += fmt.Sprintf("**Current:** %.0f°C, %s\n\n", forecast.Temperature, forecast.Condition)
-	response += "## 3-Day Forecast\n"
-	
-	for _, day := range forecast.Forecast {
-		response += fmt.Sprintf("- %s: %.0f°C, %s (Rain: %.0f%%)\n", 
-			day.Date, day.Temperature, day.Condition, day.RainProb)
-	}
-
-	if forecast.RainProb > 60 {
-		response += fmt.Sprintf("\n⚠️ **Rain Alert:** %s\n", forecast.Suggestion)
+		return nil, err
 	}
 
-	return response, nil
+	return &WeatherResult{City: city, Forecast: forecast}, nil
 }
 
 // handleFlightCheck checks flight status
-func (o *Orchestrator) handleFlightCheck(ctx context.Context, intent *agents.IntentResult) (string, error) {
+func (o *Orchestrator) handleFlightCheck(ctx context.Context, intent *agents.IntentResult) (Result, error) {
 	flightCode := o.getStringEntity(intent.Entities, "flight_code", "")
-	
+
 	if flightCode == "" {
-		return "Please provide a flight code (e.g., 'Is flight JL708 on time?')", nil
+		return &MessageResult{Text: "Please provide a flight code (e.g., 'Is flight JL708 on time?')"}, nil
 	}
 
 	log.Printf("Checking flight: %s", flightCode)
 
 	status, err := o.flightAgent.CheckFlight(ctx, flightCode)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	response := fmt.Sprintf("# Flight %s Status\n\n", status.FlightCode)
-	response += fmt.Sprintf("**Status:** %s\n", strings.Title(status.Status))
-	response += fmt.Sprintf("**Departure:** %s\n", status.DepartureTime)
-	response += fmt.Sprintf("**Arrival:** %s\n", status.ArrivalTime)
-	
-	if status.Gate != "" {
-		response += fmt.Sprintf("**Gate:** %s\n", status.Gate)
+	return &FlightStatusResult{Status: status}, nil
+}
+
+// handleFlightSearch finds ranked flight options for a route
+func (o *Orchestrator) handleFlightSearch(ctx context.Context, intent *agents.IntentResult) (Result, error) {
+	destination := o.getStringEntity(intent.Entities, "destination", "")
+	if destination == "" {
+		return &MessageResult{Text: "Where would you like to fly? For example: 'Find flights from Bangkok to Tokyo on 2025-05-01'."}, nil
 	}
 
-	if status.DelayMinutes > 0 {
-		response += fmt.Sprintf("\n⚠️ **Delayed by %d minutes**\n\n", status.DelayMinutes)
+	request := o.flightSearchRequest(intent.Entities, destination).WithDefaults()
+	log.Printf("Searching flights: %s → %s on %s", request.Origin, request.Destination, request.Date.Format("2006-01-02"))
+
+	options, err := o.flightAgent.SearchFlights(ctx, request)
+	if err != nil {
+		return &MessageResult{Text: fmt.Sprintf("I couldn't search flights for that route: %v", err)}, nil
 	}
 
-	response += fmt.Sprintf("\n%s", status.Notification)
+	return &FlightSearchResult{Search: request, Options: options}, nil
+}
 
-	return response, nil
+// flightSearchRequest builds a flight search from intent entities: origin (default Bangkok),
+// date_from and travelers
+func (o *Orchestrator) flightSearchRequest(entities map[string]interface{}, destination string) agents.FlightSearchRequest {
+	request := agents.FlightSearchRequest{
+		Origin:      o.getStringEntity(entities, "origin", "Bangkok"),
+		Destination: destination,
+		Passengers:  o.getIntEntity(entities, "travelers", 0),
+		SortBy:      agents.NormalizeFlightSort(o.getStringEntity(entities, "sort_by", "")),
+	}
+	if directOnly, ok := entities["direct_only"].(bool); ok {
+		request.DirectOnly = directOnly
+	}
+	if date, err := time.Parse("2006-01-02", o.getStringEntity(entities, "date_from", "")); err == nil {
+		request.Date = date
+	}
+	return request
 }
 
 // handleHotelSearch searches for hotels
-func (o *Orchestrator) handleHotelSearch(ctx context.Context, intent *agents.IntentResult) (string, error) {
+func (o *Orchestrator) handleHotelSearch(ctx context.Context, intent *agents.IntentResult) (*HotelListResult, error) {
 	destination := o.getStringEntity(intent.Entities, "destination", "Bangkok")
-	budget := o.getFloatEntity(intent.Entities, "budget", 3000)
+	budget := o.budgetEntity(ctx, intent.Entities, 3000)
+	request := o.hotelSearchRequest(intent.Entities, destination, budget, 0).WithDefaults()
 
 	log.Printf("Searching hotels in %s, budget: %.0f THB/night", destination, budget)
 
-	hotels, err := o.hotelAgent.SearchHotels(ctx, destination, budget)
+	hotels, err := o.hotelAgent.Search(ctx, request)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	response := fmt.Sprintf("# Hotels in %s\n\n", destination)
-	response += fmt.Sprintf("Budget: Up to %.0f THB per night\n\n", budget)
+	return &HotelListResult{
+		Destination: destination,
+		Budget:      budget,
+		Search:      request,
+		Hotels:      hotels,
+	}, nil
+}
 
-	for i, hotel := range hotels {
-		response += fmt.Sprintf("%d. **%s**\n", i+1, hotel.Name)
-		response += fmt.Sprintf("   - Price: %.0f THB/night\n", hotel.PricePerNight)
-		response += fmt.Sprintf("   - Rating: %.1f★\n", hotel.Rating)
-		response += fmt.Sprintf("   - Distance: %.1f km from center\n", hotel.Distance)
-		response += fmt.Sprintf("   - Address: %s\n\n", hotel.Address)
+// hotelSearchRequest builds a hotel search from intent entities: date_from/date_to, travelers,
+// stars, amenities, neighborhood, refundable and sort_by. Without date_to the stay lasts nights
+// (when set) after date_from.
+func (o *Orchestrator) hotelSearchRequest(entities map[string]interface{}, destination string, budgetPerNight float64, nights int) agents.HotelSearchRequest {
+	request := agents.HotelSearchRequest{
+		City:           destination,
+		BudgetPerNight: budgetPerNight,
+		Guests:         o.getIntEntity(entities, "travelers", 0),
+		MinStars:       o.getIntEntity(entities, "stars", 0),
+		MinRating:      o.getFloatEntity(entities, "min_rating", 0),
+		Amenities:      stringListEntity(entities, "amenities"),
+		Neighborhood:   o.getStringEntity(entities, "neighborhood", ""),
+		SortBy:         agents.NormalizeHotelSort(o.getStringEntity(entities, "sort_by", "")),
+	}
+	if refundable, ok := entities["refundable"].(bool); ok {
+		request.RefundableOnly = refundable
 	}
 
-	return response, nil
+	if checkIn, err := time.Parse("2006-01-02", o.getStringEntity(entities, "date_from", "")); err == nil {
+		request.CheckIn = checkIn
+		if checkOut, err := time.Parse("2006-01-02", o.getStringEntity(entities, "date_to", "")); err == nil {
+			request.CheckOut = checkOut
+		} else if nights > 0 {
+			request.CheckOut = checkIn.AddDate(0, 0, nights)
+		}
+	}
+
+	return request
+}
+
+// stringListEntity reads a list entity given as a JSON array or a comma-separated string
+func stringListEntity(entities map[string]interface{}, key string) []string {
+	var values []string
+	switch val := entities[key].(type) {
+	case []interface{}:
+		for _, item := range val {
+			if text, ok := item.(string); ok && strings.TrimSpace(text) != "" {
+				values = append(values, strings.TrimSpace(text))
+			}
+		}
+	case []string:
+		values = append(values, val...)
+	case string:
+		for _, item := range strings.Split(val, ",") {
+			if strings.TrimSpace(item) != "" {
+				values = append(values, strings.TrimSpace(item))
+			}
+		}
+	}
+	return values
 }
 
 // handleLocalRecommendation finds nearby places
-func (o *Orchestrator) handleLocalRecommendation(ctx context.Context, intent *agents.IntentResult) (string, error) {
+func (o *Orchestrator) handleLocalRecommendation(ctx context.Context, intent *agents.IntentResult) (*LocalPlacesResult, error) {
 	interest := o.getStringEntity(intent.Entities, "interests", "restaurant")
 	destination := o.getStringEntity(intent.Entities, "destination", "")
-	
-	// Get location from entities
+
+	// Get location from entities, then from the destination
 	lat := 13.7563 // Default Bangkok
 	lng := 100.5018
-	
+	if destLat, destLng, ok := airports.Coordinates(destination); ok {
+		lat, lng = destLat, destLng
+	}
+
 	if loc, ok := intent.Entities["location"].(map[string]interface{}); ok {
 		if latVal, ok := loc["lat"].(float64); ok {
 			lat = latVal
@@ -284,54 +618,57 @@ func (o *Orchestrator) handleLocalRecommendation(ctx context.Context, intent *ag
 
 	places, err := o.localAgent.GetRecommendations(ctx, lat, lng, interest)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	response := fmt.Sprintf("# Nearby %s Recommendations\n\n", strings.Title(interest))
-
-	// Add AI-generated recommendations
-	for i, place := range places {
-		response += fmt.Sprintf("%d. **%s**\n", i+1, place.Name)
-		response += fmt.Sprintf("   - Type: %s\n", place.Type)
-		response += fmt.Sprintf("   - Rating: %.1f★\n", place.Rating)
-		response += fmt.Sprintf("   - Distance: %.1f km away\n", place.DistanceKm)
-		response += fmt.Sprintf("   - Address: %s\n\n", place.Address)
+	result := &LocalPlacesResult{
+		Interest:     interest,
+		Destination:  destination,
+		Latitude:     lat,
+		Longitude:    lng,
+		Places:       places,
+		PopularSpots: []SocialPlace{},
 	}
 
 	// Add socially popular spots if available and destination is provided
 	if o.socialService != nil && destination != "" {
-		socialPlaces, err := o.socialService.GetTopRatedPlaces(interest, destination, 3)
+		socialPlaces, err := o.socialService.GetTopRatedPlaces(ctx, interest, destination, 3)
 		if err == nil && len(socialPlaces) > 0 {
-			response += fmt.Sprintf("\n## Socially Popular %s in %s\n", strings.Title(interest), destination)
-			response += "*Top-rated by the community*\n\n"
-			for i, place := range socialPlaces {
-				response += fmt.Sprintf("%d. **%s** (%.1f★, %d reviews)\n", 
-					len(places)+i+1, place.Name, place.Rating, place.ReviewCount)
-				response += fmt.Sprintf("   - Address: %s\n\n", place.Address)
-			}
+			result.PopularSpots = socialPlaces
 		}
 	}
 
-	return response, nil
+	return result, nil
 }
 
-// handleBudgetInquiry provides budget breakdown
-func (o *Orchestrator) handleBudgetInquiry(ctx context.Context, intent *agents.IntentResult) (string, error) {
-	budget := o.getFloatEntity(intent.Entities, "budget", 50000)
-
-	budgetPlan := agents.EstimateBudget(int(budget))
-
-	response := fmt.Sprintf("# Budget Breakdown for %.0f THB\n\n", budget)
-	response += fmt.Sprintf("- **Flights:** %d THB (45%%)\n", budgetPlan.Flight)
-	response += fmt.Sprintf("- **Hotels:** %d THB (25%%)\n", budgetPlan.Hotel)
-	response += fmt.Sprintf("- **Food:** %d THB (15%%)\n", budgetPlan.Food)
-	response += fmt.Sprintf("- **Transport:** %d THB (10%%)\n", budgetPlan.Transport)
-	response += fmt.Sprintf("- **Miscellaneous:** %d THB (5%%)\n", budgetPlan.Misc)
-
-	total := budgetPlan.Flight + budgetPlan.Hotel + budgetPlan.Food + budgetPlan.Transport + budgetPlan.Misc
-	response += fmt.Sprintf("\n**Total:** %d THB\n", total)
+// handleBudgetInquiry prices a trip to the destination in the traveler's style and checks it against
+// their budget. Without a destination the budget is split into fixed shares.
+func (o *Orchestrator) handleBudgetInquiry(ctx context.Context, intent *agents.IntentResult) (*BudgetResult, error) {
+	destination := o.getStringEntity(intent.Entities, "destination", "")
+	if destination == "" {
+		budget := o.budgetEntity(ctx, intent.Entities, 50000)
+		return &BudgetResult{
+			Total: budget,
+			Plan:  agents.EstimateBudget(int(budget)),
+		}, nil
+	}
 
-	return response, nil
+	estimate := agents.EstimateTripBudget(agents.BudgetRequest{
+		Destination: destination,
+		Origin:      o.getStringEntity(intent.Entities, "origin", ""),
+		Days:        o.getIntEntity(intent.Entities, "duration", 0),
+		Travelers:   o.getIntEntity(intent.Entities, "travelers", 0),
+		Style:       o.getStringEntity(intent.Entities, "style", ""),
+		Total:       o.budgetEntity(ctx, intent.Entities, 0),
+	})
+	log.Printf("Budget estimate: destination=%s, days=%d, style=%s, required=%.0f THB, verdict=%s",
+		estimate.Destination, estimate.Days, estimate.Style, estimate.Required, estimate.Verdict)
+
+	return &BudgetResult{
+		Total:    estimate.Total,
+		Plan:     estimate.BudgetPlan(),
+		Estimate: &estimate,
+	}, nil
 }
 
 // Helper methods to extract entities safely
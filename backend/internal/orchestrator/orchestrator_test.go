@@ -6,6 +6,7 @@ import (
 "testing"
 "time"
 
+"github.com/smithisrealdev/travel-ai-agent/backend/agents"
 "github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +18,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "I want to visit Vancouver for 7 days with 100,000 THB budget"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -31,7 +32,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "What's the weather in Bangkok?"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -45,7 +46,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "Is flight JL708 on time?"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -59,7 +60,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "Find hotels in Tokyo"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -73,7 +74,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "Find good ramen restaurants nearby"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -89,7 +90,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "What can I do with 50000 baht budget?"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -103,7 +104,7 @@ ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 defer cancel()
 
 message := "Hello!"
-response, err := orch.ProcessMessage(ctx, message)
+response, err := orch.ProcessMessage(ctx, "", message)
 
 assert.NoError(t, err, "ProcessMessage should not error")
 assert.NotEmpty(t, response, "Response should not be empty")
@@ -133,3 +134,95 @@ assert.Equal(t, 7, duration, "Should extract duration")
 missing := orch.getStringEntity(entities, "missing", "Default")
 assert.Equal(t, "Default", missing, "Should return default for missing entity")
 }
+
+func TestOrchestrator_HotelSearchRequestFromEntities(t *testing.T) {
+	orch := New("", "", "", "")
+
+	request := orch.hotelSearchRequest(map[string]interface{}{
+		"date_from":    "2025-05-01",
+		"date_to":      "2025-05-04",
+		"travelers":    3.0,
+		"stars":        4.0,
+		"amenities":    []interface{}{"pool", "breakfast"},
+		"neighborhood": "Shinjuku",
+		"refundable":   true,
+		"sort_by":      "best rated",
+	}, "Tokyo", 3000, 0)
+
+	assert.Equal(t, "Tokyo", request.City)
+	assert.Equal(t, "2025-05-01", request.CheckIn.Format("2006-01-02"))
+	assert.Equal(t, "2025-05-04", request.CheckOut.Format("2006-01-02"))
+	assert.Equal(t, 3, request.Guests)
+	assert.Equal(t, 4, request.MinStars)
+	assert.Equal(t, []string{"pool", "breakfast"}, request.Amenities)
+	assert.Equal(t, "Shinjuku", request.Neighborhood)
+	assert.True(t, request.RefundableOnly)
+	assert.Equal(t, agents.HotelSortRating, request.SortBy)
+	assert.Equal(t, 3000.0, request.BudgetPerNight)
+
+	// A trip plan without date_to stays for the trip duration
+	request = orch.hotelSearchRequest(map[string]interface{}{"date_from": "2025-05-01"}, "Tokyo", 3000, 5)
+	assert.Equal(t, "2025-05-06", request.CheckOut.Format("2006-01-02"))
+}
+
+func TestOrchestrator_ProcessMessage_HotelSearchWithFilters(t *testing.T) {
+	orch := New("", "", "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := orch.Process(ctx, "", "Find a 4-star hotel in Tokyo with a pool for 3 people from 2025-05-01 to 2025-05-04")
+	assert.NoError(t, err)
+
+	result, ok := response.Result.(*HotelListResult)
+	if assert.True(t, ok) {
+		assert.Len(t, result.Hotels, 3)
+		for _, hotel := range result.Hotels {
+			assert.GreaterOrEqual(t, hotel.Stars, 4)
+			assert.True(t, hotel.HasAmenity("pool"))
+		}
+	}
+
+	markdown := response.Markdown()
+	assert.Contains(t, markdown, "Stay: 2025-05-01 to 2025-05-04 (3 nights), 3 guests, 2 rooms")
+	assert.Contains(t, markdown, "Filters: 4+ stars, pool")
+	assert.Contains(t, markdown, "Amenities:")
+}
+
+func TestOrchestrator_ProcessMessage_FlightSearch(t *testing.T) {
+	orch := New("", "", "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	response, err := orch.Process(ctx, "", "Find the cheapest flights from Tokyo to Seoul on 2025-05-01 for 2 passengers")
+	assert.NoError(t, err)
+
+	result, ok := response.Result.(*FlightSearchResult)
+	if assert.True(t, ok) {
+		assert.Equal(t, "NRT", result.Search.Origin)
+		assert.Equal(t, "ICN", result.Search.Destination)
+		assert.Equal(t, 2, result.Search.Passengers)
+		assert.NotEmpty(t, result.Options)
+		for i := 1; i < len(result.Options); i++ {
+			assert.LessOrEqual(t, result.Options[i-1].PricePerPassenger, result.Options[i].PricePerPassenger)
+		}
+	}
+
+	markdown := response.Markdown()
+	assert.Contains(t, markdown, "# Flights NRT → ICN")
+	assert.Contains(t, markdown, "Date: 2025-05-01, 2 passenger(s)")
+	assert.Contains(t, markdown, "1. **")
+}
+
+func TestOrchestrator_LocalRecommendationUsesDestinationCoordinates(t *testing.T) {
+	orch := New("", "", "", "")
+
+	result, err := orch.handleLocalRecommendation(context.Background(), &agents.IntentResult{
+		Intent:   "local_recommendation",
+		Entities: map[string]interface{}{"destination": "Tokyo", "interests": "ramen"},
+	})
+	assert.NoError(t, err)
+	assert.InDelta(t, 35.68, result.Latitude, 0.01)
+	assert.InDelta(t, 139.65, result.Longitude, 0.01)
+}
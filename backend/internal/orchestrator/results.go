@@ -0,0 +1,524 @@
+package orchestrator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+)
+
+// Result is the typed outcome of an orchestrator handler
+type Result = agents.Result
+
+// Response is the complete outcome of processing a message
+type Response struct {
+	SessionID string                 `json:"session_id,omitempty"`
+	Intent    string                 `json:"intent"`
+	Entities  map[string]interface{} `json:"entities,omitempty"`
+	Currency  string                 `json:"currency,omitempty"`
+	Result    Result                 `json:"data"`
+}
+
+// Markdown renders the response's result
+func (r *Response) Markdown() string {
+	if r == nil || r.Result == nil {
+		return ""
+	}
+	return r.Result.Markdown()
+}
+
+// MessageResult is a plain text reply, used for chat and guidance messages
+type MessageResult struct {
+	Text string `json:"text"`
+}
+
+// Type implements Result
+func (r *MessageResult) Type() string { return "message" }
+
+// Markdown implements Result
+func (r *MessageResult) Markdown() string { return r.Text }
+
+// TripPlanResult is a complete trip plan with weather, hotels and popular spots
+type TripPlanResult struct {
+	Destination  string                       `json:"destination"`
+	Duration     int                          `json:"duration"`
+	Budget       float64                      `json:"budget"`
+	Plan         *agents.TripPlan             `json:"plan"`
+	Weather      *agents.WeatherForecast      `json:"weather,omitempty"`
+	Hotels       []agents.HotelRecommendation `json:"hotels"`
+	PopularSpots []SocialPlace                `json:"popular_spots"`
+	Flights      []agents.FlightOption        `json:"flights"`
+	Agents       []AgentRun                   `json:"agents"`
+	Currency     string                       `json:"currency,omitempty"`
+}
+
+// Type implements Result
+func (r *TripPlanResult) Type() string { return "trip_plan" }
+
+// Markdown implements Result
+func (r *TripPlanResult) Markdown() string {
+	var md strings.Builder
+	code := displayCode(r.Currency)
+
+	md.WriteString(fmt.Sprintf("# %d-Day Trip to %s\n\n", r.Duration, r.Destination))
+	md.WriteString(fmt.Sprintf("**Budget:** %s\n\n", currency.Format(r.Budget, code)))
+
+	md.WriteString("## Itinerary\n")
+	writeItinerary(&md, r.Plan.Itinerary, code)
+
+	if r.Weather != nil {
+		md.WriteString("\n## Weather Forecast\n")
+		md.WriteString(fmt.Sprintf("Current: %.0f°C, %s\n", r.Weather.Temperature, r.Weather.Condition))
+		if r.Weather.RainProb > 60 {
+			md.WriteString(fmt.Sprintf("\n⚠️ %s\n", r.Weather.Suggestion))
+		}
+	}
+
+	if len(r.Hotels) > 0 {
+		md.WriteString("\n## Recommended Hotels\n")
+		for i, hotel := range r.Hotels {
+			if i < 3 {
+				md.WriteString(fmt.Sprintf("- **%s** - %s/night (Rating: %.1f★)\n",
+					hotel.Name, currency.Format(hotel.PricePerNight, displayCode(hotel.Currency)), hotel.Rating))
+			}
+		}
+	}
+
+	if len(r.Flights) > 0 {
+		md.WriteString("\n## Flight Options\n")
+		for i, flight := range r.Flights {
+			if i < 3 {
+				md.WriteString(fmt.Sprintf("- **%s** %s - %s, %s, %s\n",
+					flight.Airline, flight.FlightNumber, currency.Format(flight.PricePerPassenger, displayCode(flight.Currency)), flight.Duration(), stopsLabel(flight.Stops)))
+			}
+		}
+	}
+
+	if len(r.PopularSpots) > 0 {
+		md.WriteString("\n## Socially Popular Spots\n")
+		md.WriteString("*Top-rated places based on reviews*\n\n")
+		for i, place := range r.PopularSpots {
+			if i < 5 {
+				md.WriteString(fmt.Sprintf("- **%s** (%.1f★, %d reviews)\n",
+					place.Name, place.Rating, place.ReviewCount))
+			}
+		}
+	}
+
+	if slow := r.timedOutAgents(); len(slow) > 0 {
+		md.WriteString(fmt.Sprintf("\n_Some details are missing because the %s lookup did not respond in time._\n",
+			strings.Join(slow, " and ")))
+	}
+
+	md.WriteString(fmt.Sprintf("\n%s", r.Plan.Summary))
+
+	return md.String()
+}
+
+// timedOutAgents lists the sub-agents that missed their deadline, in fan-out order
+func (r *TripPlanResult) timedOutAgents() []string {
+	var slow []string
+	for _, name := range []string{agentPlanner, agentWeather, agentHotels, agentSocial, agentFlights} {
+		for _, run := range r.Agents {
+			if run.Agent == name && run.Status == AgentStatusTimeout {
+				slow = append(slow, run.Agent)
+			}
+		}
+	}
+	return slow
+}
+
+// PlanUpdateResult is a revised plan with the day-by-day changes
+type PlanUpdateResult struct {
+	Request        string                 `json:"request"`
+	PreviousBudget float64                `json:"previous_budget"`
+	Plan           *agents.TripPlan       `json:"plan"`
+	Changes        []agents.PlanDayChange `json:"changes"`
+	Currency       string                 `json:"currency,omitempty"`
+}
+
+// Type implements Result
+func (r *PlanUpdateResult) Type() string { return "plan_update" }
+
+// Markdown implements Result
+func (r *PlanUpdateResult) Markdown() string {
+	var md strings.Builder
+	code := displayCode(r.Currency)
+
+	md.WriteString(fmt.Sprintf("# Updated %d-Day Trip to %s\n\n", r.Plan.Duration, r.Plan.Destination))
+	md.WriteString(fmt.Sprintf("**Requested change:** %s\n\n", r.Request))
+	if r.Plan.TotalBudget != r.PreviousBudget {
+		md.WriteString(fmt.Sprintf("**Budget:** %s → %s\n\n", currency.Format(r.PreviousBudget, code), currency.Format(r.Plan.TotalBudget, code)))
+	} else {
+		md.WriteString(fmt.Sprintf("**Budget:** %s\n\n", currency.Format(r.Plan.TotalBudget, code)))
+	}
+
+	if len(r.Changes) == 0 {
+		md.WriteString("No changes were needed — your itinerary already matches this request.\n")
+		return md.String()
+	}
+
+	md.WriteString("## What Changed\n```diff\n")
+	for _, change := range r.Changes {
+		switch change.Status {
+		case "added":
+			md.WriteString(fmt.Sprintf("@@ Day %d (new) @@\n", change.Day))
+		case "removed":
+			md.WriteString(fmt.Sprintf("@@ Day %d (removed) @@\n", change.Day))
+		default:
+			md.WriteString(fmt.Sprintf("@@ Day %d @@\n", change.Day))
+		}
+		for _, activity := range change.Removed {
+			md.WriteString(fmt.Sprintf("- %s\n", activity))
+		}
+		for _, activity := range change.Added {
+			md.WriteString(fmt.Sprintf("+ %s\n", activity))
+		}
+		if change.Status == "modified" && change.BudgetBefore != change.BudgetAfter {
+			md.WriteString(fmt.Sprintf("  Daily budget: %s -> %s\n", currency.Format(change.BudgetBefore, code), currency.Format(change.BudgetAfter, code)))
+		}
+	}
+	md.WriteString("```\n")
+
+	md.WriteString("\n## Updated Itinerary\n")
+	writeItinerary(&md, r.Plan.Itinerary, code)
+
+	return md.String()
+}
+
+// WeatherResult is a weather forecast for a city
+type WeatherResult struct {
+	City     string                  `json:"city"`
+	Forecast *agents.WeatherForecast `json:"forecast"`
+}
+
+// Type implements Result
+func (r *WeatherResult) Type() string { return "weather_forecast" }
+
+// Markdown implements Result
+func (r *WeatherResult) Markdown() string {
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# Weather Forecast for %s\n\n", r.City))
+	md.WriteString(fmt.Sprintf("**Current:** %.0f°C, %s\n\n", r.Forecast.Temperature, r.Forecast.Condition))
+	md.WriteString("## 3-Day Forecast\n")
+
+	for _, day := range r.Forecast.Forecast {
+		md.WriteString(fmt.Sprintf("- %s: %.0f°C, %s (Rain: %.0f%%)\n",
+			day.Date, day.Temperature, day.Condition, day.RainProb))
+	}
+
+	if r.Forecast.RainProb > 60 {
+		md.WriteString(fmt.Sprintf("\n⚠️ **Rain Alert:** %s\n", r.Forecast.Suggestion))
+	}
+
+	return md.String()
+}
+
+// FlightStatusResult is the current status of a flight
+type FlightStatusResult struct {
+	Status *agents.FlightStatus `json:"status"`
+}
+
+// Type implements Result
+func (r *FlightStatusResult) Type() string { return "flight_status" }
+
+// Markdown implements Result
+func (r *FlightStatusResult) Markdown() string {
+	var md strings.Builder
+	status := r.Status
+
+	md.WriteString(fmt.Sprintf("# Flight %s Status\n\n", status.FlightCode))
+	md.WriteString(fmt.Sprintf("**Status:** %s\n", strings.Title(status.Status)))
+	md.WriteString(fmt.Sprintf("**Departure:** %s\n", status.DepartureTime))
+	md.WriteString(fmt.Sprintf("**Arrival:** %s\n", status.ArrivalTime))
+
+	if status.Gate != "" {
+		md.WriteString(fmt.Sprintf("**Gate:** %s\n", status.Gate))
+	}
+
+	if status.DelayMinutes > 0 {
+		md.WriteString(fmt.Sprintf("\n⚠️ **Delayed by %d minutes**\n\n", status.DelayMinutes))
+	}
+
+	md.WriteString(fmt.Sprintf("\n%s", status.Notification))
+
+	return md.String()
+}
+
+// FlightSearchResult is a ranked list of flight options for a route
+type FlightSearchResult struct {
+	Search  agents.FlightSearchRequest `json:"search"`
+	Options []agents.FlightOption      `json:"options"`
+}
+
+// Type implements Result
+func (r *FlightSearchResult) Type() string { return "flight_options" }
+
+// Markdown implements Result
+func (r *FlightSearchResult) Markdown() string {
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# Flights %s → %s\n\n", r.Search.Origin, r.Search.Destination))
+	md.WriteString(fmt.Sprintf("Date: %s, %d passenger(s)\n\n", r.Search.Date.Format("2006-01-02"), r.Search.Passengers))
+
+	if len(r.Options) == 0 {
+		md.WriteString("No flights match this search.\n")
+		return md.String()
+	}
+
+	estimated := false
+	for _, option := range r.Options {
+		md.WriteString(fmt.Sprintf("%d. **%s** %s\n", option.Rank, option.Airline, option.FlightNumber))
+		md.WriteString(fmt.Sprintf("   - %s → %s (%s, %s)\n",
+			option.DepartTime.Format("15:04"), option.ArriveTime.Format("Jan 2 15:04"), option.Duration(), stopsLabel(option.Stops)))
+		md.WriteString(fmt.Sprintf("   - Price: %.0f %s per person (%.0f %s total)\n\n",
+			option.PricePerPassenger, option.Currency, option.TotalPrice, option.Currency))
+		estimated = estimated || option.PriceEstimated
+	}
+
+	if estimated {
+		md.WriteString("_Prices marked as estimates may differ when you book._\n")
+	}
+
+	return md.String()
+}
+
+// stopsLabel describes the number of stops
+func stopsLabel(stops int) string {
+	switch stops {
+	case 0:
+		return "direct"
+	case 1:
+		return "1 stop"
+	}
+	return fmt.Sprintf("%d stops", stops)
+}
+
+// HotelListResult is a list of hotels for a stay, filtered and sorted as requested
+type HotelListResult struct {
+	Destination string                       `json:"destination"`
+	Budget      float64                      `json:"budget_per_night"`
+	Search      agents.HotelSearchRequest    `json:"search"`
+	Hotels      []agents.HotelRecommendation `json:"hotels"`
+	Currency    string                       `json:"currency,omitempty"`
+}
+
+// Type implements Result
+func (r *HotelListResult) Type() string { return "hotel_list" }
+
+// Markdown implements Result
+func (r *HotelListResult) Markdown() string {
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# Hotels in %s\n\n", r.Destination))
+	md.WriteString(fmt.Sprintf("Budget: Up to %s per night\n", currency.Format(r.Budget, displayCode(r.Currency))))
+	if !r.Search.CheckIn.IsZero() && r.Search.Rooms > 0 {
+		md.WriteString(fmt.Sprintf("Stay: %s to %s (%d nights), %d guests, %d rooms\n",
+			r.Search.CheckIn.Format("2006-01-02"), r.Search.CheckOut.Format("2006-01-02"),
+			r.Search.Nights(), r.Search.Guests, r.Search.Rooms))
+	}
+	if filters := r.filters(); len(filters) > 0 {
+		md.WriteString(fmt.Sprintf("Filters: %s\n", strings.Join(filters, ", ")))
+	}
+	md.WriteString("\n")
+
+	if len(r.Hotels) == 0 {
+		md.WriteString("No hotels match these filters. Try removing some of them.\n")
+	}
+
+	for i, hotel := range r.Hotels {
+		if hotel.Stars > 0 {
+			md.WriteString(fmt.Sprintf("%d. **%s** (%d★ hotel)\n", i+1, hotel.Name, hotel.Stars))
+		} else {
+			md.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, hotel.Name))
+		}
+		if hotel.TotalPrice > 0 && hotel.Currency != "" {
+			md.WriteString(fmt.Sprintf("   - Price: %s/night (%s total)\n", currency.Format(hotel.PricePerNight, hotel.Currency), currency.Format(hotel.TotalPrice, hotel.Currency)))
+		} else {
+			md.WriteString(fmt.Sprintf("   - Price: %s/night\n", currency.Format(hotel.PricePerNight, displayCode(hotel.Currency))))
+		}
+		md.WriteString(fmt.Sprintf("   - Rating: %.1f★\n", hotel.Rating))
+		if hotel.RoomType != "" {
+			md.WriteString(fmt.Sprintf("   - Room: %s\n", hotel.RoomType))
+		}
+		if len(hotel.Amenities) > 0 {
+			md.WriteString(fmt.Sprintf("   - Amenities: %s\n", strings.Join(hotel.Amenities, ", ")))
+		}
+		if hotel.Refundable {
+			md.WriteString("   - Free cancellation\n")
+		} else if hotel.Source != "" {
+			md.WriteString("   - Non-refundable\n")
+		}
+		md.WriteString(fmt.Sprintf("   - Distance: %.1f km from center\n", hotel.Distance))
+		md.WriteString(fmt.Sprintf("   - Address: %s\n\n", hotel.Address))
+	}
+
+	return md.String()
+}
+
+// filters describes the active search filters
+func (r *HotelListResult) filters() []string {
+	var filters []string
+	if r.Search.MinStars > 0 {
+		filters = append(filters, fmt.Sprintf("%d+ stars", r.Search.MinStars))
+	}
+	if r.Search.MinRating > 0 {
+		filters = append(filters, fmt.Sprintf("rating %.1f+", r.Search.MinRating))
+	}
+	if len(r.Search.Amenities) > 0 {
+		filters = append(filters, strings.Join(r.Search.Amenities, ", "))
+	}
+	if r.Search.Neighborhood != "" {
+		filters = append(filters, "in "+r.Search.Neighborhood)
+	}
+	if r.Search.RefundableOnly {
+		filters = append(filters, "free cancellation")
+	}
+	return filters
+}
+
+// LocalPlacesResult is a list of nearby places plus socially popular ones
+type LocalPlacesResult struct {
+	Interest     string                       `json:"interest"`
+	Destination  string                       `json:"destination,omitempty"`
+	Latitude     float64                      `json:"latitude"`
+	Longitude    float64                      `json:"longitude"`
+	Places       []agents.PlaceRecommendation `json:"places"`
+	PopularSpots []SocialPlace                `json:"popular_spots"`
+}
+
+// Type implements Result
+func (r *LocalPlacesResult) Type() string { return "local_places" }
+
+// Markdown implements Result
+func (r *LocalPlacesResult) Markdown() string {
+	var md strings.Builder
+
+	md.WriteString(fmt.Sprintf("# Nearby %s Recommendations\n\n", strings.Title(r.Interest)))
+
+	for i, place := range r.Places {
+		md.WriteString(fmt.Sprintf("%d. **%s**\n", i+1, place.Name))
+		md.WriteString(fmt.Sprintf("   - Type: %s\n", place.Type))
+		md.WriteString(fmt.Sprintf("   - Rating: %.1f★\n", place.Rating))
+		md.WriteString(fmt.Sprintf("   - Distance: %.1f km away\n", place.DistanceKm))
+		md.WriteString(fmt.Sprintf("   - Address: %s\n\n", place.Address))
+	}
+
+	if len(r.PopularSpots) > 0 {
+		md.WriteString(fmt.Sprintf("\n## Socially Popular %s in %s\n", strings.Title(r.Interest), r.Destination))
+		md.WriteString("*Top-rated by the community*\n\n")
+		for i, place := range r.PopularSpots {
+			md.WriteString(fmt.Sprintf("%d. **%s** (%.1f★, %d reviews)\n",
+				len(r.Places)+i+1, place.Name, place.Rating, place.ReviewCount))
+			md.WriteString(fmt.Sprintf("   - Address: %s\n\n", place.Address))
+		}
+	}
+
+	return md.String()
+}
+
+// BudgetResult is a budget split into expense categories. Estimate is set when the destination is
+// known and the split is based on what the trip costs there.
+type BudgetResult struct {
+	Total    float64                `json:"total"`
+	Plan     agents.BudgetPlan      `json:"breakdown"`
+	Estimate *agents.BudgetEstimate `json:"estimate,omitempty"`
+	Currency string                 `json:"currency,omitempty"`
+}
+
+// Type implements Result
+func (r *BudgetResult) Type() string { return "budget_plan" }
+
+// Markdown implements Result
+func (r *BudgetResult) Markdown() string {
+	if r.Estimate != nil {
+		return r.estimateMarkdown()
+	}
+
+	var md strings.Builder
+	plan := r.Plan
+	code := displayCode(r.Currency)
+
+	md.WriteString(fmt.Sprintf("# Budget Breakdown for %.0f %s\n\n", r.Total, code))
+	md.WriteString(fmt.Sprintf("- **Flights:** %d %s (45%%)\n", plan.Flight, code))
+	md.WriteString(fmt.Sprintf("- **Hotels:** %d %s (25%%)\n", plan.Hotel, code))
+	md.WriteString(fmt.Sprintf("- **Food:** %d %s (15%%)\n", plan.Food, code))
+	md.WriteString(fmt.Sprintf("- **Transport:** %d %s (10%%)\n", plan.Transport, code))
+	md.WriteString(fmt.Sprintf("- **Miscellaneous:** %d %s (5%%)\n", plan.Misc, code))
+
+	total := plan.Flight + plan.Hotel + plan.Food + plan.Transport + plan.Misc
+	md.WriteString(fmt.Sprintf("\n**Total:** %d %s\n", total, code))
+
+	return md.String()
+}
+
+// budgetCategoryNames are the headings of the budget estimate categories
+var budgetCategoryNames = map[string]string{
+	agents.BudgetFlights:       "Flights",
+	agents.BudgetAccommodation: "Accommodation",
+	agents.BudgetFood:          "Food",
+	agents.BudgetTransport:     "Local transport",
+	agents.BudgetActivities:    "Activities",
+	agents.BudgetMisc:          "Miscellaneous",
+}
+
+// travelStyleNames describe travel styles in prose
+var travelStyleNames = map[string]string{
+	agents.StyleBackpacker: "backpacker",
+	agents.StyleMid:        "mid-range",
+	agents.StyleLuxury:     "luxury",
+}
+
+// estimateMarkdown renders a destination-based budget with its verdict
+func (r *BudgetResult) estimateMarkdown() string {
+	var md strings.Builder
+	estimate := r.Estimate
+	code := displayCode(r.Currency)
+	style := travelStyleNames[estimate.Style]
+
+	md.WriteString(fmt.Sprintf("# Budget for %d Days in %s\n\n", estimate.Days, estimate.Destination))
+	md.WriteString(fmt.Sprintf("*%s trip from %s, %d traveler(s)*\n\n", strings.Title(style), estimate.Origin, estimate.Travelers))
+
+	for _, allocation := range estimate.Allocations {
+		if allocation.Amount == 0 {
+			continue
+		}
+		md.WriteString(fmt.Sprintf("- **%s:** %s (%s/day, %.0f%%)\n", budgetCategoryNames[allocation.Category],
+			currency.Format(allocation.Amount, code), currency.Format(allocation.PerDay, code), allocation.Share))
+	}
+
+	md.WriteString(fmt.Sprintf("\n**Total:** %s\n", currency.Format(estimate.Total, code)))
+	md.WriteString(fmt.Sprintf("**Per day:** %s (%s per person)\n\n", currency.Format(estimate.PerDay, code), currency.Format(estimate.PerPersonPerDay, code)))
+
+	switch estimate.Verdict {
+	case agents.VerdictSuggested:
+		md.WriteString(fmt.Sprintf("This is what a typical %s trip costs. Tell me your budget to check whether it is enough.\n", style))
+	case agents.VerdictComfortable:
+		md.WriteString(fmt.Sprintf("✅ Your budget is comfortable: a typical %s trip costs %s.\n", style, currency.Format(estimate.Required, code)))
+	case agents.VerdictFeasible:
+		md.WriteString(fmt.Sprintf("✅ Your budget covers a typical %s trip (%s), with little to spare.\n", style, currency.Format(estimate.Required, code)))
+	case agents.VerdictTooLow:
+		md.WriteString(fmt.Sprintf("⚠️ **Budget too low by %s:** a typical %s trip costs %s.\n",
+			currency.Format(estimate.Shortfall, code), style, currency.Format(estimate.Required, code)))
+		if estimate.FitsStyle != "" {
+			md.WriteString(fmt.Sprintf("It is enough for a %s trip.\n", travelStyleNames[estimate.FitsStyle]))
+		} else {
+			md.WriteString("Try a shorter trip or a closer destination.\n")
+		}
+	}
+
+	return md.String()
+}
+
+// writeItinerary renders itinerary days with their activities and daily budget in the given currency
+func writeItinerary(md *strings.Builder, itinerary []agents.ItineraryDay, code string) {
+	for _, day := range itinerary {
+		md.WriteString(fmt.Sprintf("\n**Day %d:**\n", day.Day))
+		for _, activity := range day.Activities {
+			md.WriteString(fmt.Sprintf("- %s\n", activity))
+		}
+		md.WriteString(fmt.Sprintf("*Daily Budget: %s*\n", currency.Format(day.Budget, code)))
+	}
+}
@@ -0,0 +1,104 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrator_Process_ReturnsTypedResults(t *testing.T) {
+	orch := New("", "", "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		message    string
+		resultType string
+	}{
+		{"Trip plan", "Plan a trip to Tokyo for 4 days with 40000 baht", "trip_plan"},
+		{"Weather", "What's the weather in Bangkok?", "weather_forecast"},
+		{"Flight status", "Is flight JL708 on time?", "flight_status"},
+		{"Hotels", "Find hotels in Tokyo", "hotel_list"},
+		{"Local places", "Find good ramen restaurants nearby", "local_places"},
+		{"Budget", "What can I do with 50000 baht budget?", "budget_plan"},
+		{"General chat", "Hello!", "message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := orch.Process(ctx, "", tt.message)
+			require.NoError(t, err)
+			require.NotNil(t, response.Result)
+			assert.Equal(t, tt.resultType, response.Result.Type())
+			assert.NotEmpty(t, response.Markdown())
+
+			// Every result must serialize cleanly for the JSON response mode
+			_, err = json.Marshal(response)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTripPlanResult_MarkdownMatchesData(t *testing.T) {
+	orch := New("", "", "", "")
+
+	response, err := orch.Process(context.Background(), "", "Plan a trip to Tokyo for 4 days with 40000 baht")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*TripPlanResult)
+	require.True(t, ok)
+	assert.Equal(t, "Tokyo", result.Destination)
+	assert.Equal(t, 4, result.Duration)
+	assert.Equal(t, 40000.0, result.Budget)
+	require.NotNil(t, result.Plan)
+	assert.Len(t, result.Plan.Itinerary, 4)
+
+	markdown := response.Markdown()
+	assert.Contains(t, markdown, "4-Day Trip to Tokyo")
+	assert.Contains(t, markdown, "40000 THB")
+}
+
+func TestBudgetResult_EstimatesTheDestination(t *testing.T) {
+	orch := New("", "", "", "")
+
+	response, err := orch.Process(context.Background(), "", "Is 30000 baht enough for 5 days in Tokyo?")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*BudgetResult)
+	require.True(t, ok)
+	require.NotNil(t, result.Estimate)
+	assert.Equal(t, "Tokyo", result.Estimate.Destination)
+	assert.Equal(t, 30000.0, result.Total)
+	assert.Equal(t, agents.VerdictTooLow, result.Estimate.Verdict)
+	assert.Equal(t, 30000, result.Plan.Flight+result.Plan.Hotel+result.Plan.Food+result.Plan.Transport+result.Plan.Misc,
+		"The breakdown still adds up to the budget")
+
+	markdown := response.Markdown()
+	assert.Contains(t, markdown, "# Budget for 5 Days in Tokyo")
+	assert.Contains(t, markdown, "**Budget too low by 19280 THB:** a typical mid-range trip costs 49280 THB.")
+	assert.Contains(t, markdown, "It is enough for a backpacker trip.")
+	assert.Contains(t, markdown, "**Per day:** 6000 THB")
+}
+
+func TestBudgetResult_SuggestsBudgetForStyle(t *testing.T) {
+	orch := New("", "", "", "")
+
+	response, err := orch.Process(context.Background(), "", "How much is a luxury trip to Paris for 4 days for 2 people?")
+	require.NoError(t, err)
+
+	result, ok := response.Result.(*BudgetResult)
+	require.True(t, ok)
+	require.NotNil(t, result.Estimate)
+	assert.Equal(t, agents.VerdictSuggested, result.Estimate.Verdict)
+	assert.Equal(t, agents.StyleLuxury, result.Estimate.Style)
+	assert.Equal(t, 2, result.Estimate.Travelers)
+	assert.Equal(t, result.Estimate.Required, result.Total)
+	assert.Contains(t, response.Markdown(), "Tell me your budget")
+}
@@ -0,0 +1,212 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+)
+
+// DefaultSessionTTL is how long an idle conversation is kept
+const DefaultSessionTTL = 24 * time.Hour
+
+// maxSessionHistory caps the number of turns kept per conversation
+const maxSessionHistory = 20
+
+// ErrSessionNotOwned is returned for a session another signed-in user started
+var ErrSessionNotOwned = errors.New("session belongs to another user")
+
+// ConversationState holds everything the orchestrator remembers between turns
+type ConversationState struct {
+	SessionID string `json:"session_id"`
+	// UserID is the signed-in user the conversation belongs to; anonymous conversations have none
+	UserID     string                 `json:"user_id,omitempty"`
+	LastIntent string                 `json:"last_intent,omitempty"`
+	Entities   map[string]interface{} `json:"entities"`
+	History    []agents.IntentResult  `json:"history"`
+	LastPlan   *agents.TripPlan       `json:"last_plan,omitempty"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// NewConversationState creates an empty state for a session
+func NewConversationState(sessionID string) *ConversationState {
+	return &ConversationState{
+		SessionID: sessionID,
+		Entities:  make(map[string]interface{}),
+		History:   make([]agents.IntentResult, 0),
+	}
+}
+
+// sessionUserKey is the context key for the signed-in user a message is from
+type sessionUserKey struct{}
+
+// WithUser sets the signed-in user a message is from. Sessions they start belong to them and
+// cannot be continued or read by anyone else.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, sessionUserKey{}, userID)
+}
+
+// userFromContext returns the user set with WithUser, or "" for anonymous messages
+func userFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(sessionUserKey{}).(string)
+	return userID
+}
+
+// PreviousIntent returns the last intent with all entities collected so far
+func (s *ConversationState) PreviousIntent() *agents.IntentResult {
+	if s == nil || s.LastIntent == "" {
+		return nil
+	}
+	return &agents.IntentResult{
+		Intent:   s.LastIntent,
+		Entities: s.Entities,
+	}
+}
+
+// Record stores a resolved intent and carries its entities forward
+func (s *ConversationState) Record(intent *agents.IntentResult) {
+	if s.Entities == nil {
+		s.Entities = make(map[string]interface{})
+	}
+	for key, value := range intent.Entities {
+		if !isEmptyEntity(value) {
+			s.Entities[key] = value
+		}
+	}
+
+	s.LastIntent = intent.Intent
+	s.History = append(s.History, *intent)
+	if len(s.History) > maxSessionHistory {
+		s.History = s.History[len(s.History)-maxSessionHistory:]
+	}
+	s.UpdatedAt = time.Now()
+}
+
+// SessionStore persists conversation state between messages
+type SessionStore interface {
+	// Load returns the state for a session, or nil if the session is unknown
+	Load(ctx context.Context, sessionID string) (*ConversationState, error)
+	// Save stores the state and refreshes its expiry
+	Save(ctx context.Context, state *ConversationState) error
+}
+
+// RedisSessionStore keeps conversation state in Redis with a TTL
+type RedisSessionStore struct {
+	cache *database.RedisCache
+	ttl   time.Duration
+}
+
+// NewRedisSessionStore creates a Redis-backed session store
+func NewRedisSessionStore(cache *database.RedisCache, ttl time.Duration) *RedisSessionStore {
+	if ttl <= 0 {
+		ttl = DefaultSessionTTL
+	}
+	return &RedisSessionStore{
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+// Load retrieves a session from Redis
+func (s *RedisSessionStore) Load(ctx context.Context, sessionID string) (*ConversationState, error) {
+	data, err := s.cache.Get(sessionKey(sessionID))
+	if errors.Is(err, database.ErrCacheMiss) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	var state ConversationState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &state, nil
+}
+
+// Save writes a session to Redis
+func (s *RedisSessionStore) Save(ctx context.Context, state *ConversationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	return s.cache.Set(sessionKey(state.SessionID), data, s.ttl)
+}
+
+// MemorySessionStore keeps conversation state in process memory (used in tests and local runs)
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string][]byte
+}
+
+// NewMemorySessionStore creates an in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{
+		sessions: make(map[string][]byte),
+	}
+}
+
+// Load retrieves a copy of the stored session
+func (s *MemorySessionStore) Load(ctx context.Context, sessionID string) (*ConversationState, error) {
+	s.mu.RLock()
+	data, ok := s.sessions[sessionID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, nil
+	}
+
+	// Round-trip through JSON so callers see the same types as with Redis
+	var state ConversationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse session: %w", err)
+	}
+	return &state, nil
+}
+
+// Save stores a copy of the session
+func (s *MemorySessionStore) Save(ctx context.Context, state *ConversationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	s.mu.Lock()
+	s.sessions[state.SessionID] = data
+	s.mu.Unlock()
+	return nil
+}
+
+// sessionKey builds the cache key for a session
+func sessionKey(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// isEmptyEntity reports whether an extracted entity carries no information
+func isEmptyEntity(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case float64:
+		return v == 0
+	case int:
+		return v == 0
+	case []interface{}:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, inner := range v {
+			if !isEmptyEntity(inner) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
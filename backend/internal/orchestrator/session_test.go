@@ -0,0 +1,213 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_FollowUpKeepsDestinationAndBudget(t *testing.T) {
+	orch := New("", "", "", "")
+	store := NewMemorySessionStore()
+	orch.SetSessionStore(store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Turn 1: full trip request
+	response, err := orch.ProcessMessage(ctx, "journey-1", "อยากไปเที่ยวแคนาดา 7 วัน งบ 100,000 บาท")
+	require.NoError(t, err)
+	assert.Contains(t, response, "7-Day Trip to Canada")
+	assert.Contains(t, response, "100000 THB")
+
+	// Turn 2: follow-up that only changes the duration
+	response, err = orch.ProcessMessage(ctx, "journey-1", "make it 5 days instead")
+	require.NoError(t, err)
+	assert.Contains(t, response, "5-Day Trip to Canada", "Destination should carry over from the first turn")
+	assert.Contains(t, response, "100000 THB", "Budget should carry over from the first turn")
+
+	state, err := store.Load(ctx, "journey-1")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, "plan_trip", state.LastIntent)
+	assert.Len(t, state.History, 2)
+	require.NotNil(t, state.LastPlan)
+	assert.Equal(t, 5, state.LastPlan.Duration)
+	assert.Len(t, state.LastPlan.Itinerary, 5)
+}
+
+func TestSession_EntitiesCarryAcrossIntents(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.SetSessionStore(NewMemorySessionStore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := orch.ProcessMessage(ctx, "journey-2", "Plan a trip to Tokyo for 4 days")
+	require.NoError(t, err)
+
+	// The hotel search does not mention a city, so it should use Tokyo from the first turn
+	response, err := orch.ProcessMessage(ctx, "journey-2", "Find me a hotel")
+	require.NoError(t, err)
+	assert.Contains(t, response, "Hotels in Tokyo")
+}
+
+func TestSession_SessionsAreIsolated(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.SetSessionStore(NewMemorySessionStore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := orch.ProcessMessage(ctx, "session-a", "Plan a trip to Tokyo for 4 days")
+	require.NoError(t, err)
+
+	response, err := orch.ProcessMessage(ctx, "session-b", "Find me a hotel")
+	require.NoError(t, err)
+	assert.Contains(t, response, "Hotels in Bangkok", "Another session should not see Tokyo")
+}
+
+func TestSession_BelongsToTheUserWhoStartedIt(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.SetSessionStore(NewMemorySessionStore())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	owner := WithUser(ctx, "user-1")
+	other := WithUser(ctx, "user-2")
+
+	_, err := orch.Process(owner, "private", "Plan a trip to Tokyo for 4 days")
+	require.NoError(t, err)
+	require.NoError(t, orch.CheckSession(owner, "private"))
+	assert.NotNil(t, orch.SessionPlan(owner, "private"))
+
+	for name, ctx := range map[string]context.Context{"Another user": other, "Anonymous": ctx} {
+		_, err = orch.Process(ctx, "private", "make it 5 days instead")
+		assert.ErrorIs(t, err, ErrSessionNotOwned, name)
+		assert.ErrorIs(t, orch.CheckSession(ctx, "private"), ErrSessionNotOwned, name)
+		assert.Nil(t, orch.SessionPlan(ctx, "private"), name)
+	}
+	assert.NoError(t, orch.CheckSession(other, "unknown"), "New sessions can be started by anyone")
+
+	// An anonymous conversation belongs to the first user who signs in to continue it
+	_, err = orch.Process(ctx, "anonymous", "Plan a trip to Osaka for 3 days")
+	require.NoError(t, err)
+	_, err = orch.Process(owner, "anonymous", "make it 5 days instead")
+	require.NoError(t, err)
+	_, err = orch.Process(ctx, "anonymous", "make it 2 days instead")
+	assert.ErrorIs(t, err, ErrSessionNotOwned)
+}
+
+func TestSession_NoSessionIDIsStateless(t *testing.T) {
+	orch := New("", "", "", "")
+	store := NewMemorySessionStore()
+	orch.SetSessionStore(store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := orch.ProcessMessage(ctx, "", "Plan a trip to Tokyo for 4 days")
+	require.NoError(t, err)
+
+	response, err := orch.ProcessMessage(ctx, "", "make it 5 days instead")
+	require.NoError(t, err)
+	assert.Contains(t, response, "assistant", "Without a session the follow-up is plain chat")
+	assert.Empty(t, store.sessions)
+}
+
+func TestResolveFollowUp(t *testing.T) {
+	state := NewConversationState("s1")
+	state.Record(&agents.IntentResult{
+		Intent: "plan_trip",
+		Entities: map[string]interface{}{
+			"destination": "Canada",
+			"budget":      100000.0,
+			"duration":    7.0,
+		},
+	})
+
+	t.Run("Entity-only message follows previous intent", func(t *testing.T) {
+		resolved := resolveFollowUp(&agents.IntentResult{
+			Intent:   "general_chat",
+			Entities: map[string]interface{}{"duration": 5.0},
+		}, state)
+
+		assert.Equal(t, "plan_trip", resolved.Intent)
+		assert.Equal(t, "Canada", resolved.Entities["destination"])
+		assert.Equal(t, 100000.0, resolved.Entities["budget"])
+		assert.Equal(t, 5.0, resolved.Entities["duration"])
+	})
+
+	t.Run("Greeting stays general chat", func(t *testing.T) {
+		resolved := resolveFollowUp(&agents.IntentResult{
+			Intent:   "general_chat",
+			Entities: map[string]interface{}{},
+		}, state)
+
+		assert.Equal(t, "general_chat", resolved.Intent)
+	})
+
+	t.Run("Empty LLM entities do not overwrite known values", func(t *testing.T) {
+		resolved := resolveFollowUp(&agents.IntentResult{
+			Intent: "weather_check",
+			Entities: map[string]interface{}{
+				"destination": "",
+				"budget":      0.0,
+				"location":    map[string]interface{}{"lat": 0.0, "lng": 0.0},
+			},
+		}, state)
+
+		assert.Equal(t, "weather_check", resolved.Intent)
+		assert.Equal(t, "Canada", resolved.Entities["destination"])
+		assert.Equal(t, 100000.0, resolved.Entities["budget"])
+	})
+}
+
+func TestConversationState_HistoryIsCapped(t *testing.T) {
+	state := NewConversationState("s1")
+	for i := 0; i < maxSessionHistory+5; i++ {
+		state.Record(&agents.IntentResult{Intent: "general_chat"})
+	}
+	assert.Len(t, state.History, maxSessionHistory)
+}
+
+func TestSession_PlanUpdateAppliesChangesToCurrentPlan(t *testing.T) {
+	orch := New("", "", "", "")
+	store := NewMemorySessionStore()
+	orch.SetSessionStore(store)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := orch.ProcessMessage(ctx, "journey-3", "Plan a trip to Tokyo for 4 days with 40000 baht")
+	require.NoError(t, err)
+
+	response, err := orch.ProcessMessage(ctx, "journey-3", "Please shorten the trip to 3 days")
+	require.NoError(t, err)
+	assert.Contains(t, response, "Updated 3-Day Trip to Tokyo")
+	assert.Contains(t, response, "@@ Day 4 (removed) @@")
+	assert.Contains(t, response, "Daily budget: 10000 THB -> 13333 THB")
+
+	response, err = orch.ProcessMessage(ctx, "journey-3", "Swap the landmarks for a sumo match on day 2")
+	require.NoError(t, err)
+	assert.Contains(t, response, "@@ Day 2 @@\n- Visit popular landmarks\n+ sumo match")
+
+	state, err := store.Load(ctx, "journey-3")
+	require.NoError(t, err)
+	require.NotNil(t, state.LastPlan)
+	assert.Equal(t, 3, state.LastPlan.Duration)
+	assert.Contains(t, state.LastPlan.Itinerary[1].Activities, "sumo match")
+}
+
+func TestPlanUpdate_WithoutPlan(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.SetSessionStore(NewMemorySessionStore())
+
+	response, err := orch.ProcessMessage(context.Background(), "journey-4", "Change my plan please")
+	require.NoError(t, err)
+	assert.Contains(t, response, "don't have a plan to update yet")
+}
@@ -1,30 +1,32 @@
 package orchestrator
 
 import (
+	"context"
+
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
 )
 
 // SocialServiceAdapter adapts the services.SocialService to the orchestrator interface
 type SocialServiceAdapter struct {
 	service interface {
-		GetTopRatedPlaces(keyword, location string, limit int) ([]models.SocialPlace, error)
+		GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]models.SocialPlace, error)
 	}
 }
 
 // NewSocialServiceAdapter creates a new adapter
 func NewSocialServiceAdapter(service interface {
-	GetTopRatedPlaces(keyword, location string, limit int) ([]models.SocialPlace, error)
+	GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]models.SocialPlace, error)
 }) *SocialServiceAdapter {
 	return &SocialServiceAdapter{service: service}
 }
 
 // GetTopRatedPlaces adapts the service call and converts models
-func (a *SocialServiceAdapter) GetTopRatedPlaces(keyword, location string, limit int) ([]SocialPlace, error) {
+func (a *SocialServiceAdapter) GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]SocialPlace, error) {
 	if a.service == nil {
 		return nil, nil
 	}
 	
-	places, err := a.service.GetTopRatedPlaces(keyword, location, limit)
+	places, err := a.service.GetTopRatedPlaces(ctx, keyword, location, limit)
 	if err != nil {
 		return nil, err
 	}
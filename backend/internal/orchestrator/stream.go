@@ -0,0 +1,43 @@
+package orchestrator
+
+import "context"
+
+// Stream event types emitted while a message is processed
+const (
+	EventIntent       = "intent"
+	EventItinerary    = "itinerary"
+	EventWeather      = "weather"
+	EventHotels       = "hotels"
+	EventPopularSpots = "popular_spots"
+	EventFlights      = "flights"
+	EventSummaryToken = "summary_token"
+	EventResult       = "result"
+)
+
+// StreamEvent reports progress while a message is processed
+type StreamEvent struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// EventFunc receives stream events; it is called from the processing goroutine
+type EventFunc func(event StreamEvent)
+
+// send emits an event when a receiver is set
+func (f EventFunc) send(eventType string, data interface{}) {
+	if f != nil {
+		f(StreamEvent{Type: eventType, Data: data})
+	}
+}
+
+// ProcessStream handles a user message like Process, emitting an event as each stage completes.
+// Trip plans also stream the summary token by token. The final event is always EventResult.
+func (o *Orchestrator) ProcessStream(ctx context.Context, sessionID, userInput string, emit EventFunc) (*Response, error) {
+	response, err := o.process(ctx, sessionID, userInput, emit)
+	if err != nil {
+		return nil, err
+	}
+
+	emit.send(EventResult, response)
+	return response, nil
+}
@@ -0,0 +1,54 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrchestrator_ProcessStream_PlanTripStages(t *testing.T) {
+	orch := New("", "", "", "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var stages []string
+	var summary strings.Builder
+	response, err := orch.ProcessStream(ctx, "", "Plan a trip to Tokyo for 4 days with 40000 baht", func(event StreamEvent) {
+		if event.Type == EventSummaryToken {
+			summary.WriteString(event.Data.(string))
+			if len(stages) > 0 && stages[len(stages)-1] == EventSummaryToken {
+				return
+			}
+		}
+		stages = append(stages, event.Type)
+	})
+	require.NoError(t, err)
+
+	// Sub-agents run concurrently, so their events may arrive in any order
+	require.Len(t, stages, 8)
+	assert.Equal(t, EventIntent, stages[0])
+	assert.ElementsMatch(t, []string{EventItinerary, EventWeather, EventHotels, EventPopularSpots, EventFlights}, stages[1:6])
+	assert.Equal(t, []string{EventSummaryToken, EventResult}, stages[6:])
+
+	result, ok := response.Result.(*TripPlanResult)
+	require.True(t, ok)
+	assert.Equal(t, result.Plan.Summary, summary.String(), "Streamed tokens should add up to the plan summary")
+}
+
+func TestOrchestrator_ProcessStream_OtherIntents(t *testing.T) {
+	orch := New("", "", "", "")
+
+	var stages []string
+	response, err := orch.ProcessStream(context.Background(), "", "What's the weather in Bangkok?", func(event StreamEvent) {
+		stages = append(stages, event.Type)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{EventIntent, EventResult}, stages)
+	assert.Equal(t, "weather_forecast", response.Result.Type())
+}
@@ -0,0 +1,56 @@
+package orchestrator
+
+import (
+	"context"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
+)
+
+// savedTripKey is the context key for the saved trip a message is about
+type savedTripKey struct{}
+
+// WithSavedTrip makes a saved trip the context of a message: plan updates apply to its itinerary,
+// and weather and budget questions default to its destination, length and budget
+func WithSavedTrip(ctx context.Context, plan *agents.TripPlan) context.Context {
+	return context.WithValue(ctx, savedTripKey{}, plan)
+}
+
+// SessionPlan returns the plan most recently created or updated in a session, or nil. Sessions of
+// other users than the one set with WithUser have no plan.
+func (o *Orchestrator) SessionPlan(ctx context.Context, sessionID string) *agents.TripPlan {
+	state, err := o.loadSession(ctx, sessionID)
+	if err != nil || state == nil {
+		return nil
+	}
+	return state.LastPlan
+}
+
+// applySavedTrip loads the saved trip attached to ctx into the conversation, starting one for
+// stateless requests
+func applySavedTrip(ctx context.Context, state *ConversationState, sessionID string) *ConversationState {
+	plan, _ := ctx.Value(savedTripKey{}).(*agents.TripPlan)
+	if plan == nil {
+		return state
+	}
+	if state == nil {
+		state = NewConversationState(sessionID)
+	}
+
+	state.LastPlan = plan
+	if state.LastIntent == "" {
+		state.LastIntent = "plan_trip"
+	}
+	if state.Entities == nil {
+		state.Entities = make(map[string]interface{})
+	}
+	state.Entities["destination"] = plan.Destination
+	if plan.Duration > 0 {
+		state.Entities["duration"] = float64(plan.Duration)
+	}
+	if plan.TotalBudget > 0 {
+		state.Entities["budget"] = plan.TotalBudget
+		state.Entities["budget_currency"] = currency.Base
+	}
+	return state
+}
@@ -0,0 +1,69 @@
+package orchestrator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func savedKyotoTrip() *agents.TripPlan {
+	return &agents.TripPlan{
+		Destination: "Kyoto",
+		Duration:    3,
+		TotalBudget: 45000,
+		Itinerary: []agents.ItineraryDay{
+			{Day: 1, Activities: []string{"Fushimi Inari"}, Budget: 15000},
+			{Day: 2, Activities: []string{"Arashiyama"}, Budget: 15000},
+			{Day: 3, Activities: []string{"Kiyomizu-dera"}, Budget: 15000},
+		},
+	}
+}
+
+func TestSavedTrip_IsTheContextOfQuestions(t *testing.T) {
+	orch := New("", "", "", "")
+	ctx := WithSavedTrip(context.Background(), savedKyotoTrip())
+
+	response, err := orch.Process(ctx, "", "What's the weather like?")
+	require.NoError(t, err)
+	weather, ok := response.Result.(*WeatherResult)
+	require.True(t, ok, "got %T", response.Result)
+	assert.Equal(t, "Kyoto", weather.City)
+
+	response, err = orch.Process(ctx, "", "Is my budget enough?")
+	require.NoError(t, err)
+	budget, ok := response.Result.(*BudgetResult)
+	require.True(t, ok, "got %T", response.Result)
+	require.NotNil(t, budget.Estimate)
+	assert.Equal(t, "Kyoto", budget.Estimate.Destination)
+	assert.Equal(t, 45000.0, budget.Total)
+	assert.Equal(t, 3, budget.Estimate.Days)
+}
+
+func TestSavedTrip_UpdatesTheSavedItinerary(t *testing.T) {
+	orch := New("", "", "", "")
+	orch.SetSessionStore(NewMemorySessionStore())
+	ctx := WithSavedTrip(context.Background(), savedKyotoTrip())
+
+	response, err := orch.Process(ctx, "kyoto-session", "Change day 2 to a cooking class")
+	require.NoError(t, err)
+	update, ok := response.Result.(*PlanUpdateResult)
+	require.True(t, ok, "got %T", response.Result)
+	assert.Equal(t, "Kyoto", update.Plan.Destination)
+	assert.Equal(t, 45000.0, update.PreviousBudget)
+
+	// The updated plan is what the session would save back to the trip
+	plan := orch.SessionPlan(context.Background(), "kyoto-session")
+	require.NotNil(t, plan)
+	assert.Equal(t, update.Plan, plan)
+}
+
+func TestSavedTrip_WithoutTripNothingChanges(t *testing.T) {
+	assert.Nil(t, applySavedTrip(context.Background(), nil, ""))
+
+	state := NewConversationState("s")
+	assert.Same(t, state, applySavedTrip(context.Background(), state, "s"))
+	assert.Nil(t, state.LastPlan)
+}
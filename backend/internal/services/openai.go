@@ -7,6 +7,7 @@ import (
 
 	"github.com/sashabaranov/go-openai"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/currency"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
 )
 
@@ -79,7 +80,11 @@ func (s *OpenAIService) buildTravelPrompt(req *models.TravelSearchRequest) strin
 	}
 
 	if req.Budget > 0 {
-		prompt += fmt.Sprintf(" My budget is approximately $%.2f.", req.Budget)
+		code, ok := currency.Normalize(req.Currency)
+		if !ok {
+			code = currency.Base
+		}
+		prompt += fmt.Sprintf(" My budget is approximately %s.", currency.Format(req.Budget, code))
 	}
 
 	if len(req.Preferences) > 0 {
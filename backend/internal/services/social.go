@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,17 +9,24 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/cache"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/config"
 	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
 )
 
+// socialCachePolicy keeps places fresh for an hour, serves them for up to a day more while
+// refreshing, and backs off the Places API for two minutes after a failure
+var socialCachePolicy = cache.Policy{Fresh: time.Hour, Stale: 24 * time.Hour, Negative: 2 * time.Minute}
+
 // SocialService handles Google Places API interactions
 type SocialService struct {
 	apiKey  string
 	baseURL string
 	client  *http.Client
+	loader  *cache.Loader
 }
 
 // googlePlacesTextSearchResponse represents Google Places Text Search API response
@@ -63,8 +71,19 @@ func NewSocialService(cfg *config.Config) *SocialService {
 	}
 }
 
-// GetTopRatedPlaces fetches top-rated places from Google Places API
-func (s *SocialService) GetTopRatedPlaces(keyword, location string, limit int) ([]models.SocialPlace, error) {
+// SetCache makes the service share Places results between requests for the same search
+func (s *SocialService) SetCache(c cache.Cache) {
+	s.loader = cache.NewLoader("social", c, socialCachePolicy)
+}
+
+// CacheStats reports how place lookups were served
+func (s *SocialService) CacheStats() cache.Stats {
+	return s.loader.Stats()
+}
+
+// GetTopRatedPlaces fetches top-rated places from Google Places API. The request is abandoned
+// when ctx is done, unless other callers are still waiting for the same search.
+func (s *SocialService) GetTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]models.SocialPlace, error) {
 	if s == nil {
 		return nil, fmt.Errorf("social service not initialized")
 	}
@@ -73,6 +92,20 @@ func (s *SocialService) GetTopRatedPlaces(keyword, location string, limit int) (
 		limit = 10
 	}
 
+	var places []models.SocialPlace
+	key := fmt.Sprintf("social:places:%s:%s:%d", strings.ToLower(keyword), strings.ToLower(location), limit)
+	err := s.loader.Load(ctx, key, &places, func(ctx context.Context) (interface{}, error) {
+		return s.fetchTopRatedPlaces(ctx, keyword, location, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return places, nil
+}
+
+// fetchTopRatedPlaces queries the Places text search API
+func (s *SocialService) fetchTopRatedPlaces(ctx context.Context, keyword, location string, limit int) ([]models.SocialPlace, error) {
+
 	// Build the query
 	query := keyword + " in " + location
 
@@ -84,7 +117,11 @@ func (s *SocialService) GetTopRatedPlaces(keyword, location string, limit int) (
 	apiURL := fmt.Sprintf("%s/textsearch/json?%s", s.baseURL, params.Encode())
 
 	// Make the HTTP request
-	resp, err := s.client.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build places request: %w", err)
+	}
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch places data: %w", err)
 	}
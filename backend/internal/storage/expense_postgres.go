@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+)
+
+// PostgresExpenseRepository keeps trip expenses in the trip_expenses table, budgets in trip_budgets and
+// group members in trip_members
+type PostgresExpenseRepository struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresExpenseRepository creates a Postgres-backed expense repository
+func NewPostgresExpenseRepository(db *database.PostgresDB) *PostgresExpenseRepository {
+	return &PostgresExpenseRepository{db: db}
+}
+
+const expenseColumns = `id, trip_id, category, amount, currency, amount_thb, expense_date, COALESCE(day, 0),
+	COALESCE(description, ''), COALESCE(paid_by, 0), split, created_at, updated_at`
+
+// CreateExpense implements ExpenseRepository
+func (s *PostgresExpenseRepository) CreateExpense(ctx context.Context, expense *agents.Expense) error {
+	split, err := marshalSplit(expense.Split)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO trip_expenses (trip_id, category, amount, currency, amount_thb, expense_date, day, description,
+			paid_by, split)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0), NULLIF($8, ''), NULLIF($9, 0), $10)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = s.db.DB.QueryRowContext(ctx, query,
+		expense.TripID,
+		expense.Category,
+		expense.Amount,
+		expense.Currency,
+		expense.AmountTHB,
+		expense.Date.Format("2006-01-02"),
+		expense.Day,
+		expense.Description,
+		expense.PaidBy,
+		split,
+	).Scan(&expense.ID, &expense.CreatedAt, &expense.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create expense: %w", err)
+	}
+	return nil
+}
+
+// GetExpense implements ExpenseRepository
+func (s *PostgresExpenseRepository) GetExpense(ctx context.Context, tripID, id int64) (*agents.Expense, error) {
+	row := s.db.DB.QueryRowContext(ctx, "SELECT "+expenseColumns+" FROM trip_expenses WHERE trip_id = $1 AND id = $2", tripID, id)
+	expense, err := scanExpense(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &expense, nil
+}
+
+// ListExpenses implements ExpenseRepository
+func (s *PostgresExpenseRepository) ListExpenses(ctx context.Context, tripID int64) ([]agents.Expense, error) {
+	rows, err := s.db.DB.QueryContext(ctx,
+		"SELECT "+expenseColumns+" FROM trip_expenses WHERE trip_id = $1 ORDER BY expense_date, id", tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expenses: %w", err)
+	}
+	defer rows.Close()
+
+	expenses := []agents.Expense{}
+	for rows.Next() {
+		expense, err := scanExpense(rows)
+		if err != nil {
+			return nil, err
+		}
+		expenses = append(expenses, expense)
+	}
+	return expenses, rows.Err()
+}
+
+// UpdateExpense implements ExpenseRepository
+func (s *PostgresExpenseRepository) UpdateExpense(ctx context.Context, expense *agents.Expense) error {
+	split, err := marshalSplit(expense.Split)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE trip_expenses
+		SET category = $3, amount = $4, currency = $5, amount_thb = $6, expense_date = $7,
+			day = NULLIF($8, 0), description = NULLIF($9, ''), paid_by = NULLIF($10, 0), split = $11,
+			updated_at = CURRENT_TIMESTAMP
+		WHERE trip_id = $1 AND id = $2
+		RETURNING created_at, updated_at
+	`
+
+	err = s.db.DB.QueryRowContext(ctx, query,
+		expense.TripID,
+		expense.ID,
+		expense.Category,
+		expense.Amount,
+		expense.Currency,
+		expense.AmountTHB,
+		expense.Date.Format("2006-01-02"),
+		expense.Day,
+		expense.Description,
+		expense.PaidBy,
+		split,
+	).Scan(&expense.CreatedAt, &expense.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("expense %d not found", expense.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update expense: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpense implements ExpenseRepository
+func (s *PostgresExpenseRepository) DeleteExpense(ctx context.Context, tripID, id int64) error {
+	if _, err := s.db.DB.ExecContext(ctx, "DELETE FROM trip_expenses WHERE trip_id = $1 AND id = $2", tripID, id); err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	return nil
+}
+
+// SetBudget implements ExpenseRepository
+func (s *PostgresExpenseRepository) SetBudget(ctx context.Context, budget *agents.TripBudget) error {
+	breakdown, err := json.Marshal(budget.Plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal budget breakdown: %w", err)
+	}
+
+	query := `
+		INSERT INTO trip_budgets (trip_id, total, breakdown, days)
+		VALUES ($1, $2, $3, NULLIF($4, 0))
+		ON CONFLICT (trip_id) DO UPDATE
+		SET total = EXCLUDED.total, breakdown = EXCLUDED.breakdown, days = EXCLUDED.days, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at
+	`
+
+	err = s.db.DB.QueryRowContext(ctx, query, budget.TripID, budget.Total, breakdown, budget.Days).Scan(&budget.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save trip budget: %w", err)
+	}
+	return nil
+}
+
+// GetBudget implements ExpenseRepository
+func (s *PostgresExpenseRepository) GetBudget(ctx context.Context, tripID int64) (*agents.TripBudget, error) {
+	var budget agents.TripBudget
+	var breakdown []byte
+	err := s.db.DB.QueryRowContext(ctx,
+		"SELECT trip_id, total, breakdown, COALESCE(days, 0), updated_at FROM trip_budgets WHERE trip_id = $1", tripID,
+	).Scan(&budget.TripID, &budget.Total, &breakdown, &budget.Days, &budget.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trip budget: %w", err)
+	}
+
+	if err := json.Unmarshal(breakdown, &budget.Plan); err != nil {
+		return nil, fmt.Errorf("failed to parse budget breakdown: %w", err)
+	}
+	return &budget, nil
+}
+
+// AddMember implements ExpenseRepository
+func (s *PostgresExpenseRepository) AddMember(ctx context.Context, member *agents.TripMember) error {
+	query := `
+		INSERT INTO trip_members (trip_id, name, email)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id, created_at
+	`
+
+	err := s.db.DB.QueryRowContext(ctx, query, member.TripID, member.Name, member.Email).Scan(&member.ID, &member.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to add trip member: %w", err)
+	}
+	return nil
+}
+
+// ListMembers implements ExpenseRepository
+func (s *PostgresExpenseRepository) ListMembers(ctx context.Context, tripID int64) ([]agents.TripMember, error) {
+	rows, err := s.db.DB.QueryContext(ctx,
+		"SELECT id, trip_id, name, COALESCE(email, ''), created_at FROM trip_members WHERE trip_id = $1 ORDER BY id", tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trip members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []agents.TripMember{}
+	for rows.Next() {
+		var member agents.TripMember
+		if err := rows.Scan(&member.ID, &member.TripID, &member.Name, &member.Email, &member.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan trip member: %w", err)
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// DeleteMember implements ExpenseRepository
+func (s *PostgresExpenseRepository) DeleteMember(ctx context.Context, tripID, id int64) error {
+	if _, err := s.db.DB.ExecContext(ctx, "DELETE FROM trip_members WHERE trip_id = $1 AND id = $2", tripID, id); err != nil {
+		return fmt.Errorf("failed to delete trip member: %w", err)
+	}
+	return nil
+}
+
+// marshalSplit encodes an expense split for the split column; unsplit expenses store NULL
+func marshalSplit(split *agents.ExpenseSplit) ([]byte, error) {
+	if split == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(split)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal expense split: %w", err)
+	}
+	return data, nil
+}
+
+// scanExpense reads a row selected with expenseColumns
+func scanExpense(row rowScanner) (agents.Expense, error) {
+	var expense agents.Expense
+	var split []byte
+	err := row.Scan(
+		&expense.ID,
+		&expense.TripID,
+		&expense.Category,
+		&expense.Amount,
+		&expense.Currency,
+		&expense.AmountTHB,
+		&expense.Date,
+		&expense.Day,
+		&expense.Description,
+		&expense.PaidBy,
+		&split,
+		&expense.CreatedAt,
+		&expense.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return expense, err
+	}
+	if err != nil {
+		return expense, fmt.Errorf("failed to scan expense: %w", err)
+	}
+
+	if split != nil {
+		expense.Split = &agents.ExpenseSplit{}
+		if err := json.Unmarshal(split, expense.Split); err != nil {
+			return expense, fmt.Errorf("failed to parse expense split: %w", err)
+		}
+	}
+	return expense, nil
+}
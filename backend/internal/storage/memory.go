@@ -0,0 +1,332 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// MemorySearchRepository keeps searches in process memory
+type MemorySearchRepository struct {
+	mu       sync.RWMutex
+	nextID   int
+	searches map[int]Search
+}
+
+// NewMemorySearchRepository creates an empty in-memory search repository
+func NewMemorySearchRepository() *MemorySearchRepository {
+	return &MemorySearchRepository{searches: make(map[int]Search)}
+}
+
+// CreateSearch implements SearchRepository
+func (r *MemorySearchRepository) CreateSearch(ctx context.Context, search *Search) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	search.ID = r.nextID
+	search.CreatedAt = time.Now()
+	search.UpdatedAt = search.CreatedAt
+	r.searches[search.ID] = *search
+	return nil
+}
+
+// GetSearch implements SearchRepository
+func (r *MemorySearchRepository) GetSearch(ctx context.Context, id int) (*Search, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	search, ok := r.searches[id]
+	if !ok {
+		return nil, nil
+	}
+	return &search, nil
+}
+
+// ListSearches implements SearchRepository
+func (r *MemorySearchRepository) ListSearches(ctx context.Context, userID string, limit int) ([]Search, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	searches := []Search{}
+	for _, search := range r.searches {
+		if search.UserID == userID {
+			searches = append(searches, search)
+		}
+	}
+	// IDs follow creation order
+	sort.Slice(searches, func(i, j int) bool { return searches[i].ID > searches[j].ID })
+	if limit > 0 && len(searches) > limit {
+		searches = searches[:limit]
+	}
+	return searches, nil
+}
+
+// MemoryRecommendationRepository keeps recommendations in process memory
+type MemoryRecommendationRepository struct {
+	mu       sync.RWMutex
+	nextID   int
+	bySearch map[int][]models.TravelRecommendation
+}
+
+// NewMemoryRecommendationRepository creates an empty in-memory recommendation repository
+func NewMemoryRecommendationRepository() *MemoryRecommendationRepository {
+	return &MemoryRecommendationRepository{bySearch: make(map[int][]models.TravelRecommendation)}
+}
+
+// SaveRecommendations implements RecommendationRepository
+func (r *MemoryRecommendationRepository) SaveRecommendations(ctx context.Context, searchID int, recommendations []models.TravelRecommendation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range recommendations {
+		r.nextID++
+		recommendations[i].ID = r.nextID
+		r.bySearch[searchID] = append(r.bySearch[searchID], recommendations[i])
+	}
+	return nil
+}
+
+// ListRecommendations implements RecommendationRepository
+func (r *MemoryRecommendationRepository) ListRecommendations(ctx context.Context, searchID int) ([]models.TravelRecommendation, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]models.TravelRecommendation{}, r.bySearch[searchID]...), nil
+}
+
+// MemoryTripRepository keeps trips in process memory
+type MemoryTripRepository struct {
+	mu     sync.RWMutex
+	nextID int64
+	trips  map[int64]agents.Trip
+}
+
+// NewMemoryTripRepository creates an empty in-memory trip repository
+func NewMemoryTripRepository() *MemoryTripRepository {
+	return &MemoryTripRepository{trips: make(map[int64]agents.Trip)}
+}
+
+// CreateTrip implements TripRepository
+func (r *MemoryTripRepository) CreateTrip(ctx context.Context, trip *agents.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	trip.ID = r.nextID
+	trip.CreatedAt = time.Now()
+	trip.UpdatedAt = trip.CreatedAt
+	r.trips[trip.ID] = copyTrip(*trip)
+	return nil
+}
+
+// GetTrip implements TripRepository
+func (r *MemoryTripRepository) GetTrip(ctx context.Context, id int64) (*agents.Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	trip, ok := r.trips[id]
+	if !ok {
+		return nil, nil
+	}
+	trip = copyTrip(trip)
+	return &trip, nil
+}
+
+// ListTrips implements TripRepository
+func (r *MemoryTripRepository) ListTrips(ctx context.Context, userID string) ([]agents.Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	trips := []agents.Trip{}
+	for _, trip := range r.trips {
+		if trip.UserID == userID {
+			trips = append(trips, copyTrip(trip))
+		}
+	}
+	sort.Slice(trips, func(i, j int) bool {
+		if !trips[i].UpdatedAt.Equal(trips[j].UpdatedAt) {
+			return trips[i].UpdatedAt.After(trips[j].UpdatedAt)
+		}
+		return trips[i].ID > trips[j].ID
+	})
+	return trips, nil
+}
+
+// UpdateTrip implements TripRepository
+func (r *MemoryTripRepository) UpdateTrip(ctx context.Context, trip *agents.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.trips[trip.ID]
+	if !ok {
+		return fmt.Errorf("trip %d not found", trip.ID)
+	}
+	trip.UserID = existing.UserID
+	trip.CreatedAt = existing.CreatedAt
+	trip.UpdatedAt = time.Now()
+	r.trips[trip.ID] = copyTrip(*trip)
+	return nil
+}
+
+// DeleteTrip implements TripRepository
+func (r *MemoryTripRepository) DeleteTrip(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.trips, id)
+	return nil
+}
+
+// copyTrip copies a trip's itinerary so stored trips cannot be changed through returned values
+func copyTrip(trip agents.Trip) agents.Trip {
+	days := make([]agents.TripDay, len(trip.Days))
+	for i, day := range trip.Days {
+		days[i] = day
+		days[i].Activities = append([]agents.TripActivity{}, day.Activities...)
+	}
+	trip.Days = days
+	return trip
+}
+
+// MemoryExpenseRepository keeps expenses, budgets and members in process memory
+type MemoryExpenseRepository struct {
+	mu           sync.RWMutex
+	nextID       int64
+	nextMemberID int64
+	expenses     map[int64]agents.Expense
+	budgets      map[int64]agents.TripBudget
+	members      []agents.TripMember
+}
+
+// NewMemoryExpenseRepository creates an empty in-memory expense repository
+func NewMemoryExpenseRepository() *MemoryExpenseRepository {
+	return &MemoryExpenseRepository{
+		expenses: make(map[int64]agents.Expense),
+		budgets:  make(map[int64]agents.TripBudget),
+	}
+}
+
+// CreateExpense implements ExpenseRepository
+func (r *MemoryExpenseRepository) CreateExpense(ctx context.Context, expense *agents.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	expense.ID = r.nextID
+	expense.CreatedAt = time.Now()
+	expense.UpdatedAt = expense.CreatedAt
+	r.expenses[expense.ID] = *expense
+	return nil
+}
+
+// GetExpense implements ExpenseRepository
+func (r *MemoryExpenseRepository) GetExpense(ctx context.Context, tripID, id int64) (*agents.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	expense, ok := r.expenses[id]
+	if !ok || expense.TripID != tripID {
+		return nil, nil
+	}
+	return &expense, nil
+}
+
+// ListExpenses implements ExpenseRepository
+func (r *MemoryExpenseRepository) ListExpenses(ctx context.Context, tripID int64) ([]agents.Expense, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	expenses := []agents.Expense{}
+	for _, expense := range r.expenses {
+		if expense.TripID == tripID {
+			expenses = append(expenses, expense)
+		}
+	}
+	sort.Slice(expenses, func(i, j int) bool {
+		if !expenses[i].Date.Equal(expenses[j].Date) {
+			return expenses[i].Date.Before(expenses[j].Date)
+		}
+		return expenses[i].ID < expenses[j].ID
+	})
+	return expenses, nil
+}
+
+// UpdateExpense implements ExpenseRepository
+func (r *MemoryExpenseRepository) UpdateExpense(ctx context.Context, expense *agents.Expense) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.expenses[expense.ID]
+	if !ok || existing.TripID != expense.TripID {
+		return fmt.Errorf("expense %d not found", expense.ID)
+	}
+	expense.CreatedAt = existing.CreatedAt
+	expense.UpdatedAt = time.Now()
+	r.expenses[expense.ID] = *expense
+	return nil
+}
+
+// DeleteExpense implements ExpenseRepository
+func (r *MemoryExpenseRepository) DeleteExpense(ctx context.Context, tripID, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if expense, ok := r.expenses[id]; ok && expense.TripID == tripID {
+		delete(r.expenses, id)
+	}
+	return nil
+}
+
+// SetBudget implements ExpenseRepository
+func (r *MemoryExpenseRepository) SetBudget(ctx context.Context, budget *agents.TripBudget) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	budget.UpdatedAt = time.Now()
+	r.budgets[budget.TripID] = *budget
+	return nil
+}
+
+// GetBudget implements ExpenseRepository
+func (r *MemoryExpenseRepository) GetBudget(ctx context.Context, tripID int64) (*agents.TripBudget, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	budget, ok := r.budgets[tripID]
+	if !ok {
+		return nil, nil
+	}
+	return &budget, nil
+}
+
+// AddMember implements ExpenseRepository
+func (r *MemoryExpenseRepository) AddMember(ctx context.Context, member *agents.TripMember) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.members {
+		if existing.TripID == member.TripID && existing.Name == member.Name {
+			return fmt.Errorf("trip %d already has a member named %s", member.TripID, member.Name)
+		}
+	}
+	r.nextMemberID++
+	member.ID = r.nextMemberID
+	member.CreatedAt = time.Now()
+	r.members = append(r.members, *member)
+	return nil
+}
+
+// ListMembers implements ExpenseRepository
+func (r *MemoryExpenseRepository) ListMembers(ctx context.Context, tripID int64) ([]agents.TripMember, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := []agents.TripMember{}
+	for _, member := range r.members {
+		if member.TripID == tripID {
+			members = append(members, member)
+		}
+	}
+	return members, nil
+}
+
+// DeleteMember implements ExpenseRepository
+func (r *MemoryExpenseRepository) DeleteMember(ctx context.Context, tripID, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	members := r.members[:0]
+	for _, member := range r.members {
+		if member.TripID != tripID || member.ID != id {
+			members = append(members, member)
+		}
+	}
+	r.members = members
+	return nil
+}
@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemorySearchRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemorySearchRepository()
+
+	for _, destination := range []string{"Tokyo", "Osaka", "Kyoto"} {
+		search := Search{UserID: "user-1", Destination: destination, Summary: "Visit " + destination}
+		require.NoError(t, repo.CreateSearch(ctx, &search))
+		assert.NotZero(t, search.ID)
+		assert.False(t, search.CreatedAt.IsZero())
+	}
+	anonymous := Search{Destination: "Paris"}
+	require.NoError(t, repo.CreateSearch(ctx, &anonymous))
+
+	searches, err := repo.ListSearches(ctx, "user-1", 2)
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, "Kyoto", searches[0].Destination, "Newest first")
+	assert.Equal(t, "Osaka", searches[1].Destination)
+
+	got, err := repo.GetSearch(ctx, anonymous.ID)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, "Paris", got.Destination)
+	assert.Empty(t, got.UserID)
+
+	got, err = repo.GetSearch(ctx, 99)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestMemoryRecommendationRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRecommendationRepository()
+
+	recommendations := []models.TravelRecommendation{
+		{Type: "hotel", Title: "Hotel Gracery"},
+		{Type: "attraction", Title: "Senso-ji"},
+	}
+	require.NoError(t, repo.SaveRecommendations(ctx, 1, recommendations))
+	assert.NotZero(t, recommendations[0].ID)
+	assert.NotEqual(t, recommendations[0].ID, recommendations[1].ID)
+
+	stored, err := repo.ListRecommendations(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, recommendations, stored)
+
+	stored, err = repo.ListRecommendations(ctx, 2)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestMemoryTripRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryTripRepository()
+
+	first := agents.Trip{UserID: "user-1", Destination: "Tokyo", Days: []agents.TripDay{{Day: 1, Activities: []agents.TripActivity{{Position: 1, Description: "Shibuya"}}}}}
+	require.NoError(t, repo.CreateTrip(ctx, &first))
+	second := agents.Trip{UserID: "user-1", Destination: "Osaka"}
+	require.NoError(t, repo.CreateTrip(ctx, &second))
+	other := agents.Trip{UserID: "user-2", Destination: "Paris"}
+	require.NoError(t, repo.CreateTrip(ctx, &other))
+
+	trips, err := repo.ListTrips(ctx, "user-1")
+	require.NoError(t, err)
+	require.Len(t, trips, 2)
+	assert.Equal(t, second.ID, trips[0].ID, "Most recently updated first")
+
+	// Returned trips are copies
+	trips[1].Days[0].Activities[0].Description = "changed"
+	got, err := repo.GetTrip(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Shibuya", got.Days[0].Activities[0].Description)
+
+	update := agents.Trip{ID: first.ID, UserID: "user-2", Destination: "Kyoto"}
+	require.NoError(t, repo.UpdateTrip(ctx, &update))
+	assert.Equal(t, "user-1", update.UserID, "Updates keep the owner")
+	got, err = repo.GetTrip(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Kyoto", got.Destination)
+	assert.Empty(t, got.Days)
+
+	assert.Error(t, repo.UpdateTrip(ctx, &agents.Trip{ID: 99}))
+
+	require.NoError(t, repo.DeleteTrip(ctx, first.ID))
+	got, err = repo.GetTrip(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+// newExpense returns an expense of trip 1 in THB
+func newExpense(category string, amount float64, day string) agents.Expense {
+	date, _ := time.Parse("2006-01-02", day)
+	return agents.Expense{TripID: 1, Category: category, Amount: amount, Currency: "THB", AmountTHB: amount, Date: date}
+}
+
+func TestMemoryExpenseRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryExpenseRepository()
+
+	later := newExpense(agents.ExpenseFood, 300, "2025-05-02")
+	earlier := newExpense(agents.ExpenseHotel, 1200, "2025-05-01")
+	other := newExpense(agents.ExpenseFood, 100, "2025-05-01")
+	other.TripID = 2
+	for _, expense := range []*agents.Expense{&later, &earlier, &other} {
+		require.NoError(t, repo.CreateExpense(ctx, expense))
+	}
+
+	expenses, err := repo.ListExpenses(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, expenses, 2)
+	assert.Equal(t, earlier.ID, expenses[0].ID, "Expenses are listed by date")
+
+	found, err := repo.GetExpense(ctx, 2, later.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "Expenses of other trips are not found")
+
+	later.Amount, later.AmountTHB = 450, 450
+	require.NoError(t, repo.UpdateExpense(ctx, &later))
+	found, err = repo.GetExpense(ctx, 1, later.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 450.0, found.Amount)
+
+	require.NoError(t, repo.DeleteExpense(ctx, 1, later.ID))
+	expenses, err = repo.ListExpenses(ctx, 1)
+	require.NoError(t, err)
+	assert.Len(t, expenses, 1)
+
+	budget, err := repo.GetBudget(ctx, 1)
+	require.NoError(t, err)
+	assert.Nil(t, budget)
+	require.NoError(t, repo.SetBudget(ctx, &agents.TripBudget{TripID: 1, Total: 5000}))
+	budget, err = repo.GetBudget(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 5000.0, budget.Total)
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// searchResults is what travel_searches.results holds
+type searchResults struct {
+	// Summary is the AI-written overview, stored under its original key
+	Summary string              `json:"recommendations"`
+	Weather *models.WeatherInfo `json:"weather,omitempty"`
+}
+
+// recommendationMetadata is what travel_recommendations.metadata holds
+type recommendationMetadata struct {
+	Location string                 `json:"location,omitempty"`
+	ImageURL string                 `json:"imageUrl,omitempty"`
+	URL      string                 `json:"url,omitempty"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// PostgresSearchRepository keeps searches in the travel_searches table
+type PostgresSearchRepository struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresSearchRepository creates a Postgres-backed search repository
+func NewPostgresSearchRepository(db *database.PostgresDB) *PostgresSearchRepository {
+	return &PostgresSearchRepository{db: db}
+}
+
+const searchColumns = `id, COALESCE(user_id, ''), destination, start_date, end_date, COALESCE(budget, 0),
+	preferences, results, created_at, updated_at`
+
+// CreateSearch implements SearchRepository
+func (r *PostgresSearchRepository) CreateSearch(ctx context.Context, search *Search) error {
+	preferencesJSON, _ := json.Marshal(search.Preferences)
+	resultsJSON, _ := json.Marshal(searchResults{Summary: search.Summary, Weather: search.Weather})
+
+	query := `
+		INSERT INTO travel_searches (user_id, destination, start_date, end_date, budget, preferences, results)
+		VALUES (NULLIF($1, ''), $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.DB.QueryRowContext(ctx,
+		query,
+		search.UserID,
+		search.Destination,
+		search.StartDate,
+		search.EndDate,
+		search.Budget,
+		preferencesJSON,
+		resultsJSON,
+	).Scan(&search.ID, &search.CreatedAt, &search.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to store search: %w", err)
+	}
+	return nil
+}
+
+// GetSearch implements SearchRepository
+func (r *PostgresSearchRepository) GetSearch(ctx context.Context, id int) (*Search, error) {
+	row := r.db.DB.QueryRowContext(ctx, "SELECT "+searchColumns+" FROM travel_searches WHERE id = $1", id)
+	search, err := scanSearch(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search: %w", err)
+	}
+	return search, nil
+}
+
+// ListSearches implements SearchRepository
+func (r *PostgresSearchRepository) ListSearches(ctx context.Context, userID string, limit int) ([]Search, error) {
+	query := "SELECT " + searchColumns + " FROM travel_searches WHERE user_id = $1 ORDER BY created_at DESC, id DESC"
+	args := []interface{}{userID}
+	if limit > 0 {
+		query += " LIMIT $2"
+		args = append(args, limit)
+	}
+
+	rows, err := r.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list searches: %w", err)
+	}
+	defer rows.Close()
+
+	searches := []Search{}
+	for rows.Next() {
+		search, err := scanSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan search: %w", err)
+		}
+		searches = append(searches, *search)
+	}
+	return searches, rows.Err()
+}
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSearch reads a row selected with searchColumns
+func scanSearch(row rowScanner) (*Search, error) {
+	var search Search
+	var startDate, endDate sql.NullTime
+	var preferencesJSON, resultsJSON []byte
+	err := row.Scan(
+		&search.ID,
+		&search.UserID,
+		&search.Destination,
+		&startDate,
+		&endDate,
+		&search.Budget,
+		&preferencesJSON,
+		&resultsJSON,
+		&search.CreatedAt,
+		&search.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if startDate.Valid {
+		search.StartDate = &startDate.Time
+	}
+	if endDate.Valid {
+		search.EndDate = &endDate.Time
+	}
+
+	// Older rows may hold results in another shape; they just have no summary
+	if len(preferencesJSON) > 0 {
+		json.Unmarshal(preferencesJSON, &search.Preferences)
+	}
+	var results searchResults
+	if len(resultsJSON) > 0 {
+		json.Unmarshal(resultsJSON, &results)
+	}
+	search.Summary = results.Summary
+	search.Weather = results.Weather
+	return &search, nil
+}
+
+// PostgresRecommendationRepository keeps recommendations in the travel_recommendations table
+type PostgresRecommendationRepository struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresRecommendationRepository creates a Postgres-backed recommendation repository
+func NewPostgresRecommendationRepository(db *database.PostgresDB) *PostgresRecommendationRepository {
+	return &PostgresRecommendationRepository{db: db}
+}
+
+// SaveRecommendations implements RecommendationRepository; either all recommendations are stored
+// or none are
+func (r *PostgresRecommendationRepository) SaveRecommendations(ctx context.Context, searchID int, recommendations []models.TravelRecommendation) error {
+	tx, err := r.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO travel_recommendations (search_id, recommendation_type, title, description, price, rating, metadata)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+		RETURNING id
+	`
+	ids := make([]int, len(recommendations))
+	for i, rec := range recommendations {
+		metadataJSON, _ := json.Marshal(recommendationMetadata{
+			Location: rec.Location,
+			ImageURL: rec.ImageURL,
+			URL:      rec.URL,
+			Details:  rec.Metadata,
+		})
+		err := tx.QueryRowContext(ctx,
+			query,
+			searchID,
+			rec.Type,
+			rec.Title,
+			rec.Description,
+			rec.Price,
+			rec.Rating,
+			metadataJSON,
+		).Scan(&ids[i])
+		if err != nil {
+			return fmt.Errorf("failed to store recommendation: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	for i := range recommendations {
+		recommendations[i].ID = ids[i]
+	}
+	return nil
+}
+
+// ListRecommendations implements RecommendationRepository
+func (r *PostgresRecommendationRepository) ListRecommendations(ctx context.Context, searchID int) ([]models.TravelRecommendation, error) {
+	query := `
+		SELECT id, recommendation_type, title, COALESCE(description, ''), COALESCE(price, 0),
+		       COALESCE(rating, 0), metadata
+		FROM travel_recommendations
+		WHERE search_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.DB.QueryContext(ctx, query, searchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recommendations: %w", err)
+	}
+	defer rows.Close()
+
+	recommendations := []models.TravelRecommendation{}
+	for rows.Next() {
+		var rec models.TravelRecommendation
+		var metadataJSON []byte
+		if err := rows.Scan(&rec.ID, &rec.Type, &rec.Title, &rec.Description, &rec.Price, &rec.Rating, &metadataJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan recommendation: %w", err)
+		}
+		var metadata recommendationMetadata
+		if len(metadataJSON) > 0 {
+			json.Unmarshal(metadataJSON, &metadata)
+		}
+		rec.Location = metadata.Location
+		rec.ImageURL = metadata.ImageURL
+		rec.URL = metadata.URL
+		rec.Metadata = metadata.Details
+		recommendations = append(recommendations, rec)
+	}
+	return recommendations, rows.Err()
+}
@@ -0,0 +1,103 @@
+// Package storage defines the repositories the API keeps travel searches, their recommendations,
+// saved trips and their expense ledgers in, with Postgres and in-memory implementations.
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/models"
+)
+
+// Search is a stored travel search. Its recommendations are kept by a RecommendationRepository.
+type Search struct {
+	ID          int
+	UserID      string
+	Destination string
+	StartDate   *time.Time
+	EndDate     *time.Time
+	Budget      float64
+	Preferences map[string]interface{}
+	Summary     string
+	Weather     *models.WeatherInfo
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SearchRepository persists travel searches
+type SearchRepository interface {
+	// CreateSearch stores a search, filling in its ID and timestamps
+	CreateSearch(ctx context.Context, search *Search) error
+	// GetSearch returns the search with the given ID, or nil if there is none
+	GetSearch(ctx context.Context, id int) (*Search, error)
+	// ListSearches returns a user's most recent searches, newest first
+	ListSearches(ctx context.Context, userID string, limit int) ([]Search, error)
+}
+
+// RecommendationRepository persists the recommendations of travel searches
+type RecommendationRepository interface {
+	// SaveRecommendations stores recommendations for a search, filling in their IDs
+	SaveRecommendations(ctx context.Context, searchID int, recommendations []models.TravelRecommendation) error
+	// ListRecommendations returns a search's recommendations in the order they were saved
+	ListRecommendations(ctx context.Context, searchID int) ([]models.TravelRecommendation, error)
+}
+
+// TripRepository persists saved trips
+type TripRepository interface {
+	CreateTrip(ctx context.Context, trip *agents.Trip) error
+	// GetTrip returns the trip with the given ID, or nil if there is none
+	GetTrip(ctx context.Context, id int64) (*agents.Trip, error)
+	// ListTrips returns a user's trips, most recently updated first
+	ListTrips(ctx context.Context, userID string) ([]agents.Trip, error)
+	// UpdateTrip replaces a trip's details and itinerary
+	UpdateTrip(ctx context.Context, trip *agents.Trip) error
+	DeleteTrip(ctx context.Context, id int64) error
+}
+
+// ExpenseRepository persists trip expenses, the budgets they are tracked against and the members
+// of group trips who share them
+type ExpenseRepository interface {
+	CreateExpense(ctx context.Context, expense *agents.Expense) error
+	// GetExpense returns the expense of a trip with the given ID, or nil if there is none
+	GetExpense(ctx context.Context, tripID, id int64) (*agents.Expense, error)
+	// ListExpenses returns a trip's expenses by date
+	ListExpenses(ctx context.Context, tripID int64) ([]agents.Expense, error)
+	UpdateExpense(ctx context.Context, expense *agents.Expense) error
+	DeleteExpense(ctx context.Context, tripID, id int64) error
+	SetBudget(ctx context.Context, budget *agents.TripBudget) error
+	// GetBudget returns the budget of a trip, or nil if none was set
+	GetBudget(ctx context.Context, tripID int64) (*agents.TripBudget, error)
+	AddMember(ctx context.Context, member *agents.TripMember) error
+	ListMembers(ctx context.Context, tripID int64) ([]agents.TripMember, error)
+	DeleteMember(ctx context.Context, tripID, id int64) error
+}
+
+// Repositories groups the repositories of one backend
+type Repositories struct {
+	Searches        SearchRepository
+	Recommendations RecommendationRepository
+	Trips           TripRepository
+	Expenses        ExpenseRepository
+}
+
+// NewPostgresRepositories creates repositories backed by Postgres
+func NewPostgresRepositories(db *database.PostgresDB) Repositories {
+	return Repositories{
+		Searches:        NewPostgresSearchRepository(db),
+		Recommendations: NewPostgresRecommendationRepository(db),
+		Trips:           NewPostgresTripRepository(db),
+		Expenses:        NewPostgresExpenseRepository(db),
+	}
+}
+
+// NewMemoryRepositories creates empty repositories kept in process memory
+func NewMemoryRepositories() Repositories {
+	return Repositories{
+		Searches:        NewMemorySearchRepository(),
+		Recommendations: NewMemoryRecommendationRepository(),
+		Trips:           NewMemoryTripRepository(),
+		Expenses:        NewMemoryExpenseRepository(),
+	}
+}
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/smithisrealdev/travel-ai-agent/backend/agents"
+	"github.com/smithisrealdev/travel-ai-agent/backend/internal/database"
+)
+
+// PostgresTripRepository keeps saved trips in the trips table, with their itinerary as rows of
+// trip_days and trip_activities
+type PostgresTripRepository struct {
+	db *database.PostgresDB
+}
+
+// NewPostgresTripRepository creates a Postgres-backed trip repository
+func NewPostgresTripRepository(db *database.PostgresDB) *PostgresTripRepository {
+	return &PostgresTripRepository{db: db}
+}
+
+const tripColumns = `id, user_id, title, destination, start_date, duration_days, budget, COALESCE(summary, ''),
+	created_at, updated_at`
+
+// CreateTrip implements TripRepository
+func (s *PostgresTripRepository) CreateTrip(ctx context.Context, trip *agents.Trip) error {
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO trips (user_id, title, destination, start_date, duration_days, budget, summary)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		trip.UserID,
+		trip.Title,
+		trip.Destination,
+		trip.StartDate,
+		trip.Duration,
+		trip.Budget,
+		trip.Summary,
+	).Scan(&trip.ID, &trip.CreatedAt, &trip.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create trip: %w", err)
+	}
+
+	if err := insertTripDays(ctx, tx, trip); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetTrip implements TripRepository
+func (s *PostgresTripRepository) GetTrip(ctx context.Context, id int64) (*agents.Trip, error) {
+	trips, err := s.queryTrips(ctx, "SELECT "+tripColumns+" FROM trips WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(trips) == 0 {
+		return nil, nil
+	}
+	return &trips[0], nil
+}
+
+// ListTrips implements TripRepository
+func (s *PostgresTripRepository) ListTrips(ctx context.Context, userID string) ([]agents.Trip, error) {
+	return s.queryTrips(ctx, "SELECT "+tripColumns+" FROM trips WHERE user_id = $1 ORDER BY updated_at DESC, id DESC", userID)
+}
+
+// UpdateTrip implements TripRepository
+func (s *PostgresTripRepository) UpdateTrip(ctx context.Context, trip *agents.Trip) error {
+	tx, err := s.db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE trips
+		SET title = $2, destination = $3, start_date = $4, duration_days = $5, budget = $6,
+			summary = NULLIF($7, ''), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING user_id, created_at, updated_at
+	`
+
+	err = tx.QueryRowContext(ctx, query,
+		trip.ID,
+		trip.Title,
+		trip.Destination,
+		trip.StartDate,
+		trip.Duration,
+		trip.Budget,
+		trip.Summary,
+	).Scan(&trip.UserID, &trip.CreatedAt, &trip.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("trip %d not found", trip.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to update trip: %w", err)
+	}
+
+	// Activities go with their days
+	if _, err := tx.ExecContext(ctx, "DELETE FROM trip_days WHERE trip_id = $1", trip.ID); err != nil {
+		return fmt.Errorf("failed to replace trip itinerary: %w", err)
+	}
+	if err := insertTripDays(ctx, tx, trip); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteTrip implements TripRepository. The trip's itinerary, budget, expenses and members are
+// deleted with it by their foreign keys.
+func (s *PostgresTripRepository) DeleteTrip(ctx context.Context, id int64) error {
+	if _, err := s.db.DB.ExecContext(ctx, "DELETE FROM trips WHERE id = $1", id); err != nil {
+		return fmt.Errorf("failed to delete trip: %w", err)
+	}
+	return nil
+}
+
+// queryTrips runs a query selecting tripColumns and loads the itineraries of the trips found
+func (s *PostgresTripRepository) queryTrips(ctx context.Context, query string, args ...interface{}) ([]agents.Trip, error) {
+	rows, err := s.db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trips: %w", err)
+	}
+	defer rows.Close()
+
+	trips := []agents.Trip{}
+	index := make(map[int64]int)
+	for rows.Next() {
+		var trip agents.Trip
+		var startDate sql.NullTime
+		err := rows.Scan(
+			&trip.ID,
+			&trip.UserID,
+			&trip.Title,
+			&trip.Destination,
+			&startDate,
+			&trip.Duration,
+			&trip.Budget,
+			&trip.Summary,
+			&trip.CreatedAt,
+			&trip.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trip: %w", err)
+		}
+		if startDate.Valid {
+			trip.StartDate = &startDate.Time
+		}
+		trip.Days = []agents.TripDay{}
+		index[trip.ID] = len(trips)
+		trips = append(trips, trip)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(trips) == 0 {
+		return trips, nil
+	}
+
+	ids := make([]int64, 0, len(trips))
+	for _, trip := range trips {
+		ids = append(ids, trip.ID)
+	}
+	if err := s.loadDays(ctx, trips, index, ids); err != nil {
+		return nil, err
+	}
+	return trips, nil
+}
+
+// loadDays fills in the days and activities of the given trips with one query
+func (s *PostgresTripRepository) loadDays(ctx context.Context, trips []agents.Trip, index map[int64]int, ids []int64) error {
+	query := `
+		SELECT d.trip_id, d.day, COALESCE(d.budget, 0), a.position, a.description
+		FROM trip_days d
+		LEFT JOIN trip_activities a ON a.trip_day_id = d.id
+		WHERE d.trip_id = ANY($1)
+		ORDER BY d.trip_id, d.day, a.position
+	`
+
+	rows, err := s.db.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return fmt.Errorf("failed to query trip days: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tripID int64
+		var day agents.TripDay
+		var position sql.NullInt64
+		var description sql.NullString
+		if err := rows.Scan(&tripID, &day.Day, &day.Budget, &position, &description); err != nil {
+			return fmt.Errorf("failed to scan trip day: %w", err)
+		}
+
+		trip := &trips[index[tripID]]
+		if n := len(trip.Days); n == 0 || trip.Days[n-1].Day != day.Day {
+			day.Activities = []agents.TripActivity{}
+			trip.Days = append(trip.Days, day)
+		}
+		if position.Valid {
+			last := &trip.Days[len(trip.Days)-1]
+			last.Activities = append(last.Activities, agents.TripActivity{
+				Position:    int(position.Int64),
+				Description: description.String,
+			})
+		}
+	}
+	return rows.Err()
+}
+
+// insertTripDays writes a trip's days and their activities
+func insertTripDays(ctx context.Context, tx *sql.Tx, trip *agents.Trip) error {
+	for _, day := range trip.Days {
+		var dayID int64
+		err := tx.QueryRowContext(ctx,
+			"INSERT INTO trip_days (trip_id, day, budget) VALUES ($1, $2, $3) RETURNING id",
+			trip.ID, day.Day, day.Budget,
+		).Scan(&dayID)
+		if err != nil {
+			return fmt.Errorf("failed to save trip day %d: %w", day.Day, err)
+		}
+
+		for _, activity := range day.Activities {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO trip_activities (trip_day_id, position, description) VALUES ($1, $2, $3)",
+				dayID, activity.Position, activity.Description,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to save activity of trip day %d: %w", day.Day, err)
+			}
+		}
+	}
+	return nil
+}